@@ -0,0 +1,182 @@
+// MIT License
+
+// Copyright (c) 2022 Project-Sparrow
+// Copyright (c) 2023 Kelwing <kelwing@kelnet.org>
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package snowflake
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+)
+
+// WorkerIDAllocator hands out worker IDs that no other instance holds
+// at the same time, such as the leases of the redisalloc and etcdalloc
+// packages or FileAllocator.
+//
+// An allocator whose claims expire can lose one to another instance, after
+// which a Generator still using the worker ID may generate Snowflakes that
+// clash with that instance's. WithAllocator does not watch for this, so such
+// allocators should offer a way to stop the Generator when it happens.
+type WorkerIDAllocator interface {
+	// Acquire claims a free worker ID.
+	Acquire(ctx context.Context) (uint8, error)
+
+	// Renew checks or extends the claim on the worker ID,
+	// for allocators whose claims expire.
+	Renew(ctx context.Context) error
+
+	// Release gives up the claim on the worker ID.
+	Release() error
+}
+
+// WithAllocator makes New acquire the worker ID from a instead of taking it
+// from WithWorkerID, and Close release it.
+// The worker ID must fit in the worker bits of the Generator's Layout.
+func WithAllocator(a WorkerIDAllocator) Option {
+	return func(g *Generator) error {
+		if err := g.once("worker ID"); err != nil {
+			return err
+		}
+
+		if a == nil {
+			return errors.New("worker ID allocator is nil")
+		}
+
+		g.allocator = a
+
+		return nil
+	}
+}
+
+// acquire takes the worker ID from the allocator set by WithAllocator, if any.
+func (g *Generator) acquire() error {
+	if g.allocator == nil {
+		return nil
+	}
+
+	id, err := g.allocator.Acquire(context.Background())
+	if err != nil {
+		return fmt.Errorf("acquiring worker ID: %w", err)
+	}
+
+	if uint16(id) > g.layout.MaxWorkerID() {
+		return errors.Join(
+			fmt.Errorf("allocated worker ID %d exceeds maximum %d", id, g.layout.MaxWorkerID()),
+			g.allocator.Release(),
+		)
+	}
+
+	g.workerID = uint16(id)
+	for _, sh := range g.shards {
+		sh.workerID = g.workerID
+	}
+
+	_, err = g.onClose(g.allocator.Release)
+	return err
+}
+
+// FileAllocator is a WorkerIDAllocator for processes on one host, through
+// lock files named like worker-3.lock in a shared directory. The claim lasts
+// until Release is called or the process exits, even if it crashes: the lock
+// is held by the operating system, so a lock file left behind by a dead
+// process is taken over.
+type FileAllocator struct {
+	dir  string
+	bits uint8
+
+	mtx  sync.Mutex
+	id   int
+	file *os.File
+}
+
+// NewFileAllocator creates a FileAllocator claiming worker IDs of the given
+// bit width through lock files in dir, which is created if needed.
+func NewFileAllocator(dir string, bits uint8) (*FileAllocator, error) {
+	if err := checkIDBits("worker", bits); err != nil {
+		return nil, err
+	}
+
+	return &FileAllocator{dir: dir, bits: bits}, nil
+}
+
+// Acquire claims the lowest worker ID not claimed by another FileAllocator
+// sharing the directory. It returns an error wrapping ErrNoFreeID if every
+// worker ID is claimed.
+func (a *FileAllocator) Acquire(ctx context.Context) (uint8, error) {
+	a.mtx.Lock()
+	defer a.mtx.Unlock()
+
+	if a.file != nil {
+		return 0, fmt.Errorf("worker ID %d is already acquired", a.id)
+	}
+
+	id, f, err := claimLock(a.dir, "worker", a.bits)
+	if err != nil {
+		return 0, err
+	}
+
+	a.id, a.file = id, f
+
+	return uint8(id), nil
+}
+
+// Renew checks that the lock file of the worker ID was not removed,
+// which would let another process claim it.
+func (a *FileAllocator) Renew(ctx context.Context) error {
+	a.mtx.Lock()
+	defer a.mtx.Unlock()
+
+	if a.file == nil {
+		return errors.New("no worker ID acquired")
+	}
+
+	held, err := a.file.Stat()
+	if err != nil {
+		return err
+	}
+
+	current, err := os.Stat(a.file.Name())
+	if err != nil || !os.SameFile(held, current) {
+		return fmt.Errorf("lock file of worker ID %d was removed", a.id)
+	}
+
+	return nil
+}
+
+// Release removes the lock file, freeing the worker ID.
+// It does nothing if no worker ID is held.
+func (a *FileAllocator) Release() error {
+	a.mtx.Lock()
+	defer a.mtx.Unlock()
+
+	if a.file == nil {
+		return nil
+	}
+
+	err := unlockFile(a.file)
+	a.file = nil
+
+	return err
+}
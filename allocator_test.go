@@ -0,0 +1,318 @@
+// MIT License
+
+// Copyright (c) 2022 Project-Sparrow
+// Copyright (c) 2023 Kelwing <kelwing@kelnet.org>
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package snowflake_test
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"sync"
+	"testing"
+	"time"
+
+	"wumpgo.dev/snowflake"
+)
+
+func newFileAllocator(t *testing.T, dir string, bits uint8) *snowflake.FileAllocator {
+	t.Helper()
+
+	a, err := snowflake.NewFileAllocator(dir, bits)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { a.Release() })
+
+	return a
+}
+
+func TestFileAllocator(t *testing.T) {
+	dir := t.TempDir()
+	ctx := context.Background()
+
+	const goroutines = 12
+
+	ids := make(chan uint8, goroutines)
+	errs := make(chan error, goroutines)
+	allocs := make([]*snowflake.FileAllocator, goroutines)
+
+	var wg sync.WaitGroup
+	for i := range allocs {
+		allocs[i] = newFileAllocator(t, dir, 2)
+
+		wg.Add(1)
+		go func(a *snowflake.FileAllocator) {
+			defer wg.Done()
+			id, err := a.Acquire(ctx)
+			if err != nil {
+				errs <- err
+				return
+			}
+			ids <- id
+		}(allocs[i])
+	}
+	wg.Wait()
+	close(ids)
+	close(errs)
+
+	seen := make(map[uint8]bool)
+	for id := range ids {
+		if seen[id] {
+			t.Errorf("worker ID %d acquired twice", id)
+		}
+		seen[id] = true
+	}
+
+	if len(seen) != 4 {
+		t.Errorf("%d worker IDs acquired, want 4", len(seen))
+	}
+
+	for err := range errs {
+		if !errors.Is(err, snowflake.ErrNoFreeID) {
+			t.Errorf("Acquire() = %v, want ErrNoFreeID", err)
+		}
+	}
+
+	for _, a := range allocs {
+		if err := a.Release(); err != nil {
+			t.Errorf("Release() = %v", err)
+		}
+	}
+
+	a := newFileAllocator(t, dir, 2)
+	if id, err := a.Acquire(ctx); err != nil || id != 0 {
+		t.Errorf("Acquire() after release = %d, %v, want 0", id, err)
+	}
+
+	if _, err := a.Acquire(ctx); err == nil {
+		t.Error("second Acquire succeeded, want error")
+	}
+}
+
+func TestFileAllocatorRenew(t *testing.T) {
+	dir := t.TempDir()
+	ctx := context.Background()
+
+	a := newFileAllocator(t, dir, 5)
+	if err := a.Renew(ctx); err == nil {
+		t.Error("Renew() before Acquire succeeded, want error")
+	}
+
+	if _, err := a.Acquire(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := a.Renew(ctx); err != nil {
+		t.Errorf("Renew() = %v", err)
+	}
+
+	if err := os.Remove(filepath.Join(dir, "worker-0.lock")); err != nil {
+		t.Skip("lock files cannot be removed while held on this platform")
+	}
+
+	if err := a.Renew(ctx); err == nil {
+		t.Error("Renew() after the lock file was removed succeeded, want error")
+	}
+}
+
+func TestNewFileAllocatorInvalid(t *testing.T) {
+	for _, bits := range []uint8{0, 9} {
+		if _, err := snowflake.NewFileAllocator(t.TempDir(), bits); err == nil {
+			t.Errorf("NewFileAllocator with %d bits succeeded, want error", bits)
+		}
+	}
+}
+
+// TestFileAllocatorHelper acquires a worker ID when run as a subprocess
+// by TestFileAllocatorCrash, and holds it until killed or its standard
+// input is closed.
+func TestFileAllocatorHelper(t *testing.T) {
+	dir := os.Getenv("SNOWFLAKE_ALLOCATOR_DIR")
+	if dir == "" {
+		t.Skip("only run as a subprocess")
+	}
+
+	a, err := snowflake.NewFileAllocator(dir, 5)
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+
+	id, err := a.Acquire(context.Background())
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+
+	fmt.Println(id)
+
+	// Block on stdin rather than with select {}, which the runtime's
+	// deadlock detector may end, releasing the lock.
+	io.Copy(io.Discard, os.Stdin)
+	runtime.KeepAlive(a)
+}
+
+func TestFileAllocatorCrash(t *testing.T) {
+	dir := t.TempDir()
+	ctx := context.Background()
+
+	// Subprocesses contend for worker IDs with this process.
+	var procs []*exec.Cmd
+	seen := make(map[string]bool)
+	for i := 0; i < 3; i++ {
+		cmd := exec.Command(os.Args[0], "-test.run=^TestFileAllocatorHelper$")
+		cmd.Env = append(os.Environ(), "SNOWFLAKE_ALLOCATOR_DIR="+dir)
+		out, err := cmd.StdoutPipe()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		in, err := cmd.StdinPipe()
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer in.Close()
+
+		if err := cmd.Start(); err != nil {
+			t.Fatal(err)
+		}
+		defer cmd.Process.Kill()
+		procs = append(procs, cmd)
+
+		line, err := bufio.NewReader(out).ReadString('\n')
+		if err != nil || seen[line] {
+			t.Fatalf("subprocess acquired %q, %v, want a new worker ID", line, err)
+		}
+		seen[line] = true
+	}
+
+	a := newFileAllocator(t, dir, 5)
+	if id, err := a.Acquire(ctx); err != nil || id != 3 {
+		t.Fatalf("Acquire() while subprocesses run = %d, %v, want 3", id, err)
+	}
+
+	// The subprocess holding worker ID 0 crashes without releasing it.
+	procs[0].Process.Kill()
+	procs[0].Wait()
+
+	b := newFileAllocator(t, dir, 5)
+	if id, err := b.Acquire(ctx); err != nil || id != 0 {
+		t.Fatalf("Acquire() after a subprocess died = %d, %v, want 0", id, err)
+	}
+}
+
+// fixedAllocator hands out a fixed worker ID and records its calls.
+type fixedAllocator struct {
+	id       uint8
+	err      error
+	acquired int
+	released int
+}
+
+func (a *fixedAllocator) Acquire(ctx context.Context) (uint8, error) {
+	a.acquired++
+	return a.id, a.err
+}
+
+func (a *fixedAllocator) Renew(ctx context.Context) error { return nil }
+
+func (a *fixedAllocator) Release() error {
+	a.released++
+	return nil
+}
+
+func TestWithAllocator(t *testing.T) {
+	dir := t.TempDir()
+	epoch := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	newGenerator := func(opts ...snowflake.Option) *snowflake.Generator {
+		t.Helper()
+
+		a := newFileAllocator(t, dir, 5)
+		g, err := snowflake.New(append(opts, snowflake.WithEpoch(epoch), snowflake.WithAllocator(a))...)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return g
+	}
+
+	g0, g1 := newGenerator(), newGenerator(snowflake.WithShards(2))
+	if got := g0.Deconstruct(g0.Generate()).WorkerID; got != 0 {
+		t.Errorf("first Generator worker ID = %d, want 0", got)
+	}
+
+	if got := g1.Deconstruct(g1.Generate()).WorkerID; got != 1 {
+		t.Errorf("sharded Generator worker ID = %d, want 1", got)
+	}
+
+	if err := g0.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := newGenerator().Config().WorkerID; got != 0 {
+		t.Errorf("worker ID after Close = %d, want 0", got)
+	}
+}
+
+func TestWithAllocatorInvalid(t *testing.T) {
+	epoch := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	if _, err := snowflake.New(snowflake.WithEpoch(epoch), snowflake.WithWorkerID(1), snowflake.WithAllocator(&fixedAllocator{})); err == nil {
+		t.Error("New with WithWorkerID and WithAllocator succeeded, want error")
+	}
+
+	if _, err := snowflake.New(snowflake.WithAllocator(nil)); err == nil {
+		t.Error("New with a nil allocator succeeded, want error")
+	}
+
+	errAcquire := errors.New("no IDs left")
+	if _, err := snowflake.New(snowflake.WithEpoch(epoch), snowflake.WithAllocator(&fixedAllocator{err: errAcquire})); !errors.Is(err, errAcquire) {
+		t.Errorf("New with a failing allocator = %v, want %v", err, errAcquire)
+	}
+
+	// A worker ID that does not fit is given back.
+	a := &fixedAllocator{id: 32}
+	if _, err := snowflake.New(snowflake.WithEpoch(epoch), snowflake.WithAllocator(a)); err == nil {
+		t.Error("New with worker ID 32 succeeded, want error")
+	}
+
+	if a.acquired != 1 || a.released != 1 {
+		t.Errorf("allocator acquired %d and released %d times, want once each", a.acquired, a.released)
+	}
+
+	// A configuration error does not acquire at all.
+	a = &fixedAllocator{}
+	if _, err := snowflake.New(snowflake.WithAllocator(a), snowflake.WithShards(3)); err == nil {
+		t.Error("New with 3 shards succeeded, want error")
+	}
+
+	if a.acquired != 0 {
+		t.Errorf("allocator acquired %d times for an invalid configuration, want 0", a.acquired)
+	}
+}
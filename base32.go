@@ -0,0 +1,109 @@
+// MIT License
+
+// Copyright (c) 2022 Project-Sparrow
+// Copyright (c) 2023 Kelwing <kelwing@kelnet.org>
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package snowflake
+
+import (
+	"fmt"
+	"math"
+)
+
+// base32Len is the length of the largest Snowflake in base32.
+const base32Len = 13
+
+// crockfordAlphabet holds the digits of Crockford's base32,
+// which leaves out I, L, O and U.
+const crockfordAlphabet = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+
+// crockfordDigits maps input characters to their value, or 0xFF if invalid.
+// Lowercase letters are accepted, and I and L decode as 1 and O as 0.
+var crockfordDigits = func() (d [256]byte) {
+	for i := range d {
+		d[i] = 0xFF
+	}
+
+	for i, c := range []byte(crockfordAlphabet) {
+		d[c] = byte(i)
+		d[c|0x20] = byte(i)
+	}
+
+	for c, v := range map[byte]byte{'I': 1, 'L': 1, 'O': 0} {
+		d[c] = v
+		d[c|0x20] = v
+	}
+
+	return d
+}()
+
+// EncodeBase32 returns the Snowflake as a number in Crockford's base32,
+// in uppercase without leading zeros or hyphens: 1234 is "16J".
+func (s Snowflake) EncodeBase32() string {
+	var buf [base32Len]byte
+
+	i := len(buf)
+	for v := uint64(s); ; v >>= 5 {
+		i--
+		buf[i] = crockfordAlphabet[v&31]
+		if v < 32 {
+			break
+		}
+	}
+
+	return string(buf[i:])
+}
+
+// ParseBase32 parses a Snowflake written as a number in Crockford's base32.
+// It accepts lowercase letters, decodes I and L as 1 and O as 0, and ignores
+// hyphens. Errors wrap ErrSyntax, ErrOverflow or, for input longer than 13
+// digits with a hyphen between each, ErrTooLong.
+func ParseBase32(s string) (Snowflake, error) {
+	if max := 2*base32Len - 1; len(s) > max {
+		return 0, fmt.Errorf("%w: base32 Snowflake of %d bytes, want at most %d", ErrTooLong, len(s), max)
+	}
+
+	var n uint64
+	digits := 0
+	for i := 0; i < len(s); i++ {
+		if s[i] == '-' {
+			continue
+		}
+
+		d := crockfordDigits[s[i]]
+		if d == 0xFF {
+			return 0, fmt.Errorf("%w: base32 Snowflake %q has invalid character %q", ErrSyntax, s, s[i])
+		}
+
+		if n > math.MaxUint64>>5 {
+			return 0, fmt.Errorf("%w: base32 Snowflake %q", ErrOverflow, s)
+		}
+
+		n = n<<5 | uint64(d)
+		digits++
+	}
+
+	if digits == 0 {
+		return 0, fmt.Errorf("%w: base32 Snowflake %q has no digits", ErrSyntax, s)
+	}
+
+	return Snowflake(n), nil
+}
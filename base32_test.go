@@ -0,0 +1,133 @@
+// MIT License
+
+// Copyright (c) 2022 Project-Sparrow
+// Copyright (c) 2023 Kelwing <kelwing@kelnet.org>
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package snowflake_test
+
+import (
+	"errors"
+	"math"
+	"testing"
+	"testing/quick"
+
+	"wumpgo.dev/snowflake"
+)
+
+func TestBase32(t *testing.T) {
+	for _, tt := range []struct {
+		s    snowflake.Snowflake
+		want string
+	}{
+		{0, "0"},
+		{1, "1"},
+		{31, "Z"},
+		{32, "10"},
+		{1234, "16J"},                        // 1*32² + 6*32 + 18
+		{1 << 60, "1000000000000"},           // 1 followed by twelve zeros
+		{math.MaxUint64, "FZZZZZZZZZZZZ"},    // 15*32¹² + 31*(32¹¹ + ... + 1)
+		{175928847299117063, "4W86BB0G4007"}, // 0x0271065AC1020007 in 5-bit groups
+	} {
+		if got := tt.s.EncodeBase32(); got != tt.want {
+			t.Errorf("EncodeBase32(%d) = %q, want %q", tt.s, got, tt.want)
+		}
+
+		if got, err := snowflake.ParseBase32(tt.want); err != nil || got != tt.s {
+			t.Errorf("ParseBase32(%q) = %d, %v, want %d", tt.want, got, err, tt.s)
+		}
+	}
+}
+
+func TestParseBase32Lenient(t *testing.T) {
+	for _, tt := range []struct {
+		in   string
+		want snowflake.Snowflake
+	}{
+		{"16j", 1234},
+		{"1-6-J", 1234},
+		{"-16J-", 1234},
+		{"I6J", 1234},
+		{"i6j", 1234},
+		{"L6J", 1234},
+		{"l6j", 1234},
+		{"O", 0},
+		{"oo1", 1},
+		{"000016J", 1234},
+		{"fzzz-zzzz-zzzzz", math.MaxUint64},
+	} {
+		if got, err := snowflake.ParseBase32(tt.in); err != nil || got != tt.want {
+			t.Errorf("ParseBase32(%q) = %d, %v, want %d", tt.in, got, err, tt.want)
+		}
+	}
+}
+
+func TestParseBase32Invalid(t *testing.T) {
+	for _, tt := range []struct {
+		in  string
+		err error
+	}{
+		{"", snowflake.ErrSyntax},
+		{"---", snowflake.ErrSyntax},
+		{"U", snowflake.ErrSyntax},
+		{"u1", snowflake.ErrSyntax},
+		{"16J*", snowflake.ErrSyntax},
+		{"16 J", snowflake.ErrSyntax},
+		{"1é", snowflake.ErrSyntax},
+		{"G000000000000", snowflake.ErrOverflow},
+		{"ZZZZZZZZZZZZZZ", snowflake.ErrOverflow},
+	} {
+		if got, err := snowflake.ParseBase32(tt.in); !errors.Is(err, tt.err) {
+			t.Errorf("ParseBase32(%q) = %d, %v, want %v", tt.in, got, err, tt.err)
+		}
+	}
+}
+
+func TestBase32RoundTrip(t *testing.T) {
+	roundTrip := func(v uint64) bool {
+		s := snowflake.Snowflake(v)
+		got, err := snowflake.ParseBase32(s.EncodeBase32())
+		return err == nil && got == s
+	}
+
+	if err := quick.Check(roundTrip, nil); err != nil {
+		t.Error(err)
+	}
+}
+
+func FuzzParseBase32(f *testing.F) {
+	for _, s := range []string{"0", "16J", "4W86-BB0G-4007", "FZZZZZZZZZZZZ", "G000000000000", "ilo", "U", ""} {
+		f.Add(s)
+	}
+
+	f.Fuzz(func(t *testing.T, s string) {
+		got, err := snowflake.ParseBase32(s)
+		if err != nil {
+			if !errors.Is(err, snowflake.ErrSyntax) && !errors.Is(err, snowflake.ErrOverflow) && !errors.Is(err, snowflake.ErrTooLong) {
+				t.Errorf("ParseBase32(%q) = %v, want ErrSyntax, ErrOverflow or ErrTooLong", s, err)
+			}
+			return
+		}
+
+		if again, err := snowflake.ParseBase32(got.EncodeBase32()); err != nil || again != got {
+			t.Errorf("ParseBase32(%q) = %d, but %q parses to %d, %v", s, got, got.EncodeBase32(), again, err)
+		}
+	})
+}
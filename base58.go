@@ -0,0 +1,42 @@
+// MIT License
+
+// Copyright (c) 2022 Project-Sparrow
+// Copyright (c) 2023 Kelwing <kelwing@kelnet.org>
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package snowflake
+
+// base58 uses the Bitcoin alphabet, which leaves out 0, I, O and l.
+var base58 = newRadix("base58", "123456789ABCDEFGHJKLMNPQRSTUVWXYZabcdefghijkmnopqrstuvwxyz")
+
+// EncodeBase58 returns the Snowflake as a number in Base58 with the Bitcoin
+// alphabet. This is the Bitcoin encoding of its big-endian bytes with leading
+// zero bytes removed, so 0 is "1" and no other encoding starts with "1".
+func (s Snowflake) EncodeBase58() string {
+	return base58.encode(uint64(s))
+}
+
+// ParseBase58 parses a Snowflake written as a number in Base58 with the
+// Bitcoin alphabet. Leading "1"s are zeros and do not change the value,
+// but input longer than the 11 characters of the largest Snowflake is an
+// error wrapping ErrTooLong. Other errors wrap ErrSyntax or ErrOverflow.
+func ParseBase58(s string) (Snowflake, error) {
+	return base58.parse(s)
+}
@@ -0,0 +1,136 @@
+// MIT License
+
+// Copyright (c) 2022 Project-Sparrow
+// Copyright (c) 2023 Kelwing <kelwing@kelnet.org>
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package snowflake_test
+
+import (
+	"errors"
+	"math"
+	"strings"
+	"testing"
+
+	"wumpgo.dev/snowflake"
+)
+
+func TestBase58(t *testing.T) {
+	for _, tt := range []struct {
+		s    snowflake.Snowflake
+		want string
+	}{
+		{0, "1"},
+		{1, "2"},
+		{57, "z"},
+		{58, "21"},
+		// Bitcoin Core's base58_encode_decode.json vectors that fit in
+		// 64 bits, with leading zero bytes (and so leading "1"s) removed.
+		{0x61, "2g"},
+		{0x626262, "a3gV"},
+		{0x636363, "aPEr"},
+		{0x572e4794, "3EFU7m"},
+		{0x10c8511e, "Rt5zm"},
+		{0x287fb4cd, "233QC4"}, // 00 00 28 7f b4 cd is "11233QC4"
+		{0x516b6fcd0f, "ABnLTmg"},
+		{math.MaxUint64, "jpXCZedGfVQ"},
+	} {
+		if got := tt.s.EncodeBase58(); got != tt.want {
+			t.Errorf("EncodeBase58(%#x) = %q, want %q", uint64(tt.s), got, tt.want)
+		}
+
+		if got, err := snowflake.ParseBase58(tt.want); err != nil || got != tt.s {
+			t.Errorf("ParseBase58(%q) = %#x, %v, want %#x", tt.want, uint64(got), err, uint64(tt.s))
+		}
+
+		// Leading zero bytes in the Bitcoin encoding do not change the value,
+		// up to the length of the largest Snowflake.
+		padded := strings.Repeat("1", 11-len(tt.want)) + tt.want
+		if got, err := snowflake.ParseBase58(padded); err != nil || got != tt.s {
+			t.Errorf("ParseBase58(%q) = %#x, %v, want %#x", padded, uint64(got), err, uint64(tt.s))
+		}
+
+		if got, err := snowflake.ParseBase58("1" + padded); !errors.Is(err, snowflake.ErrTooLong) {
+			t.Errorf("ParseBase58(%q) = %#x, %v, want ErrTooLong", "1"+padded, uint64(got), err)
+		}
+	}
+}
+
+func TestParseBase58Invalid(t *testing.T) {
+	for _, tt := range []struct {
+		in  string
+		err error
+	}{
+		{"", snowflake.ErrSyntax},
+		{"0", snowflake.ErrSyntax},
+		{"O", snowflake.ErrSyntax},
+		{"I", snowflake.ErrSyntax},
+		{"l", snowflake.ErrSyntax},
+		{"2g-", snowflake.ErrSyntax},
+		{" 2g", snowflake.ErrSyntax},
+		{"jpXCZedGfVR", snowflake.ErrOverflow}, // MaxUint64 + 1
+		{"zzzzzzzzzzz", snowflake.ErrOverflow},
+		{"211111111111", snowflake.ErrTooLong},
+	} {
+		if got, err := snowflake.ParseBase58(tt.in); !errors.Is(err, tt.err) {
+			t.Errorf("ParseBase58(%q) = %d, %v, want %v", tt.in, got, err, tt.err)
+		}
+	}
+}
+
+func FuzzBase58(f *testing.F) {
+	for _, v := range []uint64{0, 1, 57, 58, 0x287fb4cd, math.MaxUint64} {
+		f.Add(v)
+	}
+
+	f.Fuzz(func(t *testing.T, v uint64) {
+		s := snowflake.Snowflake(v)
+		enc := s.EncodeBase58()
+		if len(enc) > 1 && enc[0] == '1' {
+			t.Errorf("EncodeBase58(%d) = %q, has a leading zero", v, enc)
+		}
+
+		if got, err := snowflake.ParseBase58(enc); err != nil || got != s {
+			t.Errorf("ParseBase58(%q) = %d, %v, want %d", enc, got, err, s)
+		}
+	})
+}
+
+func FuzzParseBase58(f *testing.F) {
+	for _, s := range []string{"1", "2g", "11233QC4", "jpXCZedGfVQ", "jpXCZedGfVR", "0"} {
+		f.Add(s)
+	}
+
+	f.Fuzz(func(t *testing.T, in string) {
+		got, err := snowflake.ParseBase58(in)
+		if err != nil {
+			return
+		}
+
+		want := strings.TrimLeft(in, "1")
+		if want == "" {
+			want = "1"
+		}
+
+		if enc := got.EncodeBase58(); enc != want {
+			t.Errorf("ParseBase58(%q) = %d, which encodes as %q, want %q", in, got, enc, want)
+		}
+	})
+}
@@ -0,0 +1,46 @@
+// MIT License
+
+// Copyright (c) 2022 Project-Sparrow
+// Copyright (c) 2023 Kelwing <kelwing@kelnet.org>
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package snowflake
+
+// base62 digits are in ASCII order: 0-9, then A-Z, then a-z.
+// The alphabet is part of the encoding and will not change.
+var base62 = newRadix("base62", "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz")
+
+// EncodeBase62 returns the Snowflake as a number in base62 with the digits
+// 0-9, A-Z and a-z, in that order, using at most 11 characters.
+//
+// Encodings have no leading zeros, so they vary in length and do not sort
+// in numeric order: "z" (61) sorts after "10" (62). Compare the parsed
+// values, or pad to 11 characters with "0", to sort them.
+func (s Snowflake) EncodeBase62() string {
+	return base62.encode(uint64(s))
+}
+
+// ParseBase62 parses a Snowflake written as a number in base62 with the
+// digits 0-9, A-Z and a-z. It is case-sensitive, and leading zeros are
+// allowed up to 11 characters in all; longer input is an error wrapping
+// ErrTooLong. Other errors wrap ErrSyntax or ErrOverflow.
+func ParseBase62(s string) (Snowflake, error) {
+	return base62.parse(s)
+}
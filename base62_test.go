@@ -0,0 +1,104 @@
+// MIT License
+
+// Copyright (c) 2022 Project-Sparrow
+// Copyright (c) 2023 Kelwing <kelwing@kelnet.org>
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package snowflake_test
+
+import (
+	"errors"
+	"math"
+	"strings"
+	"testing"
+	"testing/quick"
+
+	"wumpgo.dev/snowflake"
+)
+
+func TestBase62(t *testing.T) {
+	for _, tt := range []struct {
+		s    snowflake.Snowflake
+		want string
+	}{
+		{0, "0"},
+		{1, "1"},
+		{9, "9"},
+		{10, "A"},
+		{35, "Z"},
+		{36, "a"},
+		{61, "z"},
+		{62, "10"},
+		{3843, "zz"}, // 61*62 + 61
+		{3844, "100"},
+		{175928847299117063, "Czks0tP37X"},
+		{math.MaxUint64, "LygHa16AHYF"},
+	} {
+		if got := tt.s.EncodeBase62(); got != tt.want {
+			t.Errorf("EncodeBase62(%d) = %q, want %q", tt.s, got, tt.want)
+		}
+
+		if got, err := snowflake.ParseBase62(tt.want); err != nil || got != tt.s {
+			t.Errorf("ParseBase62(%q) = %d, %v, want %d", tt.want, got, err, tt.s)
+		}
+
+		padded := strings.Repeat("0", 11-len(tt.want)) + tt.want
+		if got, err := snowflake.ParseBase62(padded); err != nil || got != tt.s {
+			t.Errorf("ParseBase62(%q) = %d, %v, want %d", padded, got, err, tt.s)
+		}
+
+		if got, err := snowflake.ParseBase62("0" + padded); !errors.Is(err, snowflake.ErrTooLong) {
+			t.Errorf("ParseBase62(%q) = %d, %v, want ErrTooLong", "0"+padded, got, err)
+		}
+	}
+}
+
+func TestParseBase62Invalid(t *testing.T) {
+	for _, tt := range []struct {
+		in  string
+		err error
+	}{
+		{"", snowflake.ErrSyntax},
+		{"-1", snowflake.ErrSyntax},
+		{"1_", snowflake.ErrSyntax},
+		{"Czks0tP37X=", snowflake.ErrSyntax},
+		{"LygHa16AHYG", snowflake.ErrOverflow}, // MaxUint64 + 1
+		{"zzzzzzzzzzz", snowflake.ErrOverflow},
+		{"100000000000", snowflake.ErrTooLong},
+		{"000000000000", snowflake.ErrTooLong},
+	} {
+		if got, err := snowflake.ParseBase62(tt.in); !errors.Is(err, tt.err) {
+			t.Errorf("ParseBase62(%q) = %d, %v, want %v", tt.in, got, err, tt.err)
+		}
+	}
+}
+
+func TestBase62RoundTrip(t *testing.T) {
+	roundTrip := func(v uint64) bool {
+		s := snowflake.Snowflake(v)
+		enc := s.EncodeBase62()
+		got, err := snowflake.ParseBase62(enc)
+		return err == nil && got == s && len(enc) <= 11
+	}
+
+	if err := quick.Check(roundTrip, nil); err != nil {
+		t.Error(err)
+	}
+}
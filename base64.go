@@ -0,0 +1,67 @@
+// MIT License
+
+// Copyright (c) 2022 Project-Sparrow
+// Copyright (c) 2023 Kelwing <kelwing@kelnet.org>
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package snowflake
+
+import (
+	"encoding/base64"
+	"encoding/binary"
+	"fmt"
+)
+
+// base64Len is the length of a Snowflake in unpadded base64.
+const base64Len = 11
+
+var base64Encoding = base64.RawURLEncoding.Strict()
+
+// EncodeBase64 returns the URL-safe, unpadded base64 encoding of the
+// Snowflake's 8 big-endian bytes, which is always 11 characters long.
+func (s Snowflake) EncodeBase64() string {
+	b := s.Bytes()
+	return base64Encoding.EncodeToString(b[:])
+}
+
+// ParseBase64 parses a Snowflake encoded by EncodeBase64.
+// Errors wrap ErrSyntax, or ErrTooLong for input longer than 11 characters.
+func ParseBase64(s string) (Snowflake, error) {
+	if len(s) > base64Len {
+		return 0, fmt.Errorf("%w: base64 Snowflake of %d characters, want %d", ErrTooLong, len(s), base64Len)
+	}
+
+	if len(s) < base64Len {
+		return 0, fmt.Errorf("%w: base64 Snowflake %q has %d characters, want %d", ErrSyntax, s, len(s), base64Len)
+	}
+
+	// Decode skips newlines, so a short count means the input had some.
+	var b [ByteLen]byte
+	n, err := base64Encoding.Decode(b[:], []byte(s))
+	if err != nil {
+		return 0, fmt.Errorf("%w: base64 Snowflake %q: %v", ErrSyntax, s, err)
+	}
+
+	if n != ByteLen {
+		return 0, fmt.Errorf("%w: base64 Snowflake %q decodes to %d bytes, want %d", ErrSyntax, s, n, ByteLen)
+	}
+
+	return Snowflake(binary.BigEndian.Uint64(b[:])), nil
+}
@@ -0,0 +1,91 @@
+// MIT License
+
+// Copyright (c) 2022 Project-Sparrow
+// Copyright (c) 2023 Kelwing <kelwing@kelnet.org>
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package snowflake_test
+
+import (
+	"errors"
+	"math"
+	"testing"
+	"testing/quick"
+
+	"wumpgo.dev/snowflake"
+)
+
+func TestBase64(t *testing.T) {
+	for _, tt := range []struct {
+		s    snowflake.Snowflake
+		want string
+	}{
+		{0, "AAAAAAAAAAA"},
+		{1, "AAAAAAAAAAE"},
+		{175928847299117063, "AnEGWsECAAc"},
+		{math.MaxUint64, "__________8"},
+	} {
+		if got := tt.s.EncodeBase64(); got != tt.want {
+			t.Errorf("EncodeBase64(%d) = %q, want %q", tt.s, got, tt.want)
+		}
+
+		if got, err := snowflake.ParseBase64(tt.want); err != nil || got != tt.s {
+			t.Errorf("ParseBase64(%q) = %d, %v, want %d", tt.want, got, err, tt.s)
+		}
+	}
+}
+
+func TestBase64RoundTrip(t *testing.T) {
+	roundTrip := func(v uint64) bool {
+		s := snowflake.Snowflake(v)
+		got, err := snowflake.ParseBase64(s.EncodeBase64())
+		return err == nil && got == s
+	}
+
+	if err := quick.Check(roundTrip, nil); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestParseBase64Invalid(t *testing.T) {
+	for _, in := range []string{
+		"",
+		"AAAAAAAAAA",
+		"AAAAAAAAAA=",
+		"AnEGWsECAA+",
+		"AnEGWsECAA/",
+		"AnEGWsECA c",
+		// Only the canonical encoding with zero trailing bits is accepted.
+		"AAAAAAAAAAB",
+		"__________-",
+		// Newlines are skipped by encoding/base64 and must not shorten the input.
+		"AAAAAAAAAQ\n",
+		"\nAAAAAAAAAA",
+		"AAAAA\r\nAAAA",
+	} {
+		if got, err := snowflake.ParseBase64(in); !errors.Is(err, snowflake.ErrSyntax) {
+			t.Errorf("ParseBase64(%q) = %d, %v, want ErrSyntax", in, got, err)
+		}
+	}
+
+	if got, err := snowflake.ParseBase64("AAAAAAAAAAAA"); !errors.Is(err, snowflake.ErrTooLong) {
+		t.Errorf("ParseBase64(12 characters) = %d, %v, want ErrTooLong", got, err)
+	}
+}
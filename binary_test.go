@@ -0,0 +1,190 @@
+// MIT License
+
+// Copyright (c) 2022 Project-Sparrow
+// Copyright (c) 2023 Kelwing <kelwing@kelnet.org>
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package snowflake_test
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/gob"
+	"errors"
+	"fmt"
+	"math"
+	"testing"
+
+	"wumpgo.dev/snowflake"
+)
+
+var (
+	_ encoding.BinaryMarshaler   = snowflake.Snowflake(0)
+	_ encoding.BinaryUnmarshaler = (*snowflake.Snowflake)(nil)
+	_ encoding.BinaryMarshaler   = snowflake.NullSnowflake{}
+	_ encoding.BinaryUnmarshaler = (*snowflake.NullSnowflake)(nil)
+)
+
+func TestMarshalBinary(t *testing.T) {
+	for _, s := range []snowflake.Snowflake{0, 1, 175928847299117063, math.MaxUint64} {
+		data, err := s.MarshalBinary()
+		if want := s.Bytes(); err != nil || !bytes.Equal(data, want[:]) {
+			t.Errorf("MarshalBinary(%d) = %x, %v, want %x", s, data, err, want)
+		}
+
+		var got snowflake.Snowflake
+		if err := got.UnmarshalBinary(data); err != nil || got != s {
+			t.Errorf("UnmarshalBinary(%x) = %d, %v, want %d", data, got, err, s)
+		}
+	}
+
+	for _, n := range []int{0, 1, 7, 9} {
+		got := snowflake.Snowflake(42)
+		if err := got.UnmarshalBinary(make([]byte, n)); !errors.Is(err, snowflake.ErrByteLength) {
+			t.Errorf("UnmarshalBinary(%d bytes) = %v, want ErrByteLength", n, err)
+		}
+
+		if got != 42 {
+			t.Errorf("failed UnmarshalBinary changed the Snowflake to %d", got)
+		}
+	}
+}
+
+func TestNullSnowflakeMarshalBinary(t *testing.T) {
+	for _, tt := range []struct {
+		s    snowflake.NullSnowflake
+		want []byte
+	}{
+		{snowflake.NullSnowflake{}, []byte{}},
+		{snowflake.NewNullSnowflake(42, false), []byte{}},
+		{snowflake.NewNullSnowflake(0, true), []byte{0, 0, 0, 0, 0, 0, 0, 0}},
+		{snowflake.NewNullSnowflake(175928847299117063, true), []byte{0x02, 0x71, 0x06, 0x5a, 0xc1, 0x02, 0x00, 0x07}},
+	} {
+		data, err := tt.s.MarshalBinary()
+		if err != nil || !bytes.Equal(data, tt.want) {
+			t.Errorf("MarshalBinary(%+v) = %x, %v, want %x", tt.s, data, err, tt.want)
+		}
+
+		got := snowflake.NewNullSnowflake(99, true)
+		if err := got.UnmarshalBinary(data); err != nil || got.Valid != tt.s.Valid || got.ValueOrZero() != tt.s.ValueOrZero() {
+			t.Errorf("UnmarshalBinary(%x) = %+v, %v, want %+v", data, got, err, tt.s)
+		}
+	}
+
+	for _, n := range []int{1, 7, 9} {
+		var got snowflake.NullSnowflake
+		if err := got.UnmarshalBinary(make([]byte, n)); !errors.Is(err, snowflake.ErrByteLength) {
+			t.Errorf("UnmarshalBinary(%d bytes) = %v, want ErrByteLength", n, err)
+		}
+	}
+}
+
+func TestBinaryGob(t *testing.T) {
+	type record struct {
+		ID     snowflake.Snowflake
+		Parent snowflake.NullSnowflake
+		Owner  snowflake.NullSnowflake
+	}
+
+	in := record{
+		ID:     175928847299117063,
+		Parent: snowflake.NewNullSnowflake(math.MaxUint64, true),
+	}
+
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(in); err != nil {
+		t.Fatal(err)
+	}
+
+	// Like other zero values, the empty encoding of a null Owner is left out
+	// of the stream, so it decodes into a fresh record.
+	var out record
+	if err := gob.NewDecoder(&buf).Decode(&out); err != nil {
+		t.Fatal(err)
+	}
+
+	if out.ID != in.ID || out.Parent != in.Parent || out.Owner.Valid {
+		t.Errorf("gob round trip = %+v, want %+v", out, in)
+	}
+}
+
+// fakeRedis stores values the way go-redis does: arguments that implement
+// encoding.BinaryMarshaler are marshaled, and Scan unmarshals into
+// destinations that implement encoding.BinaryUnmarshaler.
+type fakeRedis map[string]string
+
+func (r fakeRedis) Set(key string, value any) error {
+	m, ok := value.(encoding.BinaryMarshaler)
+	if !ok {
+		return fmt.Errorf("can't marshal %T", value)
+	}
+
+	b, err := m.MarshalBinary()
+	if err != nil {
+		return err
+	}
+
+	r[key] = string(b)
+	return nil
+}
+
+func (r fakeRedis) Scan(key string, dst any) error {
+	v, ok := r[key]
+	if !ok {
+		return errors.New("redis: nil")
+	}
+
+	u, ok := dst.(encoding.BinaryUnmarshaler)
+	if !ok {
+		return fmt.Errorf("can't unmarshal %T", dst)
+	}
+
+	return u.UnmarshalBinary([]byte(v))
+}
+
+func TestBinaryRedis(t *testing.T) {
+	r := fakeRedis{}
+
+	s := snowflake.Snowflake(175928847299117063)
+	if err := r.Set("id", s); err != nil {
+		t.Fatal(err)
+	}
+
+	var got snowflake.Snowflake
+	if err := r.Scan("id", &got); err != nil || got != s {
+		t.Errorf("Scan(id) = %d, %v, want %d", got, err, s)
+	}
+
+	for _, ns := range []snowflake.NullSnowflake{{}, snowflake.NewNullSnowflake(s, true)} {
+		if err := r.Set("parent", ns); err != nil {
+			t.Fatal(err)
+		}
+
+		var got snowflake.NullSnowflake
+		if err := r.Scan("parent", &got); err != nil || got != ns {
+			t.Errorf("Scan(parent) = %+v, %v, want %+v", got, err, ns)
+		}
+	}
+
+	r["bad"] = "short"
+	if err := r.Scan("bad", &got); !errors.Is(err, snowflake.ErrByteLength) {
+		t.Errorf("Scan(bad) = %v, want ErrByteLength", err)
+	}
+}
@@ -0,0 +1,131 @@
+// MIT License
+
+// Copyright (c) 2022 Project-Sparrow
+// Copyright (c) 2023 Kelwing <kelwing@kelnet.org>
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package snowflake
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// Block is a run of consecutive Snowflakes claimed by ReserveBlock.
+// Its Snowflakes take consecutive sequence numbers, moving on to the next
+// time unit at sequence 0 once a time unit's sequence is used up.
+type Block struct {
+	g   *Generator
+	ts  int64
+	seq int64
+	n   int
+}
+
+// Len returns the number of Snowflakes in the Block.
+func (b Block) Len() int {
+	return b.n
+}
+
+// First returns the first Snowflake of the Block.
+func (b Block) First() Snowflake {
+	return b.At(0)
+}
+
+// At returns the i-th Snowflake of the Block, counting from 0.
+// It panics if i is out of range.
+func (b Block) At(i int) Snowflake {
+	if i < 0 || i >= b.n {
+		panic(fmt.Sprintf("snowflake: block index %d out of range [0, %d)", i, b.n))
+	}
+
+	per := int64(b.g.seqMax) + 1
+	slot := b.seq + int64(i)
+
+	return b.g.compose(b.ts+slot/per, b.g.seqBase|uint16(slot%per))
+}
+
+// AppendTo appends every Snowflake of the Block to dst
+// and returns the extended slice.
+func (b Block) AppendTo(dst []Snowflake) []Snowflake {
+	for i := 0; i < b.n; i++ {
+		dst = append(dst, b.At(i))
+	}
+
+	return dst
+}
+
+// ReserveBlock claims n consecutive timestamp and sequence pairs at once,
+// for example to assign Snowflakes to rows that should stay clustered in an
+// index. A large Block may run into future time units; Snowflakes generated
+// afterwards continue after the Block, and wait for the clock once those
+// time units are used up. The clock is handled as by GenerateContext without
+// a deadline. ReserveBlock cannot be used with WithLockFree or
+// WithRandomSequenceStart.
+func (g *Generator) ReserveBlock(n int) (Block, error) {
+	if n <= 0 {
+		return Block{}, fmt.Errorf("block size %d is not positive", n)
+	}
+
+	if g.lockFree || g.randomStart {
+		return Block{}, errors.New("blocks cannot be reserved in lock-free mode or with random sequence starts")
+	}
+
+	if err := g.enter(); err != nil {
+		return Block{}, err
+	}
+
+	var ev events
+	defer g.fire(&ev)
+	defer g.exit()
+
+	sh := g.lock()
+	defer sh.mtx.Unlock()
+
+	return sh.reserveLocked(n, &ev)
+}
+
+// reserveLocked is like ReserveBlock but the caller must hold g.mtx.
+func (g *Generator) reserveLocked(n int, ev *events) (Block, error) {
+	// Claim the first slot as Generate would, then the rest after it.
+	prevTs, prevSeq := g.lastTimestamp, g.sequence
+	s, err := g.generateLocked(context.Background(), true, ev)
+	if err != nil {
+		return Block{}, err
+	}
+
+	b := Block{g: g, ts: g.lastTimestamp, seq: int64(g.sequence), n: n}
+
+	per := int64(g.seqMax) + 1
+	end := b.seq + int64(n) - 1
+	last := b.ts + end/per
+	if uint64(last) > g.maxTimestamp() {
+		g.lastTimestamp, g.sequence = prevTs, prevSeq
+		return Block{}, fmt.Errorf("%w: block of %d Snowflakes from %d", ErrTimestampOverflow, n, s)
+	}
+
+	g.lastTimestamp, g.sequence = last, uint16(end%per)
+	if last > b.ts {
+		g.reservedTop, g.reservedAt = last, min(b.ts, g.timestamp())
+	}
+	g.stats.generated.Add(uint64(n - 1))
+
+	return b, nil
+}
@@ -0,0 +1,207 @@
+// MIT License
+
+// Copyright (c) 2022 Project-Sparrow
+// Copyright (c) 2023 Kelwing <kelwing@kelnet.org>
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package snowflake_test
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"wumpgo.dev/snowflake"
+)
+
+func TestReserveBlock(t *testing.T) {
+	epoch := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	start := epoch.Add(time.Hour)
+	clock := snowflake.NewFakeClock(start)
+
+	g, err := snowflake.New(snowflake.WithEpoch(epoch), snowflake.WithWorkerID(3), snowflake.WithClock(clock))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	before := g.Generate()
+
+	// The block uses the rest of this millisecond, all of the next one
+	// and part of the one after.
+	b, err := g.ReserveBlock(4095 + 4096 + 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if b.Len() != 4095+4096+10 {
+		t.Errorf("Len() = %d, want %d", b.Len(), 4095+4096+10)
+	}
+
+	ids := b.AppendTo(nil)
+	if ids[0] != b.First() || ids[0] <= before {
+		t.Errorf("First() = %d, want %d after %d", b.First(), ids[0], before)
+	}
+
+	for i := 1; i < len(ids); i++ {
+		if ids[i] <= ids[i-1] {
+			t.Fatalf("At(%d) = %d, not after %d", i, ids[i], ids[i-1])
+		}
+	}
+
+	for i, want := range []struct {
+		at  time.Duration
+		seq uint16
+	}{
+		{0, 1},
+		{0, 4095},
+		{time.Millisecond, 0},
+		{time.Millisecond, 4095},
+		{2 * time.Millisecond, 0},
+		{2 * time.Millisecond, 9},
+	} {
+		idx := []int{0, 4094, 4095, 8190, 8191, 8200}[i]
+		p := g.Deconstruct(b.At(idx))
+		if !p.Time.Equal(start.Add(want.at)) || p.Sequence != want.seq || p.WorkerID != 3 {
+			t.Errorf("At(%d) = %+v, want time %v and sequence %d", idx, p, start.Add(want.at), want.seq)
+		}
+	}
+
+	// Generate carries on after the block even though the clock is behind it.
+	after, err := g.TryGenerate()
+	if err != nil {
+		t.Fatalf("TryGenerate() after block = %v", err)
+	}
+
+	if after <= ids[len(ids)-1] {
+		t.Errorf("TryGenerate() = %d, not after block end %d", after, ids[len(ids)-1])
+	}
+
+	if st := g.Stats(); st.Generated != uint64(len(ids))+2 {
+		t.Errorf("Stats().Generated = %d, want %d", st.Generated, len(ids)+2)
+	}
+}
+
+func TestReserveBlockAtPanics(t *testing.T) {
+	g, err := snowflake.New(snowflake.WithEpoch(time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	b, err := g.ReserveBlock(2)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, i := range []int{-1, 2} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("At(%d) did not panic", i)
+				}
+			}()
+			b.At(i)
+		}()
+	}
+}
+
+func TestReserveBlockInvalid(t *testing.T) {
+	epoch := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	g, err := snowflake.New(snowflake.WithEpoch(epoch))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, n := range []int{0, -1} {
+		if _, err := g.ReserveBlock(n); err == nil {
+			t.Errorf("ReserveBlock(%d) succeeded, want error", n)
+		}
+	}
+
+	for _, opt := range []snowflake.Option{snowflake.WithLockFree(), snowflake.WithRandomSequenceStart()} {
+		g, err := snowflake.New(snowflake.WithEpoch(epoch), opt)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if _, err := g.ReserveBlock(1); err == nil {
+			t.Error("ReserveBlock succeeded, want error")
+		}
+	}
+}
+
+func TestReserveBlockClockBackwards(t *testing.T) {
+	g, _ := backwardsGenerator(t, time.Second, snowflake.WithClockBackwardsPolicy(snowflake.PolicyError))
+
+	if _, err := g.ReserveBlock(10000); err == nil {
+		t.Error("ReserveBlock with the clock behind succeeded, want error")
+	}
+}
+
+func TestReserveBlockUnique(t *testing.T) {
+	epoch := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	for _, shards := range []int{1, 4} {
+		clock := snowflake.NewFakeClock(epoch.Add(time.Hour))
+
+		g, err := snowflake.New(snowflake.WithEpoch(epoch), snowflake.WithShards(shards), snowflake.WithClock(clock))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		const goroutines = 16
+		const n = 5000
+
+		results := make([][]snowflake.Snowflake, goroutines)
+		var wg sync.WaitGroup
+		for i := range results {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				ids := make([]snowflake.Snowflake, 0, n)
+				for len(ids) < n {
+					if i%2 == 0 {
+						ids = append(ids, g.Generate())
+						continue
+					}
+
+					b, err := g.ReserveBlock(1 + len(ids)%700)
+					if err != nil {
+						t.Error(err)
+						return
+					}
+					ids = b.AppendTo(ids)
+				}
+				results[i] = ids
+			}(i)
+		}
+		wg.Wait()
+
+		seen := make(map[snowflake.Snowflake]bool)
+		for _, ids := range results {
+			for _, s := range ids {
+				if seen[s] {
+					t.Fatalf("%d shards: duplicate Snowflake %d", shards, s)
+				}
+				seen[s] = true
+			}
+		}
+	}
+}
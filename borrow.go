@@ -0,0 +1,77 @@
+// MIT License
+
+// Copyright (c) 2022 Project-Sparrow
+// Copyright (c) 2023 Kelwing <kelwing@kelnet.org>
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package snowflake
+
+import (
+	"fmt"
+	"time"
+)
+
+// WithBorrowing lets the Generator borrow from future time units instead of
+// waiting when the sequence is exhausted: it moves its timestamp on to the
+// next time unit as long as that is at most max ahead of the clock, and
+// carries on from the clock once it catches up.
+// Snowflakes stay unique and increasing, but during a burst their timestamps
+// may be up to max in the future.
+// WithBorrowing cannot be combined with WithLockFree.
+func WithBorrowing(max time.Duration) Option {
+	return func(g *Generator) error {
+		if err := g.once("borrowing"); err != nil {
+			return err
+		}
+
+		if max <= 0 {
+			return fmt.Errorf("borrowing limit %v is not positive", max)
+		}
+
+		g.maxBorrow = max
+
+		return nil
+	}
+}
+
+// borrow reports whether the timestamp after the last one may be borrowed
+// with the clock reading now, and records it if so.
+// The caller must hold g.mtx.
+func (g *Generator) borrow(now int64) bool {
+	next := g.lastTimestamp + 1
+	if next-now > int64(g.maxBorrow/g.unit) {
+		return false
+	}
+
+	g.borrowTop = max(g.borrowTop, next)
+
+	return true
+}
+
+// borrowedAhead reports whether the clock reading now is behind the last
+// timestamp only because it was borrowed or reserved by ReserveBlock,
+// rather than the clock moving backwards.
+// The caller must hold g.mtx.
+func (g *Generator) borrowedAhead(now int64) bool {
+	if g.lastTimestamp <= g.reservedTop && now >= g.reservedAt {
+		return true
+	}
+	return g.lastTimestamp <= g.borrowTop && g.lastTimestamp-now <= int64(g.maxBorrow/g.unit)
+}
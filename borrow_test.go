@@ -0,0 +1,102 @@
+// MIT License
+
+// Copyright (c) 2022 Project-Sparrow
+// Copyright (c) 2023 Kelwing <kelwing@kelnet.org>
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package snowflake_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"wumpgo.dev/snowflake"
+)
+
+func TestWithBorrowing(t *testing.T) {
+	epoch := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	start := epoch.Add(time.Hour)
+	clock := snowflake.NewManualClock(start)
+
+	g, err := snowflake.New(snowflake.WithEpoch(epoch), snowflake.WithClock(clock), snowflake.WithBorrowing(5*time.Millisecond))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// With the clock frozen, 5ms can be borrowed on top of the current one.
+	ids := make([]snowflake.Snowflake, 0, 6*4096)
+	for i := 0; i < 6*4096; i++ {
+		s, err := g.TryGenerate()
+		if err != nil {
+			t.Fatalf("TryGenerate() #%d = %v", i, err)
+		}
+
+		if i > 0 && s <= ids[i-1] {
+			t.Fatalf("Snowflake %d after %d is not increasing", s, ids[i-1])
+		}
+		ids = append(ids, s)
+	}
+
+	if got, want := g.Time(ids[len(ids)-1]), start.Add(5*time.Millisecond); !got.Equal(want) {
+		t.Errorf("last borrowed time = %v, want %v", got, want)
+	}
+
+	if _, err := g.TryGenerate(); !errors.Is(err, snowflake.ErrSequenceExhausted) {
+		t.Errorf("TryGenerate() past the borrowing limit = %v, want ErrSequenceExhausted", err)
+	}
+
+	// The clock catching up partway is not mistaken for it moving backwards.
+	clock.Advance(2 * time.Millisecond)
+	s, err := g.TryGenerate()
+	if err != nil {
+		t.Fatalf("TryGenerate() after the clock moved = %v", err)
+	}
+
+	if got, want := g.Time(s), start.Add(6*time.Millisecond); !got.Equal(want) {
+		t.Errorf("time after the clock moved = %v, want %v", got, want)
+	}
+
+	// Once the clock has caught up, it is followed again.
+	clock.Advance(time.Second)
+	if s := g.Generate(); !g.Time(s).Equal(clock.Now()) || g.Deconstruct(s).Sequence != 0 {
+		t.Errorf("Generate() after catching up = %+v, want time %v and sequence 0", g.Deconstruct(s), clock.Now())
+	}
+}
+
+func TestWithBorrowingClockBackwards(t *testing.T) {
+	g, _ := backwardsGenerator(t, 2*time.Millisecond,
+		snowflake.WithBorrowing(5*time.Millisecond),
+		snowflake.WithClockBackwardsPolicy(snowflake.PolicyError))
+
+	if _, err := g.TryGenerate(); !errors.Is(err, snowflake.ErrClockBackwards) {
+		t.Errorf("TryGenerate() = %v, want ErrClockBackwards", err)
+	}
+}
+
+func TestWithBorrowingInvalid(t *testing.T) {
+	if _, err := snowflake.New(snowflake.WithBorrowing(0)); err == nil {
+		t.Error("New(WithBorrowing(0)) succeeded, want error")
+	}
+
+	if _, err := snowflake.New(snowflake.WithBorrowing(time.Millisecond), snowflake.WithLockFree()); err == nil {
+		t.Error("New with borrowing and lock-free mode succeeded, want error")
+	}
+}
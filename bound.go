@@ -0,0 +1,82 @@
+// MIT License
+
+// Copyright (c) 2022 Project-Sparrow
+// Copyright (c) 2023 Kelwing <kelwing@kelnet.org>
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package snowflake
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Bound is a Snowflake that carries the epoch it was minted with,
+// so its time component can be decoded without relying on Init.
+type Bound struct {
+	Snowflake Snowflake
+	Epoch     time.Time
+}
+
+// Bind binds a Snowflake to the epoch it was minted with.
+func Bind(s Snowflake, epoch time.Time) Bound {
+	return Bound{
+		Snowflake: s,
+		Epoch:     epoch,
+	}
+}
+
+// Time returns the time component of the Snowflake relative to the bound epoch.
+func (b Bound) Time() time.Time {
+	return b.Snowflake.TimeWithEpoch(b.Epoch)
+}
+
+// Age returns how long ago the Snowflake was created.
+// Snowflakes with a timestamp in the future return a negative duration.
+func (b Bound) Age() time.Duration {
+	return currentTime().Sub(b.Time())
+}
+
+// Before reports whether the Snowflake was created before t.
+func (b Bound) Before(t time.Time) bool {
+	return b.Time().Before(t)
+}
+
+// After reports whether the Snowflake was created after t.
+func (b Bound) After(t time.Time) bool {
+	return b.Time().After(t)
+}
+
+// String implements fmt.Stringer interface
+func (b Bound) String() string {
+	return b.Snowflake.String()
+}
+
+// MarshalJSON implements json.Marshaler interface.
+// The epoch is not encoded, only the plain Snowflake.
+func (b Bound) MarshalJSON() ([]byte, error) {
+	return b.Snowflake.MarshalJSON()
+}
+
+// UnmarshalJSON implements json.Unmarshaler interface.
+// The epoch is left untouched, so it can be set before decoding.
+func (b *Bound) UnmarshalJSON(data []byte) error {
+	return json.Unmarshal(data, &b.Snowflake)
+}
@@ -0,0 +1,85 @@
+// MIT License
+
+// Copyright (c) 2022 Project-Sparrow
+// Copyright (c) 2023 Kelwing <kelwing@kelnet.org>
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package snowflake_test
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"wumpgo.dev/snowflake"
+)
+
+func TestBound(t *testing.T) {
+	discord := time.Date(2015, 1, 1, 0, 0, 0, 0, time.UTC)
+	snowflake.Init(time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC), 0, 0)
+
+	b := snowflake.Bind(175928847299117063, discord)
+
+	created := time.Date(2016, 4, 30, 11, 18, 25, 796000000, time.UTC)
+	if !b.Time().Equal(created) {
+		t.Errorf("Time() = %v, want %v", b.Time(), created)
+	}
+
+	restore := snowflake.FreezeClock(created.Add(time.Hour))
+	defer restore()
+
+	if b.Age() != time.Hour {
+		t.Errorf("Age() = %v, want %v", b.Age(), time.Hour)
+	}
+
+	if !b.Before(created.Add(time.Millisecond)) || b.Before(created) {
+		t.Error("Before() disagrees with the bound epoch")
+	}
+
+	if !b.After(created.Add(-time.Millisecond)) || b.After(created) {
+		t.Error("After() disagrees with the bound epoch")
+	}
+
+	if b.String() != "175928847299117063" {
+		t.Errorf("String() = %q", b.String())
+	}
+}
+
+func TestBoundJSON(t *testing.T) {
+	discord := time.Date(2015, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	data, err := json.Marshal(snowflake.Bind(175928847299117063, discord))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if string(data) != `"175928847299117063"` {
+		t.Errorf("MarshalJSON() = %s", data)
+	}
+
+	b := snowflake.Bind(0, discord)
+	if err := json.Unmarshal(data, &b); err != nil {
+		t.Fatal(err)
+	}
+
+	if b.Snowflake != 175928847299117063 || !b.Epoch.Equal(discord) {
+		t.Errorf("UnmarshalJSON() = %+v", b)
+	}
+}
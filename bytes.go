@@ -0,0 +1,87 @@
+// MIT License
+
+// Copyright (c) 2022 Project-Sparrow
+// Copyright (c) 2023 Kelwing <kelwing@kelnet.org>
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package snowflake
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// ByteLen is the length of the binary form of a Snowflake.
+const ByteLen = 8
+
+// ErrByteLength is returned by FromBytes for input that is not ByteLen long.
+var ErrByteLength = errors.New("snowflake bytes have the wrong length")
+
+// Bytes returns the Snowflake as 8 big-endian bytes. Comparing two results
+// byte by byte orders them the same as the Snowflakes, so they can be used
+// as sorted keys. It returns an array so that it does not allocate.
+func (s Snowflake) Bytes() [ByteLen]byte {
+	var b [ByteLen]byte
+	binary.BigEndian.PutUint64(b[:], uint64(s))
+	return b
+}
+
+// PutBytes writes the Snowflake as 8 big-endian bytes into the start of dst.
+// It returns io.ErrShortBuffer if dst is shorter than ByteLen.
+func (s Snowflake) PutBytes(dst []byte) error {
+	if len(dst) < ByteLen {
+		return fmt.Errorf("%w: %d bytes, want at least %d", io.ErrShortBuffer, len(dst), ByteLen)
+	}
+
+	binary.BigEndian.PutUint64(dst, uint64(s))
+	return nil
+}
+
+// FromBytes decodes a Snowflake from the 8 big-endian bytes written by Bytes
+// or PutBytes. It returns an error wrapping ErrByteLength if b is not
+// exactly ByteLen long.
+func FromBytes(b []byte) (Snowflake, error) {
+	if len(b) != ByteLen {
+		return 0, fmt.Errorf("%w: %d bytes, want %d", ErrByteLength, len(b), ByteLen)
+	}
+
+	return Snowflake(binary.BigEndian.Uint64(b)), nil
+}
+
+// MarshalBinary implements encoding.BinaryMarshaler interface
+// using the 8 big-endian bytes returned by Bytes.
+func (s Snowflake) MarshalBinary() ([]byte, error) {
+	b := s.Bytes()
+	return b[:], nil
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler interface.
+// It returns an error wrapping ErrByteLength if data is not ByteLen long.
+func (s *Snowflake) UnmarshalBinary(data []byte) error {
+	v, err := FromBytes(data)
+	if err != nil {
+		return err
+	}
+
+	*s = v
+	return nil
+}
@@ -0,0 +1,118 @@
+// MIT License
+
+// Copyright (c) 2022 Project-Sparrow
+// Copyright (c) 2023 Kelwing <kelwing@kelnet.org>
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package snowflake_test
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"math"
+	"math/rand"
+	"slices"
+	"testing"
+
+	"wumpgo.dev/snowflake"
+)
+
+func TestBytes(t *testing.T) {
+	for _, tt := range []struct {
+		s    snowflake.Snowflake
+		want [8]byte
+	}{
+		{0, [8]byte{}},
+		{1, [8]byte{7: 1}},
+		{175928847299117063, [8]byte{0x02, 0x71, 0x06, 0x5a, 0xc1, 0x02, 0x00, 0x07}},
+		{math.MaxUint64, [8]byte{0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff}},
+	} {
+		if got := tt.s.Bytes(); got != tt.want {
+			t.Errorf("Bytes(%d) = %x, want %x", tt.s, got, tt.want)
+		}
+
+		buf := []byte{0xaa, 0xaa, 0xaa, 0xaa, 0xaa, 0xaa, 0xaa, 0xaa, 0xaa}
+		if err := tt.s.PutBytes(buf); err != nil {
+			t.Errorf("PutBytes(%d) = %v", tt.s, err)
+		}
+
+		if !bytes.Equal(buf[:8], tt.want[:]) || buf[8] != 0xaa {
+			t.Errorf("PutBytes(%d) wrote %x, want %x followed by aa", tt.s, buf, tt.want)
+		}
+
+		if got, err := snowflake.FromBytes(tt.want[:]); err != nil || got != tt.s {
+			t.Errorf("FromBytes(%x) = %d, %v, want %d", tt.want, got, err, tt.s)
+		}
+	}
+}
+
+func TestBytesInvalid(t *testing.T) {
+	if err := snowflake.Snowflake(1).PutBytes(make([]byte, 7)); !errors.Is(err, io.ErrShortBuffer) {
+		t.Errorf("PutBytes into 7 bytes = %v, want io.ErrShortBuffer", err)
+	}
+
+	for _, n := range []int{0, 7, 9, 16} {
+		if got, err := snowflake.FromBytes(make([]byte, n)); !errors.Is(err, snowflake.ErrByteLength) {
+			t.Errorf("FromBytes(%d bytes) = %d, %v, want ErrByteLength", n, got, err)
+		}
+	}
+
+	if got, err := snowflake.FromBytes(nil); !errors.Is(err, snowflake.ErrByteLength) {
+		t.Errorf("FromBytes(nil) = %d, %v, want ErrByteLength", got, err)
+	}
+}
+
+func TestBytesSortOrder(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	sample := []snowflake.Snowflake{0, 1, 255, 256, math.MaxUint64}
+	for i := 0; i < 1000; i++ {
+		sample = append(sample, snowflake.Snowflake(r.Uint64()>>r.Intn(64)))
+	}
+	r.Shuffle(len(sample), func(i, j int) { sample[i], sample[j] = sample[j], sample[i] })
+
+	keys := make([][]byte, len(sample))
+	for i, s := range sample {
+		b := s.Bytes()
+		keys[i] = b[:]
+	}
+
+	slices.SortFunc(keys, bytes.Compare)
+	slices.Sort(sample)
+
+	for i, s := range sample {
+		if got, _ := snowflake.FromBytes(keys[i]); got != s {
+			t.Fatalf("sorted key %d decodes to %d, want %d", i, got, s)
+		}
+	}
+}
+
+func TestBytesAllocs(t *testing.T) {
+	s := snowflake.Snowflake(175928847299117063)
+	buf := make([]byte, 8)
+
+	if n := testing.AllocsPerRun(100, func() {
+		b := s.Bytes()
+		_ = s.PutBytes(buf)
+		_, _ = snowflake.FromBytes(b[:])
+	}); n != 0 {
+		t.Errorf("Bytes, PutBytes and FromBytes allocate %v times, want 0", n)
+	}
+}
@@ -0,0 +1,99 @@
+// MIT License
+
+// Copyright (c) 2022 Project-Sparrow
+// Copyright (c) 2023 Kelwing <kelwing@kelnet.org>
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package snowflake
+
+import "errors"
+
+// ErrChecksum reports that the check digit of a string from StringChecked
+// does not match its other digits.
+var ErrChecksum = errors.New("checksum mismatch")
+
+// dammTable is the quasigroup of the Damm algorithm, as published by
+// H. Michael Damm and reproduced on Wikipedia.
+var dammTable = [10][10]byte{
+	{0, 3, 1, 7, 5, 9, 8, 6, 4, 2},
+	{7, 0, 9, 2, 1, 5, 4, 8, 6, 3},
+	{4, 2, 0, 6, 8, 7, 1, 3, 5, 9},
+	{1, 7, 5, 0, 9, 8, 3, 4, 2, 6},
+	{6, 1, 2, 3, 0, 4, 5, 9, 7, 8},
+	{3, 6, 7, 4, 2, 0, 9, 5, 8, 1},
+	{5, 8, 6, 9, 7, 2, 0, 1, 3, 4},
+	{8, 9, 4, 5, 3, 6, 2, 0, 1, 7},
+	{9, 4, 3, 8, 6, 1, 7, 2, 0, 5},
+	{2, 5, 8, 1, 4, 3, 6, 7, 9, 0},
+}
+
+// damm returns the Damm check digit of the ASCII digits in b,
+// which is 0 if b ends with its own check digit.
+func damm[T string | []byte](b T) byte {
+	var interim byte
+	for i := 0; i < len(b); i++ {
+		interim = dammTable[interim][b[i]-'0']
+	}
+	return interim
+}
+
+// StringChecked returns the decimal form of the Snowflake followed by a
+// Damm check digit, which catches every single mistyped digit and every
+// swap of two adjacent digits. The check digit is computed by starting with
+// an interim digit of 0 and replacing it, for each decimal digit d from the
+// left, with table[interim][d], where table is the quasigroup of order 10
+// given in Wikipedia's article on the Damm algorithm. The final interim
+// digit is appended, so 572 becomes "5724".
+func (s Snowflake) StringChecked() string {
+	var buf [maxDecimalLen + 1]byte
+	b := s.AppendString(buf[:0])
+	return string(append(b, '0'+damm(b)))
+}
+
+// ParseChecked parses a string returned by StringChecked. It returns a
+// *ParseError wrapping ErrChecksum if the check digit does not match,
+// or ErrSyntax, ErrNegative or ErrOverflow as ParseStrict does.
+func ParseChecked(s string) (Snowflake, error) {
+	if len(s) > maxDecimalLen+1 {
+		return 0, tooLong("ParseChecked", s, maxDecimalLen+1)
+	}
+
+	if len(s) < 2 || s[0] == '0' && len(s) > 2 {
+		return 0, &ParseError{Func: "ParseChecked", Input: s, Err: ErrSyntax}
+	}
+
+	if c := s[len(s)-1]; c < '0' || c > '9' {
+		return 0, &ParseError{Func: "ParseChecked", Input: s, Err: ErrSyntax}
+	}
+
+	v, err := parseDecimal("ParseChecked", s[:len(s)-1])
+	if err != nil {
+		// Report the whole input, including the check digit.
+		perr := err.(*ParseError)
+		perr.Input = s
+		return 0, perr
+	}
+
+	if damm(s) != 0 {
+		return 0, &ParseError{Func: "ParseChecked", Input: s, Err: ErrChecksum}
+	}
+
+	return v, nil
+}
@@ -0,0 +1,133 @@
+// MIT License
+
+// Copyright (c) 2022 Project-Sparrow
+// Copyright (c) 2023 Kelwing <kelwing@kelnet.org>
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package snowflake_test
+
+import (
+	"errors"
+	"math"
+	"strconv"
+	"testing"
+	"testing/quick"
+
+	"wumpgo.dev/snowflake"
+)
+
+func TestStringChecked(t *testing.T) {
+	for _, tt := range []struct {
+		s    snowflake.Snowflake
+		want string
+	}{
+		{572, "5724"}, // the example on Wikipedia's Damm algorithm page
+		{0, "00"},
+		{1, "13"},
+		{9, "92"},
+		{175928847299117063, "1759288472991170635"},
+		{math.MaxUint64, "184467440737095516158"},
+	} {
+		if got := tt.s.StringChecked(); got != tt.want {
+			t.Errorf("StringChecked(%d) = %q, want %q", tt.s, got, tt.want)
+		}
+
+		if got, err := snowflake.ParseChecked(tt.want); err != nil || got != tt.s {
+			t.Errorf("ParseChecked(%q) = %d, %v, want %d", tt.want, got, err, tt.s)
+		}
+	}
+}
+
+func TestParseCheckedInvalid(t *testing.T) {
+	for _, tt := range []struct {
+		in  string
+		err error
+	}{
+		{"", snowflake.ErrSyntax},
+		{"5", snowflake.ErrSyntax},
+		{"5725", snowflake.ErrChecksum},
+		{"5824", snowflake.ErrChecksum}, // mistyped digit
+		{"7524", snowflake.ErrChecksum}, // swapped digits
+		{"572", snowflake.ErrChecksum},  // check digit left off
+		{"05724", snowflake.ErrSyntax},
+		{"572x", snowflake.ErrSyntax},
+		{"57a4", snowflake.ErrSyntax},
+		{"-5724", snowflake.ErrNegative},
+		{"+5724", snowflake.ErrSyntax},
+		{"18446744073709551616" + "0", snowflake.ErrOverflow},
+	} {
+		got, err := snowflake.ParseChecked(tt.in)
+
+		var perr *snowflake.ParseError
+		if !errors.Is(err, tt.err) || !errors.As(err, &perr) || perr.Func != "ParseChecked" || perr.Input != tt.in {
+			t.Errorf("ParseChecked(%q) = %d, %v, want a ParseError wrapping %v", tt.in, got, err, tt.err)
+		}
+	}
+}
+
+// TestParseCheckedCatchesTypos checks that every single-digit error and every
+// swap of adjacent different digits in a checked string is detected.
+func TestParseCheckedCatchesTypos(t *testing.T) {
+	catches := func(v uint64) bool {
+		checked := []byte(snowflake.Snowflake(v).StringChecked())
+
+		for i := range checked {
+			for d := byte('0'); d <= '9'; d++ {
+				if d == checked[i] {
+					continue
+				}
+
+				typo := append([]byte(nil), checked...)
+				typo[i] = d
+				if _, err := snowflake.ParseChecked(string(typo)); err == nil {
+					t.Logf("ParseChecked(%q) accepted a typo of %q", typo, checked)
+					return false
+				}
+			}
+
+			if i+1 < len(checked) && checked[i] != checked[i+1] {
+				swap := append([]byte(nil), checked...)
+				swap[i], swap[i+1] = swap[i+1], swap[i]
+				if _, err := snowflake.ParseChecked(string(swap)); err == nil {
+					t.Logf("ParseChecked(%q) accepted a swap of %q", swap, checked)
+					return false
+				}
+			}
+		}
+
+		return true
+	}
+
+	if err := quick.Check(catches, nil); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestStringCheckedRoundTrip(t *testing.T) {
+	roundTrip := func(v uint64) bool {
+		checked := snowflake.Snowflake(v).StringChecked()
+		got, err := snowflake.ParseChecked(checked)
+		return err == nil && got == snowflake.Snowflake(v) && checked[:len(checked)-1] == strconv.FormatUint(v, 10)
+	}
+
+	if err := quick.Check(roundTrip, nil); err != nil {
+		t.Error(err)
+	}
+}
@@ -0,0 +1,120 @@
+// MIT License
+
+// Copyright (c) 2022 Project-Sparrow
+// Copyright (c) 2023 Kelwing <kelwing@kelnet.org>
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package snowflake
+
+import (
+	"sync"
+	"time"
+)
+
+// Clock tells a Generator the current time.
+//
+// If a Clock also has a Sleep(time.Duration) method, it is used
+// instead of time.Sleep while waiting for the clock to advance.
+//
+// If a Clock also has a Monotonic() time.Duration method, reporting time
+// elapsed since an arbitrary fixed point that never goes backwards,
+// the Generator advances its timestamps by it rather than by the wall clock,
+// so steps of the wall clock backwards don't affect ordering.
+type Clock interface {
+	Now() time.Time
+}
+
+// sleeper is implemented by Clocks that control how waiting is done.
+type sleeper interface {
+	Sleep(d time.Duration)
+}
+
+// monotonic is implemented by Clocks with a monotonic reading.
+type monotonic interface {
+	Monotonic() time.Duration
+}
+
+// processStart anchors the monotonic reading of systemClock.
+var processStart = time.Now()
+
+// systemClock is the default Clock, backed by time.Now.
+type systemClock struct{}
+
+func (systemClock) Now() time.Time {
+	return time.Now()
+}
+
+func (systemClock) Monotonic() time.Duration {
+	return time.Since(processStart)
+}
+
+// readClock reads the wall clock of c, and its monotonic reading if it has one.
+func readClock(c Clock) (wall time.Time, mono time.Duration, ok bool) {
+	wall = c.Now()
+	if m, ok := c.(monotonic); ok {
+		return wall, m.Monotonic(), true
+	}
+
+	return wall, 0, false
+}
+
+// sleepOn waits for d using c's Sleep method if it has one.
+func sleepOn(c Clock, d time.Duration) {
+	if s, ok := c.(sleeper); ok {
+		s.Sleep(d)
+		return
+	}
+	time.Sleep(d)
+}
+
+// ManualClock is a Clock that only moves when told to,
+// for writing deterministic tests.
+// A Generator waiting on a ManualClock blocks until it is moved
+// forward from another goroutine.
+type ManualClock struct {
+	mtx sync.Mutex
+	t   time.Time
+}
+
+// NewManualClock creates a ManualClock set to t.
+func NewManualClock(t time.Time) *ManualClock {
+	return &ManualClock{t: t}
+}
+
+// Now implements Clock interface
+func (c *ManualClock) Now() time.Time {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	return c.t
+}
+
+// Set moves the clock to t, which may be in the past.
+func (c *ManualClock) Set(t time.Time) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	c.t = t
+}
+
+// Advance moves the clock forward by d, or backwards if d is negative.
+func (c *ManualClock) Advance(d time.Duration) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	c.t = c.t.Add(d)
+}
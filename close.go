@@ -0,0 +1,160 @@
+// MIT License
+
+// Copyright (c) 2022 Project-Sparrow
+// Copyright (c) 2023 Kelwing <kelwing@kelnet.org>
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package snowflake
+
+import (
+	"errors"
+	"slices"
+	"sync"
+	"sync/atomic"
+)
+
+// ErrClosed is returned when generating with a Generator after Close.
+var ErrClosed = errors.New("generator is closed")
+
+// closer holds what a Generator releases on Close.
+type closer struct {
+	closed atomic.Bool
+	once   sync.Once
+	err    error
+
+	// calls is read-locked by generation calls in progress,
+	// so Close can wait for them to finish.
+	calls sync.RWMutex
+
+	// funcs are pointers so that a registration can be told apart
+	// when it is removed.
+	mtx   sync.Mutex
+	funcs []*func() error
+}
+
+// WithSaveStateOnClose makes Close pass the Generator's State to save,
+// so it can be persisted and passed to RestoreState on the next start.
+func WithSaveStateOnClose(save func(state []byte) error) Option {
+	return func(g *Generator) error {
+		if err := g.once("save state on close"); err != nil {
+			return err
+		}
+
+		if save == nil {
+			return errors.New("save state function is nil")
+		}
+
+		g.onClose(func() error {
+			state, err := g.State()
+			if err != nil {
+				return err
+			}
+
+			return save(state)
+		})
+
+		return nil
+	}
+}
+
+// Close stops the background work tied to the Generator, such as Pools
+// created from it, and saves its state if WithSaveStateOnClose was used.
+// Calls generating Snowflakes that are already in progress finish normally,
+// including ones waiting for the clock, and Close waits for them before
+// stopping anything, so a saved state covers every Snowflake handed out.
+// Calls made once Close has begun fail: GenerateContext, TryGenerate,
+// GenerateAt and ReserveBlock return ErrClosed, and Generate, GenerateN and
+// AppendN panic. Close is safe to call more than once and concurrently with
+// generation; later calls return the same error as the first.
+func (g *Generator) Close() error {
+	g.closer.once.Do(func() {
+		g.closer.closed.Store(true)
+		g.closer.calls.Lock()
+		g.closer.calls.Unlock()
+
+		g.closer.mtx.Lock()
+		funcs := g.closer.funcs
+		g.closer.funcs = nil
+		g.closer.mtx.Unlock()
+
+		var errs []error
+		for i := len(funcs) - 1; i >= 0; i-- {
+			if err := (*funcs[i])(); err != nil {
+				errs = append(errs, err)
+			}
+		}
+
+		g.closer.err = errors.Join(errs...)
+	})
+
+	return g.closer.err
+}
+
+// closed reports whether Close has been called.
+func (g *Generator) closed() bool {
+	return g.closer.closed.Load()
+}
+
+// enter marks a generation call as in progress, so that Close waits for it,
+// or returns ErrClosed if Close has begun. A nil error must be followed by
+// a call to exit, before any hooks are fired.
+func (g *Generator) enter() error {
+	// Checking first keeps calls from queuing behind a waiting Close.
+	if g.closed() {
+		return ErrClosed
+	}
+
+	g.closer.calls.RLock()
+	if g.closed() {
+		g.closer.calls.RUnlock()
+		return ErrClosed
+	}
+
+	return nil
+}
+
+// exit ends a generation call started by enter.
+func (g *Generator) exit() {
+	g.closer.calls.RUnlock()
+}
+
+// onClose registers f to be called by Close, in reverse order of registration,
+// and returns a function unregistering it again.
+// It returns ErrClosed if g is already closed.
+func (g *Generator) onClose(f func() error) (remove func(), err error) {
+	g.closer.mtx.Lock()
+	defer g.closer.mtx.Unlock()
+
+	if g.closed() {
+		return nil, ErrClosed
+	}
+
+	fp := &f
+	g.closer.funcs = append(g.closer.funcs, fp)
+
+	return func() {
+		g.closer.mtx.Lock()
+		defer g.closer.mtx.Unlock()
+
+		if i := slices.Index(g.closer.funcs, fp); i >= 0 {
+			g.closer.funcs = slices.Delete(g.closer.funcs, i, i+1)
+		}
+	}, nil
+}
@@ -0,0 +1,220 @@
+// MIT License
+
+// Copyright (c) 2022 Project-Sparrow
+// Copyright (c) 2023 Kelwing <kelwing@kelnet.org>
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package snowflake_test
+
+import (
+	"context"
+	"errors"
+	"runtime"
+	"sync"
+	"testing"
+	"time"
+
+	"wumpgo.dev/snowflake"
+)
+
+// checkGoroutines fails t if more goroutines are running than before
+// once they have had a moment to exit.
+func checkGoroutines(t *testing.T, before int) {
+	t.Helper()
+
+	deadline := time.Now().Add(time.Second)
+	for runtime.NumGoroutine() > before {
+		if time.Now().After(deadline) {
+			t.Fatalf("%d goroutines running, want %d", runtime.NumGoroutine(), before)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestClose(t *testing.T) {
+	before := runtime.NumGoroutine()
+
+	g, err := snowflake.New(snowflake.WithEpoch(time.Now().Add(-time.Hour)))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 0; i < 3; i++ {
+		if _, err := snowflake.NewPool(g, 16); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := g.Close(); err != nil {
+		t.Fatalf("Close() = %v", err)
+	}
+
+	if err := g.Close(); err != nil {
+		t.Fatalf("second Close() = %v", err)
+	}
+
+	checkGoroutines(t, before)
+
+	if _, err := g.TryGenerate(); !errors.Is(err, snowflake.ErrClosed) {
+		t.Errorf("TryGenerate() = %v, want ErrClosed", err)
+	}
+
+	if _, err := g.GenerateContext(context.Background()); !errors.Is(err, snowflake.ErrClosed) {
+		t.Errorf("GenerateContext() = %v, want ErrClosed", err)
+	}
+
+	if _, err := g.GenerateAt(time.Now()); !errors.Is(err, snowflake.ErrClosed) {
+		t.Errorf("GenerateAt() = %v, want ErrClosed", err)
+	}
+
+	if _, err := snowflake.NewPool(g, 16); !errors.Is(err, snowflake.ErrClosed) {
+		t.Errorf("NewPool() = %v, want ErrClosed", err)
+	}
+
+	defer func() {
+		if r := recover(); r == nil {
+			t.Error("Generate() after Close did not panic")
+		}
+	}()
+	g.Generate()
+}
+
+func TestCloseWhileWaiting(t *testing.T) {
+	epoch := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	clock := snowflake.NewManualClock(epoch.Add(time.Hour))
+
+	g, err := snowflake.New(snowflake.WithEpoch(epoch), snowflake.WithClock(clock))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	last := g.GenerateN(4096)[4095]
+
+	generated := make(chan snowflake.Snowflake)
+	go func() {
+		generated <- g.Generate()
+	}()
+
+	time.Sleep(10 * time.Millisecond)
+
+	closed := make(chan struct{})
+	go func() {
+		g.Close()
+		close(closed)
+	}()
+
+	time.Sleep(10 * time.Millisecond)
+	select {
+	case <-closed:
+		t.Fatal("Close returned while Generate was waiting")
+	default:
+	}
+
+	if _, err := g.TryGenerate(); !errors.Is(err, snowflake.ErrClosed) {
+		t.Errorf("TryGenerate() while closing = %v, want ErrClosed", err)
+	}
+
+	clock.Advance(time.Millisecond)
+
+	select {
+	case s := <-generated:
+		if s <= last {
+			t.Errorf("Generate() = %d, want after %d", s, last)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Generate() still waiting after the clock moved")
+	}
+
+	select {
+	case <-closed:
+	case <-time.After(time.Second):
+		t.Fatal("Close() still waiting after Generate returned")
+	}
+}
+
+func TestCloseConcurrent(t *testing.T) {
+	g, err := snowflake.New(snowflake.WithEpoch(time.Now().Add(-time.Hour)), snowflake.WithShards(4))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			for {
+				if _, err := g.GenerateContext(context.Background()); err != nil {
+					if !errors.Is(err, snowflake.ErrClosed) {
+						t.Errorf("GenerateContext() = %v, want ErrClosed", err)
+					}
+					return
+				}
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			g.Close()
+		}()
+	}
+	wg.Wait()
+}
+
+func TestSaveStateOnClose(t *testing.T) {
+	epoch := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	clock := snowflake.NewManualClock(epoch.Add(time.Hour))
+
+	var saved []byte
+	save := func(state []byte) error {
+		saved = state
+		return nil
+	}
+
+	g, err := snowflake.New(snowflake.WithEpoch(epoch), snowflake.WithClock(clock), snowflake.WithSaveStateOnClose(save))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	last := g.GenerateN(10)[9]
+	g.Close()
+
+	next, err := snowflake.New(snowflake.WithEpoch(epoch), snowflake.WithClock(clock))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := next.RestoreState(saved); err != nil {
+		t.Fatalf("RestoreState() = %v", err)
+	}
+
+	clock.Advance(time.Millisecond)
+	if s := next.Generate(); s <= last {
+		t.Errorf("Generate() after restoring = %d, want after %d", s, last)
+	}
+
+	failing, err := snowflake.New(snowflake.WithSaveStateOnClose(func([]byte) error { return errors.New("disk full") }))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := failing.Close(); err == nil || err.Error() != "disk full" {
+		t.Errorf("Close() = %v, want disk full", err)
+	}
+}
@@ -0,0 +1,261 @@
+// MIT License
+
+// Copyright (c) 2022 Project-Sparrow
+// Copyright (c) 2023 Kelwing <kelwing@kelnet.org>
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+// Package cloudid derives Snowflake worker IDs from the identity of the
+// cloud instance a program runs on, read from the instance metadata service,
+// so an instance keeps its worker ID across restarts.
+//
+// Worker IDs are the low bits of the 64-bit FNV-1a hash of the instance ID,
+// as computed by snowflake.WorkerIDFromString. This will not change between
+// releases. Instances whose IDs share those bits get the same worker ID.
+package cloudid
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"wumpgo.dev/snowflake"
+)
+
+// ErrUnavailable is returned when the instance metadata service cannot be
+// reached, usually because the program is not running on that cloud.
+var ErrUnavailable = errors.New("instance metadata unavailable")
+
+const (
+	// DefaultTimeout bounds each request to the metadata service.
+	DefaultTimeout = 2 * time.Second
+
+	// EC2Endpoint is the address of the EC2 instance metadata service.
+	EC2Endpoint = "http://169.254.169.254"
+
+	// GCEEndpoint is the address of the Compute Engine metadata server.
+	GCEEndpoint = "http://metadata.google.internal"
+)
+
+// Option configures a Source.
+type Option func(*Source) error
+
+// WithHTTPClient makes the Source send requests with c
+// instead of http.DefaultClient.
+func WithHTTPClient(c *http.Client) Option {
+	return func(s *Source) error {
+		if c == nil {
+			return errors.New("HTTP client is nil")
+		}
+
+		s.client = c
+
+		return nil
+	}
+}
+
+// WithEndpoint makes the Source query the metadata service at endpoint,
+// such as "http://169.254.169.254", instead of the provider's default.
+func WithEndpoint(endpoint string) Option {
+	return func(s *Source) error {
+		s.endpoint = strings.TrimSuffix(endpoint, "/")
+		return nil
+	}
+}
+
+// WithTimeout bounds each request to the metadata service by d
+// instead of DefaultTimeout.
+func WithTimeout(d time.Duration) Option {
+	return func(s *Source) error {
+		if d <= 0 {
+			return fmt.Errorf("timeout %v is not positive", d)
+		}
+
+		s.timeout = d
+
+		return nil
+	}
+}
+
+// WithBits sets the width of the worker IDs derived,
+// the 5 bits of snowflake.DefaultLayout by default.
+func WithBits(bits uint8) Option {
+	return func(s *Source) error {
+		if bits == 0 || bits > 8 {
+			return fmt.Errorf("worker ID width %d is not between 1 and 8 bits", bits)
+		}
+
+		s.bits = bits
+
+		return nil
+	}
+}
+
+// Source reads the instance ID from a cloud provider's metadata service.
+// The instance ID is cached after it is first read.
+// A Source is safe for concurrent use.
+type Source struct {
+	name     string
+	endpoint string
+	client   *http.Client
+	timeout  time.Duration
+	bits     uint8
+	fetch    func(ctx context.Context, s *Source) (string, error)
+
+	mtx sync.Mutex
+	id  string
+}
+
+func newSource(name, endpoint string, fetch func(context.Context, *Source) (string, error), opts []Option) (*Source, error) {
+	s := &Source{
+		name:     name,
+		endpoint: endpoint,
+		client:   http.DefaultClient,
+		timeout:  DefaultTimeout,
+		bits:     snowflake.DefaultLayout.WorkerBits,
+		fetch:    fetch,
+	}
+
+	for _, opt := range opts {
+		if err := opt(s); err != nil {
+			return nil, err
+		}
+	}
+
+	return s, nil
+}
+
+// NewEC2 creates a Source reading the instance ID of an EC2 instance
+// through IMDSv2.
+func NewEC2(opts ...Option) (*Source, error) {
+	return newSource("EC2", EC2Endpoint, fetchEC2, opts)
+}
+
+// NewGCE creates a Source reading the instance ID of a Compute Engine instance.
+func NewGCE(opts ...Option) (*Source, error) {
+	return newSource("GCE", GCEEndpoint, fetchGCE, opts)
+}
+
+// InstanceID returns the ID of the instance, reading it from the metadata
+// service the first time. It returns an error wrapping ErrUnavailable if
+// the metadata service cannot be reached.
+func (s *Source) InstanceID(ctx context.Context) (string, error) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	if s.id != "" {
+		return s.id, nil
+	}
+
+	id, err := s.fetch(ctx, s)
+	if err != nil {
+		return "", err
+	}
+
+	if id == "" {
+		return "", fmt.Errorf("%s metadata returned an empty instance ID", s.name)
+	}
+
+	s.id = id
+
+	return id, nil
+}
+
+// WorkerID derives a worker ID from the ID of the instance.
+func (s *Source) WorkerID(ctx context.Context) (uint8, error) {
+	id, err := s.InstanceID(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	return snowflake.WorkerIDFromString(id, s.bits)
+}
+
+var (
+	ec2 = must(NewEC2())
+	gce = must(NewGCE())
+)
+
+// EC2WorkerID derives a worker ID for snowflake.DefaultLayout from the ID of
+// the EC2 instance, which is cached after it is first read.
+func EC2WorkerID(ctx context.Context) (uint8, error) {
+	return ec2.WorkerID(ctx)
+}
+
+// GCEWorkerID derives a worker ID for snowflake.DefaultLayout from the ID of
+// the Compute Engine instance, which is cached after it is first read.
+func GCEWorkerID(ctx context.Context) (uint8, error) {
+	return gce.WorkerID(ctx)
+}
+
+func fetchEC2(ctx context.Context, s *Source) (string, error) {
+	token, err := s.get(ctx, http.MethodPut, "/latest/api/token", "X-aws-ec2-metadata-token-ttl-seconds", "60")
+	if err != nil {
+		return "", err
+	}
+
+	return s.get(ctx, http.MethodGet, "/latest/meta-data/instance-id", "X-aws-ec2-metadata-token", token)
+}
+
+func fetchGCE(ctx context.Context, s *Source) (string, error) {
+	return s.get(ctx, http.MethodGet, "/computeMetadata/v1/instance/id", "Metadata-Flavor", "Google")
+}
+
+// get requests path from the metadata service with the given header
+// and returns the trimmed response body.
+func (s *Source) get(ctx context.Context, method, path, header, value string) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, s.timeout)
+	defer cancel()
+
+	url := s.endpoint + path
+	req, err := http.NewRequestWithContext(ctx, method, url, nil)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set(header, value)
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("%w: %s metadata at %s: %v", ErrUnavailable, s.name, s.endpoint, err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 4096))
+	if err != nil {
+		return "", fmt.Errorf("reading %s: %w", url, err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("%s %s: %s", method, url, resp.Status)
+	}
+
+	return strings.TrimSpace(string(body)), nil
+}
+
+func must(s *Source, err error) *Source {
+	if err != nil {
+		panic(err)
+	}
+	return s
+}
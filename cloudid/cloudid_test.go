@@ -0,0 +1,208 @@
+// MIT License
+
+// Copyright (c) 2022 Project-Sparrow
+// Copyright (c) 2023 Kelwing <kelwing@kelnet.org>
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package cloudid_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"wumpgo.dev/snowflake/cloudid"
+)
+
+// ec2Server serves the IMDSv2 instance ID id.
+func ec2Server(t *testing.T, id string) (*httptest.Server, *atomic.Int64) {
+	t.Helper()
+
+	var hits atomic.Int64
+	mux := http.NewServeMux()
+	mux.HandleFunc("/latest/api/token", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPut || r.Header.Get("X-aws-ec2-metadata-token-ttl-seconds") == "" {
+			http.Error(w, "bad token request", http.StatusBadRequest)
+			return
+		}
+		w.Write([]byte("secret-token"))
+	})
+	mux.HandleFunc("/latest/meta-data/instance-id", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-aws-ec2-metadata-token") != "secret-token" {
+			http.Error(w, "missing token", http.StatusUnauthorized)
+			return
+		}
+		hits.Add(1)
+		w.Write([]byte(id))
+	})
+
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+
+	return srv, &hits
+}
+
+// gceServer serves the Compute Engine instance ID id.
+func gceServer(t *testing.T, id string) *httptest.Server {
+	t.Helper()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/computeMetadata/v1/instance/id" || r.Header.Get("Metadata-Flavor") != "Google" {
+			http.Error(w, "bad request", http.StatusForbidden)
+			return
+		}
+		w.Write([]byte(id + "\n"))
+	}))
+	t.Cleanup(srv.Close)
+
+	return srv
+}
+
+// These values are pinned: changing them would reassign the worker IDs
+// of every instance.
+var pinned = []struct {
+	id   string
+	id5  uint8
+	id8  uint8
+	ec2  bool
+	name string
+}{
+	{"i-0123456789abcdef0", 21, 21, true, "EC2"},
+	{"i-1234567890abcdef0", 23, 87, true, "EC2"},
+	{"4520031799277581759", 30, 62, false, "GCE"},
+}
+
+func TestWorkerID(t *testing.T) {
+	ctx := context.Background()
+
+	for _, tt := range pinned {
+		var endpoint string
+		var newSource func(...cloudid.Option) (*cloudid.Source, error)
+		if tt.ec2 {
+			srv, _ := ec2Server(t, tt.id)
+			endpoint, newSource = srv.URL, cloudid.NewEC2
+		} else {
+			endpoint, newSource = gceServer(t, tt.id).URL, cloudid.NewGCE
+		}
+
+		for bits, want := range map[uint8]uint8{5: tt.id5, 8: tt.id8} {
+			s, err := newSource(cloudid.WithEndpoint(endpoint+"/"), cloudid.WithBits(bits))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if got, err := s.InstanceID(ctx); err != nil || got != tt.id {
+				t.Errorf("%s InstanceID() = %q, %v, want %q", tt.name, got, err, tt.id)
+			}
+
+			if got, err := s.WorkerID(ctx); err != nil || got != want {
+				t.Errorf("%s WorkerID() for %q with %d bits = %d, %v, want %d", tt.name, tt.id, bits, got, err, want)
+			}
+		}
+	}
+}
+
+func TestInstanceIDCached(t *testing.T) {
+	srv, hits := ec2Server(t, "i-0123456789abcdef0")
+
+	s, err := cloudid.NewEC2(cloudid.WithEndpoint(srv.URL), cloudid.WithHTTPClient(srv.Client()))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 0; i < 3; i++ {
+		if _, err := s.WorkerID(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if hits.Load() != 1 {
+		t.Errorf("instance ID fetched %d times, want once", hits.Load())
+	}
+}
+
+func TestUnavailable(t *testing.T) {
+	srv := httptest.NewServer(http.NotFoundHandler())
+	endpoint := srv.URL
+	srv.Close()
+
+	s, err := cloudid.NewGCE(cloudid.WithEndpoint(endpoint))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := s.WorkerID(context.Background()); !errors.Is(err, cloudid.ErrUnavailable) {
+		t.Errorf("WorkerID() with no metadata service = %v, want ErrUnavailable", err)
+	}
+}
+
+func TestTimeout(t *testing.T) {
+	block := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-block
+	}))
+	defer srv.Close()
+	defer close(block)
+
+	s, err := cloudid.NewGCE(cloudid.WithEndpoint(srv.URL), cloudid.WithTimeout(50*time.Millisecond))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	start := time.Now()
+	if _, err := s.WorkerID(context.Background()); !errors.Is(err, cloudid.ErrUnavailable) {
+		t.Errorf("WorkerID() with a hanging metadata service = %v, want ErrUnavailable", err)
+	}
+
+	if d := time.Since(start); d > 5*time.Second {
+		t.Errorf("WorkerID() took %v despite the 50ms timeout", d)
+	}
+}
+
+func TestErrorStatus(t *testing.T) {
+	srv := gceServer(t, "4520031799277581759")
+
+	// Without the EC2 token endpoint the token request fails.
+	s, err := cloudid.NewEC2(cloudid.WithEndpoint(srv.URL))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = s.WorkerID(context.Background())
+	if err == nil || errors.Is(err, cloudid.ErrUnavailable) {
+		t.Errorf("WorkerID() with an error status = %v, want a non-ErrUnavailable error", err)
+	}
+
+	if _, err := s.WorkerID(context.Background()); err == nil {
+		t.Error("failed WorkerID() was cached")
+	}
+}
+
+func TestInvalidOptions(t *testing.T) {
+	for _, opt := range []cloudid.Option{cloudid.WithBits(0), cloudid.WithBits(9), cloudid.WithTimeout(0), cloudid.WithHTTPClient(nil)} {
+		if _, err := cloudid.NewEC2(opt); err == nil {
+			t.Error("NewEC2 succeeded, want error")
+		}
+	}
+}
@@ -0,0 +1,128 @@
+// MIT License
+
+// Copyright (c) 2022 Project-Sparrow
+// Copyright (c) 2023 Kelwing <kelwing@kelnet.org>
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package snowflake
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// WithCoarseClock makes the Generator read the clock from a background
+// goroutine refreshing it every time unit, or every millisecond for longer
+// units, instead of on every Snowflake, which saves the cost of reading the
+// clock on hosts where it is slow. Timestamps may lag the clock by up to one
+// refresh. When the sequence is exhausted the clock is read directly,
+// so waits are not lengthened. Close stops the goroutine.
+func WithCoarseClock() Option {
+	return func(g *Generator) error {
+		if err := g.once("coarse clock"); err != nil {
+			return err
+		}
+
+		g.coarse = new(coarseClock)
+
+		return nil
+	}
+}
+
+// coarseReading is a reading of a Clock cached by coarseClock.
+type coarseReading struct {
+	wall    time.Time
+	mono    time.Duration
+	hasMono bool
+}
+
+// coarseClock caches readings of a Clock.
+// Readings are taken under mtx so the cache never goes back in time
+// compared to the Clock.
+type coarseClock struct {
+	clock Clock
+	mtx   sync.Mutex
+	cur   atomic.Pointer[coarseReading]
+	stop  chan struct{}
+	done  chan struct{}
+}
+
+// startCoarse starts refreshing the coarse clock of g, if it has one.
+func (g *Generator) startCoarse() {
+	c := g.coarse
+	if c == nil {
+		return
+	}
+
+	c.clock = g.clock
+	c.stop = make(chan struct{})
+	c.done = make(chan struct{})
+	c.refresh()
+
+	go c.run(min(g.unit, time.Millisecond))
+
+	g.onClose(func() error {
+		close(c.stop)
+		<-c.done
+		return nil
+	})
+}
+
+// run refreshes c every period until stopped.
+func (c *coarseClock) run(period time.Duration) {
+	defer close(c.done)
+
+	t := time.NewTicker(period)
+	defer t.Stop()
+
+	for {
+		select {
+		case <-c.stop:
+			return
+		case <-t.C:
+			c.refresh()
+		}
+	}
+}
+
+// refresh reads the clock into the cache. It does nothing on a nil coarseClock.
+func (c *coarseClock) refresh() {
+	if c == nil {
+		return
+	}
+
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	wall, mono, ok := readClock(c.clock)
+	c.cur.Store(&coarseReading{wall: wall, mono: mono, hasMono: ok})
+}
+
+// read returns the reading of the clock the Generator should use:
+// the cached one with WithCoarseClock, or a fresh one.
+func (g *Generator) read() (wall time.Time, mono time.Duration, ok bool) {
+	if g.coarse != nil {
+		r := g.coarse.cur.Load()
+		return r.wall, r.mono, r.hasMono
+	}
+
+	return readClock(g.clock)
+}
@@ -0,0 +1,120 @@
+// MIT License
+
+// Copyright (c) 2022 Project-Sparrow
+// Copyright (c) 2023 Kelwing <kelwing@kelnet.org>
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package snowflake_test
+
+import (
+	"runtime"
+	"sync"
+	"testing"
+	"time"
+
+	"wumpgo.dev/snowflake"
+)
+
+func TestWithCoarseClockUnique(t *testing.T) {
+	before := runtime.NumGoroutine()
+
+	g, err := snowflake.New(snowflake.WithEpoch(time.Now().Add(-time.Hour)), snowflake.WithCoarseClock())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	const goroutines = 16
+	const n = 5000
+
+	results := make([][]snowflake.Snowflake, goroutines)
+	var wg sync.WaitGroup
+	for i := range results {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			ids := make([]snowflake.Snowflake, n)
+			for j := range ids {
+				ids[j] = g.Generate()
+				if j > 0 && ids[j] <= ids[j-1] {
+					t.Errorf("Snowflake %d after %d is not increasing", ids[j], ids[j-1])
+					return
+				}
+			}
+			results[i] = ids
+		}(i)
+	}
+	wg.Wait()
+
+	seen := make(map[snowflake.Snowflake]bool, goroutines*n)
+	for _, ids := range results {
+		for _, s := range ids {
+			if seen[s] {
+				t.Fatalf("duplicate Snowflake %d", s)
+			}
+			seen[s] = true
+		}
+	}
+
+	if err := g.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	checkGoroutines(t, before)
+}
+
+func TestWithCoarseClockExhausted(t *testing.T) {
+	epoch := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	clock := snowflake.NewFakeClock(epoch.Add(time.Hour))
+
+	g, err := snowflake.New(snowflake.WithEpoch(epoch), snowflake.WithClock(clock), snowflake.WithCoarseClock())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer g.Close()
+
+	// The clock only moves when slept on, so the Generator has to read it
+	// directly rather than wait for the cache to be refreshed.
+	ids := g.GenerateN(3 * 4096)
+	if got, want := g.Time(ids[len(ids)-1]), epoch.Add(time.Hour+2*time.Millisecond); !got.Equal(want) {
+		t.Errorf("last time = %v, want %v", got, want)
+	}
+}
+
+// wideLayout has enough sequence bits that benchmarks are not bound
+// by the number of Snowflakes per millisecond.
+var wideLayout = snowflake.Layout{TimestampBits: 38, WorkerBits: 5, ProcessBits: 5, SequenceBits: 16}
+
+func benchClock(b *testing.B, opts ...snowflake.Option) {
+	opts = append(opts, snowflake.WithEpoch(time.Now().Add(-time.Hour)), snowflake.WithLayout(wideLayout))
+
+	g, err := snowflake.New(opts...)
+	if err != nil {
+		b.Fatal(err)
+	}
+	defer g.Close()
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		g.Generate()
+	}
+}
+
+func BenchmarkGenerateSystemClock(b *testing.B) { benchClock(b) }
+func BenchmarkGenerateCoarseClock(b *testing.B) { benchClock(b, snowflake.WithCoarseClock()) }
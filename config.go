@@ -0,0 +1,171 @@
+// MIT License
+
+// Copyright (c) 2022 Project-Sparrow
+// Copyright (c) 2023 Kelwing <kelwing@kelnet.org>
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package snowflake
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// Config describes a Generator in a form that can be stored as JSON.
+// Zero fields take the defaults of New. Durations are strings
+// accepted by time.ParseDuration, such as "10ms".
+// Clocks and hooks cannot be expressed in a Config.
+type Config struct {
+	Epoch     time.Time `json:"epoch"`
+	WorkerID  uint16    `json:"worker_id,omitempty"`
+	ProcessID uint16    `json:"process_id,omitempty"`
+	Layout    *Layout   `json:"layout,omitempty"`
+	TimeUnit  string    `json:"time_unit,omitempty"`
+
+	ClockBackwardsPolicy Policy `json:"clock_backwards_policy,omitempty"`
+	MaxBackwardsWait     string `json:"max_backwards_wait,omitempty"`
+
+	Signed63Bit         bool   `json:"signed_63_bit,omitempty"`
+	Shards              int    `json:"shards,omitempty"`
+	LockFree            bool   `json:"lock_free,omitempty"`
+	Borrowing           string `json:"borrowing,omitempty"`
+	RandomSequenceStart bool   `json:"random_sequence_start,omitempty"`
+}
+
+// UnmarshalJSON implements json.Unmarshaler interface
+// and rejects unknown fields.
+func (c *Config) UnmarshalJSON(b []byte) error {
+	type plain Config
+
+	dec := json.NewDecoder(bytes.NewReader(b))
+	dec.DisallowUnknownFields()
+
+	var p plain
+	if err := dec.Decode(&p); err != nil {
+		return fmt.Errorf("invalid generator config: %w", err)
+	}
+
+	*c = Config(p)
+
+	return nil
+}
+
+// Validate reports whether a Generator can be created from c,
+// returning the error NewFromConfig would.
+func (c Config) Validate() error {
+	_, err := NewFromConfig(c)
+	return err
+}
+
+// NewFromConfig creates a Generator configured by c.
+func NewFromConfig(c Config) (*Generator, error) {
+	opts, err := c.options()
+	if err != nil {
+		return nil, err
+	}
+
+	return New(opts...)
+}
+
+// Config returns the effective configuration of the Generator,
+// with defaults filled in.
+func (g *Generator) Config() Config {
+	l := g.layout
+
+	c := Config{
+		Epoch:                g.epoch,
+		WorkerID:             g.workerID,
+		ProcessID:            g.processID,
+		Layout:               &l,
+		TimeUnit:             g.unit.String(),
+		ClockBackwardsPolicy: g.policy,
+		MaxBackwardsWait:     g.maxWait.String(),
+		Signed63Bit:          g.signed,
+		Shards:               g.shardCount,
+		LockFree:             g.lockFree,
+		RandomSequenceStart:  g.randomStart,
+	}
+
+	if g.maxBorrow > 0 {
+		c.Borrowing = g.maxBorrow.String()
+	}
+
+	return c
+}
+
+// options converts c into Options for New.
+func (c Config) options() ([]Option, error) {
+	opts := []Option{
+		WithWorkerID(c.WorkerID),
+		WithProcessID(c.ProcessID),
+		WithClockBackwardsPolicy(c.ClockBackwardsPolicy),
+	}
+
+	if !c.Epoch.IsZero() {
+		opts = append(opts, WithEpoch(c.Epoch))
+	}
+
+	if c.Layout != nil {
+		opts = append(opts, WithLayout(*c.Layout))
+	}
+
+	durations := []struct {
+		name  string
+		value string
+		opt   func(time.Duration) Option
+	}{
+		{"time_unit", c.TimeUnit, WithTimeUnit},
+		{"max_backwards_wait", c.MaxBackwardsWait, WithMaxBackwardsWait},
+		{"borrowing", c.Borrowing, WithBorrowing},
+	}
+
+	for _, d := range durations {
+		if d.value == "" {
+			continue
+		}
+
+		v, err := time.ParseDuration(d.value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s: %w", d.name, err)
+		}
+
+		opts = append(opts, d.opt(v))
+	}
+
+	if c.Signed63Bit {
+		opts = append(opts, WithSigned63Bit())
+	}
+
+	if c.Shards != 0 {
+		opts = append(opts, WithShards(c.Shards))
+	}
+
+	if c.LockFree {
+		opts = append(opts, WithLockFree())
+	}
+
+	if c.RandomSequenceStart {
+		opts = append(opts, WithRandomSequenceStart())
+	}
+
+	return opts, nil
+}
@@ -0,0 +1,139 @@
+// MIT License
+
+// Copyright (c) 2022 Project-Sparrow
+// Copyright (c) 2023 Kelwing <kelwing@kelnet.org>
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package snowflake_test
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+
+	"wumpgo.dev/snowflake"
+)
+
+const configJSON = `{
+	"epoch": "2015-01-01T00:00:00Z",
+	"worker_id": 3,
+	"process_id": 1,
+	"layout": {"timestamp_bits": 41, "worker_bits": 6, "process_bits": 4, "sequence_bits": 12},
+	"time_unit": "10ms",
+	"clock_backwards_policy": "error",
+	"max_backwards_wait": "50ms",
+	"signed_63_bit": true,
+	"shards": 4,
+	"borrowing": "20ms",
+	"random_sequence_start": true
+}`
+
+func TestConfigRoundTrip(t *testing.T) {
+	var c snowflake.Config
+	if err := json.Unmarshal([]byte(configJSON), &c); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := c.Validate(); err != nil {
+		t.Fatalf("Validate() = %v", err)
+	}
+
+	g, err := snowflake.NewFromConfig(c)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got := g.Config()
+	if !got.Epoch.Equal(c.Epoch) {
+		t.Errorf("Config().Epoch = %v, want %v", got.Epoch, c.Epoch)
+	}
+	got.Epoch = c.Epoch
+
+	if !reflect.DeepEqual(got, c) {
+		t.Errorf("Config() = %+v, want %+v", got, c)
+	}
+
+	b, err := json.Marshal(got)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var again snowflake.Config
+	if err := json.Unmarshal(b, &again); err != nil {
+		t.Fatalf("Unmarshal(%s) = %v", b, err)
+	}
+
+	if !reflect.DeepEqual(again, got) {
+		t.Errorf("JSON round trip = %+v, want %+v", again, got)
+	}
+}
+
+func TestConfigDefaults(t *testing.T) {
+	g, err := snowflake.NewFromConfig(snowflake.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	c := g.Config()
+	if !c.Epoch.Equal(snowflake.EpochUnix) || *c.Layout != snowflake.DefaultLayout || c.TimeUnit != "1ms" ||
+		c.ClockBackwardsPolicy != snowflake.PolicyWait || c.MaxBackwardsWait != snowflake.DefaultMaxBackwardsWait.String() {
+		t.Errorf("Config() = %+v, want the defaults of New", c)
+	}
+}
+
+func TestConfigInvalid(t *testing.T) {
+	tests := []struct {
+		name string
+		json string
+	}{
+		{"unknown field", `{"worker": 3}`},
+		{"unknown policy", `{"clock_backwards_policy": "retry"}`},
+		{"wrong type", `{"worker_id": "3"}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var c snowflake.Config
+			if err := json.Unmarshal([]byte(tt.json), &c); err == nil {
+				t.Error("Unmarshal succeeded, want error")
+			}
+		})
+	}
+
+	configs := []struct {
+		name   string
+		config snowflake.Config
+	}{
+		{"bad duration", snowflake.Config{TimeUnit: "soon"}},
+		{"worker too large", snowflake.Config{WorkerID: 32}},
+		{"future epoch", snowflake.Config{Epoch: time.Now().Add(time.Hour)}},
+		{"lock-free with shards", snowflake.Config{LockFree: true, Shards: 2}},
+		{"bad layout", snowflake.Config{Layout: &snowflake.Layout{TimestampBits: 10, SequenceBits: 10}}},
+	}
+
+	for _, tt := range configs {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.config.Validate(); err == nil {
+				t.Error("Validate() succeeded, want error")
+			}
+		})
+	}
+}
@@ -0,0 +1,72 @@
+// MIT License
+
+// Copyright (c) 2022 Project-Sparrow
+// Copyright (c) 2023 Kelwing <kelwing@kelnet.org>
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package snowflake
+
+import (
+	"slices"
+	"time"
+)
+
+// Deterministic is a Generator whose clock only moves when Advance is called,
+// so the Snowflakes it generates are the same on every run.
+// It is meant for tests and fixtures, not for production.
+type Deterministic struct {
+	*Generator
+	clock *ManualClock
+}
+
+// NewDeterministic creates a Deterministic Generator whose clock starts at start.
+// opts are applied as for New, except that WithClock may not be used.
+// The sequence restarts at 0 on every time unit, and when it is exhausted
+// the clock advances to the next time unit by itself instead of blocking.
+func NewDeterministic(start time.Time, opts ...Option) (*Deterministic, error) {
+	clock := NewManualClock(start)
+
+	g, err := New(append(slices.Clip(opts), WithClock(steppingSleeper{clock}))...)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Deterministic{Generator: g, clock: clock}, nil
+}
+
+// Advance moves the clock forward by d.
+func (d *Deterministic) Advance(dur time.Duration) {
+	d.clock.Advance(dur)
+}
+
+// Now returns the time on the clock.
+func (d *Deterministic) Now() time.Time {
+	return d.clock.Now()
+}
+
+// steppingSleeper is a ManualClock that advances when slept on.
+type steppingSleeper struct {
+	*ManualClock
+}
+
+// Sleep advances the clock by d, or a nanosecond if d is not positive.
+func (c steppingSleeper) Sleep(d time.Duration) {
+	c.Advance(max(d, time.Nanosecond))
+}
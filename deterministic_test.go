@@ -0,0 +1,82 @@
+// MIT License
+
+// Copyright (c) 2022 Project-Sparrow
+// Copyright (c) 2023 Kelwing <kelwing@kelnet.org>
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package snowflake_test
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"wumpgo.dev/snowflake"
+)
+
+func deterministicIDs(t *testing.T) []snowflake.Snowflake {
+	t.Helper()
+
+	start := snowflake.EpochDiscord.Add(24 * time.Hour)
+	g, err := snowflake.NewDeterministic(start, snowflake.WithEpoch(snowflake.EpochDiscord), snowflake.WithWorkerID(1), snowflake.WithProcessID(2))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ids := g.GenerateN(3)
+	g.Advance(time.Millisecond)
+	ids = append(ids, g.Generate())
+	g.Advance(time.Second)
+	ids = append(ids, g.Generate(), g.Generate())
+
+	// Exhausting the sequence moves the clock on instead of blocking.
+	batch := g.GenerateN(4097)
+	ids = append(ids, batch[4095], batch[4096])
+
+	if want := start.Add(time.Second + 2*time.Millisecond); !g.Now().Equal(want) {
+		t.Errorf("Now() = %v, want %v", g.Now(), want)
+	}
+
+	return ids
+}
+
+func TestDeterministicGolden(t *testing.T) {
+	want := []snowflake.Snowflake{
+		362387865739264, 362387865739265, 362387865739266,
+		362387869933568,
+		362392064237568, 362392064237569,
+		362392068431873, 362392068431874,
+	}
+
+	got := deterministicIDs(t)
+	if !slices.Equal(got, want) {
+		t.Errorf("Snowflakes = %v, want %v", got, want)
+	}
+
+	if again := deterministicIDs(t); !slices.Equal(again, got) {
+		t.Errorf("second run = %v, want %v", again, got)
+	}
+}
+
+func TestDeterministicWithClock(t *testing.T) {
+	if _, err := snowflake.NewDeterministic(time.Now(), snowflake.WithClock(snowflake.NewManualClock(time.Now()))); err == nil {
+		t.Error("NewDeterministic with WithClock succeeded, want error")
+	}
+}
@@ -0,0 +1,125 @@
+// MIT License
+
+// Copyright (c) 2022 Project-Sparrow
+// Copyright (c) 2023 Kelwing <kelwing@kelnet.org>
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package snowflake
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// ErrEnvNotSet is returned when an environment variable read for
+// a Generator is unset or empty, so callers can fall back to a default.
+var ErrEnvNotSet = errors.New("environment variable not set")
+
+// EnvNotSetError is returned by ConfigFromEnv when some of the environment
+// variables it reads are unset or empty. It wraps ErrEnvNotSet.
+type EnvNotSetError struct {
+	Keys []string // the unset variables, such as "SNOWFLAKE_WORKER_ID"
+}
+
+// Error implements error interface
+func (e *EnvNotSetError) Error() string {
+	return ErrEnvNotSet.Error() + ": " + strings.Join(e.Keys, ", ")
+}
+
+// Unwrap returns ErrEnvNotSet.
+func (e *EnvNotSetError) Unwrap() error {
+	return ErrEnvNotSet
+}
+
+// WorkerIDFromEnv parses the worker ID in the environment variable key,
+// such as SNOWFLAKE_WORKER_ID, for DefaultLayout.
+// It returns an error wrapping ErrEnvNotSet if the variable is unset or empty.
+func WorkerIDFromEnv(key string) (uint8, error) {
+	id, err := envID(key, DefaultLayout.MaxWorkerID())
+	return uint8(id), err
+}
+
+// ConfigFromEnv returns a Config with the worker and process IDs parsed from
+// the environment variables prefix_WORKER_ID and prefix_PROCESS_ID,
+// such as SNOWFLAKE_WORKER_ID and SNOWFLAKE_PROCESS_ID for the prefix
+// SNOWFLAKE. The IDs are checked against DefaultLayout.
+// If some variables are unset or empty and the others are valid, the Config
+// is returned with their fields zero, together with an *EnvNotSetError
+// naming them, so callers can tell a missing ID from 0 and apply defaults.
+func ConfigFromEnv(prefix string) (Config, error) {
+	var c Config
+
+	fields := []struct {
+		key string
+		max uint16
+		dst *uint16
+	}{
+		{prefix + "_WORKER_ID", DefaultLayout.MaxWorkerID(), &c.WorkerID},
+		{prefix + "_PROCESS_ID", DefaultLayout.MaxProcessID(), &c.ProcessID},
+	}
+
+	var (
+		errs  []error
+		unset []string
+	)
+	for _, f := range fields {
+		id, err := envID(f.key, f.max)
+		switch {
+		case err == nil:
+			*f.dst = id
+		case errors.Is(err, ErrEnvNotSet):
+			unset = append(unset, f.key)
+		default:
+			errs = append(errs, err)
+		}
+	}
+
+	if err := errors.Join(errs...); err != nil {
+		return Config{}, err
+	}
+
+	if unset != nil {
+		return c, &EnvNotSetError{Keys: unset}
+	}
+
+	return c, nil
+}
+
+// envID parses the ID in the environment variable key, which must be at most max.
+func envID(key string, max uint16) (uint16, error) {
+	v := os.Getenv(key)
+	if v == "" {
+		return 0, fmt.Errorf("%w: %s", ErrEnvNotSet, key)
+	}
+
+	id, err := strconv.ParseUint(v, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("%s=%q is not an integer between 0 and %d", key, v, max)
+	}
+
+	if id > uint64(max) {
+		return 0, fmt.Errorf("%s=%d exceeds maximum %d", key, id, max)
+	}
+
+	return uint16(id), nil
+}
@@ -0,0 +1,124 @@
+// MIT License
+
+// Copyright (c) 2022 Project-Sparrow
+// Copyright (c) 2023 Kelwing <kelwing@kelnet.org>
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package snowflake_test
+
+import (
+	"errors"
+	"os"
+	"strings"
+	"testing"
+
+	"wumpgo.dev/snowflake"
+)
+
+func TestWorkerIDFromEnv(t *testing.T) {
+	const key = "SNOWFLAKE_TEST_WORKER_ID"
+
+	if _, err := snowflake.WorkerIDFromEnv(key); !errors.Is(err, snowflake.ErrEnvNotSet) {
+		t.Errorf("WorkerIDFromEnv() unset = %v, want ErrEnvNotSet", err)
+	}
+
+	for _, tt := range []struct {
+		value string
+		want  uint8
+		err   string
+	}{
+		{"", 0, "not set"},
+		{"0", 0, ""},
+		{"31", 31, ""},
+		{"097", 0, "SNOWFLAKE_TEST_WORKER_ID=97 exceeds maximum 31"},
+		{"70000", 0, "SNOWFLAKE_TEST_WORKER_ID=70000 exceeds maximum 31"},
+		{"-1", 0, "is not an integer"},
+		{"seven", 0, `SNOWFLAKE_TEST_WORKER_ID="seven" is not an integer`},
+	} {
+		t.Setenv(key, tt.value)
+
+		got, err := snowflake.WorkerIDFromEnv(key)
+		switch {
+		case tt.err == "" && (err != nil || got != tt.want):
+			t.Errorf("WorkerIDFromEnv() with %q = %d, %v, want %d", tt.value, got, err, tt.want)
+		case tt.err != "" && (err == nil || !strings.Contains(err.Error(), tt.err)):
+			t.Errorf("WorkerIDFromEnv() with %q = %v, want error containing %q", tt.value, err, tt.err)
+		case tt.err != "" && tt.value != "" && errors.Is(err, snowflake.ErrEnvNotSet):
+			t.Errorf("WorkerIDFromEnv() with %q = %v, want an error other than ErrEnvNotSet", tt.value, err)
+		}
+	}
+}
+
+func TestConfigFromEnv(t *testing.T) {
+	c, err := snowflake.ConfigFromEnv("SNOWFLAKE_TEST")
+	var notSet *snowflake.EnvNotSetError
+	if !errors.As(err, &notSet) || !errors.Is(err, snowflake.ErrEnvNotSet) || len(notSet.Keys) != 2 {
+		t.Errorf("ConfigFromEnv() unset = %v, want EnvNotSetError for both variables", err)
+	}
+
+	t.Setenv("SNOWFLAKE_TEST_WORKER_ID", "3")
+	t.Setenv("SNOWFLAKE_TEST_PROCESS_ID", "17")
+
+	c, err = snowflake.ConfigFromEnv("SNOWFLAKE_TEST")
+	if err != nil || c.WorkerID != 3 || c.ProcessID != 17 {
+		t.Errorf("ConfigFromEnv() = %+v, %v, want worker 3 and process 17", c, err)
+	}
+
+	if err := c.Validate(); err != nil {
+		t.Errorf("Validate() = %v", err)
+	}
+
+	t.Setenv("SNOWFLAKE_TEST_WORKER_ID", "0")
+	if c, err := snowflake.ConfigFromEnv("SNOWFLAKE_TEST"); err != nil || c.WorkerID != 0 {
+		t.Errorf("ConfigFromEnv() with worker 0 = %+v, %v, want worker 0 and no error", c, err)
+	}
+
+	t.Setenv("SNOWFLAKE_TEST_WORKER_ID", "32")
+	t.Setenv("SNOWFLAKE_TEST_PROCESS_ID", "x")
+
+	_, err = snowflake.ConfigFromEnv("SNOWFLAKE_TEST")
+	for _, want := range []string{"SNOWFLAKE_TEST_WORKER_ID=32 exceeds maximum 31", `SNOWFLAKE_TEST_PROCESS_ID="x"`} {
+		if err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("ConfigFromEnv() = %v, want error containing %q", err, want)
+		}
+	}
+}
+
+func TestConfigFromEnvMissingWorker(t *testing.T) {
+	// t.Setenv restores the variable after the test, which is then unset.
+	t.Setenv("SNOWFLAKE_TEST_WORKER_ID", "")
+	os.Unsetenv("SNOWFLAKE_TEST_WORKER_ID")
+	t.Setenv("SNOWFLAKE_TEST_PROCESS_ID", "5")
+
+	c, err := snowflake.ConfigFromEnv("SNOWFLAKE_TEST")
+
+	var notSet *snowflake.EnvNotSetError
+	if !errors.As(err, &notSet) {
+		t.Fatalf("ConfigFromEnv() = %v, want EnvNotSetError", err)
+	}
+
+	if len(notSet.Keys) != 1 || notSet.Keys[0] != "SNOWFLAKE_TEST_WORKER_ID" {
+		t.Errorf("unset keys = %q, want SNOWFLAKE_TEST_WORKER_ID", notSet.Keys)
+	}
+
+	if c.ProcessID != 5 {
+		t.Errorf("ConfigFromEnv() = %+v, want process 5 kept", c)
+	}
+}
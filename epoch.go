@@ -0,0 +1,86 @@
+// MIT License
+
+// Copyright (c) 2022 Project-Sparrow
+// Copyright (c) 2023 Kelwing <kelwing@kelnet.org>
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package snowflake
+
+import (
+	"fmt"
+	"time"
+)
+
+var (
+	// EpochUnix is the Unix epoch, 1970-01-01 UTC,
+	// used when no other epoch is given.
+	EpochUnix = time.UnixMilli(0).UTC()
+
+	// EpochDiscord is the epoch of Discord Snowflakes, 2015-01-01 UTC.
+	EpochDiscord = time.UnixMilli(1420070400000).UTC()
+
+	// EpochTwitter is the epoch of Twitter's original Snowflake scheme,
+	// 2010-11-04 01:42:54.657 UTC.
+	EpochTwitter = time.UnixMilli(1288834974657).UTC()
+
+	// EpochKSUID is the epoch of KSUIDs, 2014-05-13 16:53:20 UTC.
+	EpochKSUID = time.Unix(1400000000, 0).UTC()
+)
+
+// InitDiscord is like Init with EpochDiscord,
+// so Time and Age decode Discord Snowflakes correctly.
+func InitDiscord(w, p int) {
+	Init(EpochDiscord, w, p)
+}
+
+// NewDiscord creates a Generator for Discord Snowflakes,
+// using EpochDiscord and DefaultLayout, which matches Discord's.
+// opts are applied afterwards, so WithEpoch may be used to pick another epoch.
+func NewDiscord(workerID, processID uint16, opts ...Option) (*Generator, error) {
+	g := newGenerator()
+	g.epoch = EpochDiscord
+	g.workerID = workerID
+	g.processID = processID
+
+	return g.configure(opts)
+}
+
+// ConvertEpoch re-bases a Snowflake minted with the epoch from onto the epoch
+// to, so that its Time relative to to equals its Time relative to from.
+// The worker, process and sequence bits are preserved. It returns an error
+// if the epochs differ by a fraction of a millisecond, or if the new
+// timestamp would be negative or overflow the timestamp bits.
+func ConvertEpoch(s Snowflake, from, to time.Time) (Snowflake, error) {
+	d := from.Sub(to)
+	if d%time.Millisecond != 0 {
+		return 0, fmt.Errorf("epochs %v and %v differ by a fraction of a millisecond", from, to)
+	}
+
+	ms := int64(s>>timestampShift) + d.Milliseconds()
+	if ms < 0 {
+		return 0, fmt.Errorf("%d at %v is before the epoch %v", s, s.TimeWithEpoch(from), to)
+	}
+
+	if ms > timestampMask {
+		return 0, fmt.Errorf("%w: %d at %v with the epoch %v", ErrTimestampOverflow, s, s.TimeWithEpoch(from), to)
+	}
+
+	return Snowflake(ms)<<timestampShift | s&(1<<timestampShift-1), nil
+}
@@ -0,0 +1,189 @@
+// MIT License
+
+// Copyright (c) 2022 Project-Sparrow
+// Copyright (c) 2023 Kelwing <kelwing@kelnet.org>
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package snowflake_test
+
+import (
+	"errors"
+	"math/rand"
+	"testing"
+	"time"
+
+	"wumpgo.dev/snowflake"
+)
+
+// discordVectors are public Discord Snowflakes with their creation times.
+var discordVectors = []struct {
+	name    string
+	id      snowflake.Snowflake
+	created time.Time
+	worker  uint8
+	seq     uint16
+}{
+	// The example from Discord's API reference on Snowflakes.
+	{"api reference", 175928847299117063, time.Date(2016, time.April, 30, 11, 18, 25, 796e6, time.UTC), 1, 7},
+	// The guild ID used in Discord's API reference examples.
+	{"example guild", 41771983423143937, time.Date(2015, time.April, 26, 6, 26, 56, 934e6, time.UTC), 0, 1},
+}
+
+func TestInitDiscord(t *testing.T) {
+	snowflake.InitDiscord(1, 0)
+	defer snowflake.ResetDefault()
+
+	now := time.Date(2020, time.January, 1, 0, 0, 0, 0, time.UTC)
+	restore := snowflake.FreezeClock(now)
+	defer restore()
+
+	for _, v := range discordVectors {
+		t.Run(v.name, func(t *testing.T) {
+			if got := v.id.Time(); !got.Equal(v.created) {
+				t.Errorf("Time() = %v, want %v", got, v.created)
+			}
+
+			if got, want := v.id.Age(), now.Sub(v.created); got != want {
+				t.Errorf("Age() = %v, want %v", got, want)
+			}
+
+			if v.id.WorkerID() != v.worker || v.id.Sequence() != v.seq {
+				t.Errorf("WorkerID(), Sequence() = %d, %d, want %d, %d", v.id.WorkerID(), v.id.Sequence(), v.worker, v.seq)
+			}
+		})
+	}
+}
+
+func TestNewDiscord(t *testing.T) {
+	v := discordVectors[0]
+	clock := snowflake.NewFakeClock(v.created)
+
+	g, err := snowflake.NewDiscord(1, 0, snowflake.WithClock(clock))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i := uint16(0); i < v.seq; i++ {
+		g.Generate()
+	}
+
+	if s := g.Generate(); s != v.id {
+		t.Errorf("Generate() = %d, want %d", s, v.id)
+	}
+}
+
+func TestEpochs(t *testing.T) {
+	tests := []struct {
+		epoch time.Time
+		ms    int64
+	}{
+		{snowflake.EpochUnix, 0},
+		{snowflake.EpochDiscord, 1420070400000},
+		{snowflake.EpochTwitter, 1288834974657},
+	}
+
+	for _, tt := range tests {
+		if got := tt.epoch.UnixMilli(); got != tt.ms {
+			t.Errorf("epoch %v = %d ms, want %d", tt.epoch, got, tt.ms)
+		}
+	}
+}
+
+func TestConvertEpoch(t *testing.T) {
+	for _, v := range discordVectors {
+		got, err := snowflake.ConvertEpoch(v.id, snowflake.EpochDiscord, snowflake.EpochTwitter)
+		if err != nil {
+			t.Fatalf("%s: ConvertEpoch() = %v", v.name, err)
+		}
+
+		if !got.TimeWithEpoch(snowflake.EpochTwitter).Equal(v.created) || got.WorkerID() != v.worker || got.Sequence() != v.seq {
+			t.Errorf("%s: ConvertEpoch() = %d, created %v worker %d seq %d, want %v worker %d seq %d", v.name, got,
+				got.TimeWithEpoch(snowflake.EpochTwitter), got.WorkerID(), got.Sequence(), v.created, v.worker, v.seq)
+		}
+
+		back, err := snowflake.ConvertEpoch(got, snowflake.EpochTwitter, snowflake.EpochDiscord)
+		if err != nil || back != v.id {
+			t.Errorf("%s: converting back = %d, %v, want %d", v.name, back, err, v.id)
+		}
+	}
+
+	if got, err := snowflake.ConvertEpoch(42, snowflake.EpochDiscord, snowflake.EpochDiscord); err != nil || got != 42 {
+		t.Errorf("ConvertEpoch() to the same epoch = %d, %v, want 42", got, err)
+	}
+}
+
+func TestConvertEpochInvalid(t *testing.T) {
+	// The Discord epoch is before 2020, so early Discord Snowflakes
+	// cannot be re-based onto it.
+	later := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	if _, err := snowflake.ConvertEpoch(175928847299117063, snowflake.EpochDiscord, later); err == nil {
+		t.Error("ConvertEpoch() before the new epoch succeeded")
+	}
+
+	// The largest Discord timestamp does not fit with the earlier Twitter epoch.
+	if _, err := snowflake.ConvertEpoch(1<<64-1, snowflake.EpochDiscord, snowflake.EpochTwitter); !errors.Is(err, snowflake.ErrTimestampOverflow) {
+		t.Errorf("ConvertEpoch() past the timestamp bits = %v, want ErrTimestampOverflow", err)
+	}
+
+	if _, err := snowflake.ConvertEpoch(42, snowflake.EpochDiscord, snowflake.EpochDiscord.Add(time.Microsecond)); err == nil {
+		t.Error("ConvertEpoch() with a fractional millisecond difference succeeded")
+	}
+}
+
+func TestConvertEpochProperty(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	from := snowflake.EpochDiscord
+	to := time.Date(2018, 6, 1, 12, 30, 0, 0, time.UTC)
+	shift := uint64(to.Sub(from).Milliseconds()) << 22
+
+	converted := 0
+	for i := 0; i < 10000; i++ {
+		s := snowflake.Snowflake(r.Uint64())
+
+		got, err := snowflake.ConvertEpoch(s, from, to)
+		if uint64(s) < shift {
+			if err == nil {
+				t.Fatalf("ConvertEpoch(%d) = %d, want an error for a time before the new epoch", s, got)
+			}
+			continue
+		}
+
+		if err != nil {
+			t.Fatalf("ConvertEpoch(%d) = %v", s, err)
+		}
+
+		if !got.TimeWithEpoch(to).Equal(s.TimeWithEpoch(from)) {
+			t.Fatalf("ConvertEpoch(%d) has time %v, want %v", s, got.TimeWithEpoch(to), s.TimeWithEpoch(from))
+		}
+
+		if got.WorkerID() != s.WorkerID() || got.ProcessID() != s.ProcessID() || got.Sequence() != s.Sequence() {
+			t.Fatalf("ConvertEpoch(%d) = %d, changed the low bits", s, got)
+		}
+
+		if back, err := snowflake.ConvertEpoch(got, to, from); err != nil || back != s {
+			t.Fatalf("converting %d back = %d, %v", got, back, err)
+		}
+		converted++
+	}
+
+	if converted < 5000 {
+		t.Errorf("only %d of 10000 random Snowflakes converted", converted)
+	}
+}
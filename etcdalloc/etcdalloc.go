@@ -0,0 +1,404 @@
+// MIT License
+
+// Copyright (c) 2022 Project-Sparrow
+// Copyright (c) 2023 Kelwing <kelwing@kelnet.org>
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+// Package etcdalloc leases Snowflake worker IDs from etcd, so instances
+// of a cluster each hold their own without static assignment.
+//
+// A worker ID is held by a key such as /snowflake/workers/3 attached to an
+// etcd lease, which is kept alive until the Allocator is closed. The key's
+// value is the lease ID in hexadecimal.
+package etcdalloc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"go.etcd.io/etcd/api/v3/v3rpc/rpctypes"
+	clientv3 "go.etcd.io/etcd/client/v3"
+	"wumpgo.dev/snowflake"
+)
+
+var (
+	// ErrNoFreeID is returned by Acquire when every worker ID is leased.
+	ErrNoFreeID = errors.New("no free worker ID")
+
+	// ErrLeaseLost reports that the lease on the worker ID expired,
+	// so Snowflakes generated with it may no longer be unique.
+	ErrLeaseLost = errors.New("worker ID lease lost")
+
+	// ErrNotAcquired is returned by Renew when no worker ID is held.
+	ErrNotAcquired = errors.New("no worker ID acquired")
+)
+
+const (
+	// DefaultPrefix is the default prefix of the keys holding worker IDs.
+	DefaultPrefix = "/snowflake/workers/"
+
+	// DefaultTTL is the default lifetime of a lease.
+	DefaultTTL = 10 * time.Second
+)
+
+// Client is the part of the etcd client used by an Allocator.
+// *clientv3.Client implements it.
+type Client interface {
+	clientv3.KV
+	clientv3.Lease
+}
+
+// Option configures an Allocator.
+type Option func(*Allocator) error
+
+// WithPrefix sets the prefix of the keys holding worker IDs,
+// DefaultPrefix by default.
+func WithPrefix(prefix string) Option {
+	return func(a *Allocator) error {
+		a.prefix = prefix
+		return nil
+	}
+}
+
+// WithTTL sets the lifetime of a lease, DefaultTTL by default.
+// etcd leases last whole seconds, so ttl is rounded up to one.
+func WithTTL(ttl time.Duration) Option {
+	return func(a *Allocator) error {
+		if ttl <= 0 {
+			return fmt.Errorf("lease TTL %v is not positive", ttl)
+		}
+
+		a.ttl = ttl
+
+		return nil
+	}
+}
+
+// WithBits sets the width of the worker IDs handed out,
+// the 5 bits of snowflake.DefaultLayout by default. Acquire lists the taken
+// keys in one request, so the width only bounds how many transactions a
+// race for the free ones can take.
+func WithBits(bits uint8) Option {
+	return func(a *Allocator) error {
+		if bits == 0 || bits > 8 {
+			return fmt.Errorf("worker ID width %d is not between 1 and 8 bits", bits)
+		}
+
+		a.bits = bits
+
+		return nil
+	}
+}
+
+// WithOnLost makes the Allocator call f when the client stops keeping the
+// lease alive, because it expired while etcd was unreachable or was revoked
+// by someone else. f is passed an error wrapping ErrLeaseLost and returns
+// before the Lost channel is closed. It runs on a goroutine of its own,
+// so it may close the Allocator.
+func WithOnLost(f func(error)) Option {
+	return func(a *Allocator) error {
+		a.onLost = f
+		return nil
+	}
+}
+
+var _ snowflake.WorkerIDAllocator = (*Allocator)(nil)
+
+// Allocator leases a worker ID from etcd.
+// It is safe for concurrent use.
+type Allocator struct {
+	client Client
+	prefix string
+	ttl    time.Duration
+	bits   uint8
+	onLost func(error)
+
+	mtx   sync.Mutex
+	id    int
+	lease clientv3.LeaseID
+	gen   *snowflake.Generator
+	stop  context.CancelFunc
+	done  chan struct{}
+
+	lostOnce sync.Once
+	lost     chan struct{}
+	err      error // guarded by mtx
+}
+
+// New creates an Allocator leasing worker IDs through client.
+func New(client Client, opts ...Option) (*Allocator, error) {
+	a := &Allocator{
+		client: client,
+		prefix: DefaultPrefix,
+		ttl:    DefaultTTL,
+		bits:   snowflake.DefaultLayout.WorkerBits,
+		id:     -1,
+		lost:   make(chan struct{}),
+	}
+
+	for _, opt := range opts {
+		if err := opt(a); err != nil {
+			return nil, err
+		}
+	}
+
+	return a, nil
+}
+
+// Acquire leases the lowest free worker ID and keeps the lease alive in the
+// background. Each candidate is claimed in a transaction that only succeeds
+// if its key does not exist, so instances racing for the same one cannot
+// both win. It returns ErrNoFreeID if every worker ID is leased.
+// An Allocator cannot acquire again once its lease was lost.
+func (a *Allocator) Acquire(ctx context.Context) (uint8, error) {
+	a.mtx.Lock()
+	defer a.mtx.Unlock()
+
+	if a.err != nil {
+		return 0, a.err
+	}
+
+	if a.id >= 0 {
+		return 0, fmt.Errorf("worker ID %d is already acquired", a.id)
+	}
+
+	grant, err := a.client.Grant(ctx, int64((a.ttl+time.Second-1)/time.Second))
+	if err != nil {
+		return 0, fmt.Errorf("granting lease: %w", err)
+	}
+
+	id, err := a.claim(ctx, grant.ID)
+	if err != nil {
+		a.revoke(grant.ID)
+		return 0, err
+	}
+
+	keepCtx, stop := context.WithCancel(context.Background())
+	alive, err := a.client.KeepAlive(keepCtx, grant.ID)
+	if err != nil {
+		stop()
+		a.revoke(grant.ID)
+		return 0, fmt.Errorf("keeping lease alive: %w", err)
+	}
+
+	a.id, a.lease = id, grant.ID
+	a.stop, a.done = stop, make(chan struct{})
+	go a.keepAlive(keepCtx, id, alive, a.done)
+
+	return uint8(id), nil
+}
+
+// claim puts the key of the lowest free worker ID with lease.
+func (a *Allocator) claim(ctx context.Context, lease clientv3.LeaseID) (int, error) {
+	resp, err := a.client.Get(ctx, a.prefix, clientv3.WithPrefix(), clientv3.WithKeysOnly())
+	if err != nil {
+		return 0, fmt.Errorf("listing worker IDs: %w", err)
+	}
+
+	taken := make(map[int]bool, len(resp.Kvs))
+	for _, kv := range resp.Kvs {
+		if id, err := strconv.Atoi(strings.TrimPrefix(string(kv.Key), a.prefix)); err == nil {
+			taken[id] = true
+		}
+	}
+
+	value := strconv.FormatInt(int64(lease), 16)
+	for id := 0; id < 1<<a.bits; id++ {
+		if taken[id] {
+			continue
+		}
+
+		key := a.key(id)
+		txn, err := a.client.Txn(ctx).
+			If(clientv3.Compare(clientv3.CreateRevision(key), "=", 0)).
+			Then(clientv3.OpPut(key, value, clientv3.WithLease(lease))).
+			Commit()
+		if err != nil {
+			return 0, fmt.Errorf("leasing worker ID %d: %w", id, err)
+		}
+
+		// Otherwise another instance claimed it since the listing.
+		if txn.Succeeded {
+			return id, nil
+		}
+	}
+
+	return 0, fmt.Errorf("%w among %d worker IDs under %q", ErrNoFreeID, 1<<a.bits, a.prefix)
+}
+
+// NewGenerator acquires a worker ID and creates a Generator using it with
+// opts, which is closed as soon as the client gives up on the lease.
+// snowflake.WithAllocator does not watch the lease; see
+// snowflake.WorkerIDAllocator for why that matters.
+// Once the lease is lost, Generate panics like after Generator.Close, so
+// losing etcd for longer than the TTL brings down callers using it; use
+// GenerateContext or TryGenerate, which return snowflake.ErrClosed instead,
+// where that matters.
+// Close the Allocator after the Generator to release the worker ID.
+func (a *Allocator) NewGenerator(ctx context.Context, opts ...snowflake.Option) (*snowflake.Generator, error) {
+	id, err := a.Acquire(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	g, err := snowflake.New(append(slices.Clip(opts), snowflake.WithWorkerID(uint16(id)))...)
+	if err != nil {
+		return nil, errors.Join(err, a.Release())
+	}
+
+	a.mtx.Lock()
+	a.gen = g
+	lost := a.err != nil
+	a.mtx.Unlock()
+
+	if lost {
+		g.Close()
+	}
+
+	return g, nil
+}
+
+// Renew refreshes the lease once. It returns an error wrapping ErrLeaseLost
+// if the lease has expired. Leases are kept alive in the background,
+// so calling Renew is seldom needed.
+func (a *Allocator) Renew(ctx context.Context) error {
+	a.mtx.Lock()
+	id, lease := a.id, a.lease
+	a.mtx.Unlock()
+
+	if id < 0 {
+		return ErrNotAcquired
+	}
+
+	if _, err := a.client.KeepAliveOnce(ctx, lease); err != nil {
+		if errors.Is(err, rpctypes.ErrLeaseNotFound) {
+			err := fmt.Errorf("%w: worker ID %d", ErrLeaseLost, id)
+			a.lose(err)
+			return err
+		}
+
+		return fmt.Errorf("renewing worker ID %d: %w", id, err)
+	}
+
+	return nil
+}
+
+// Release stops keeping the lease alive and revokes it, which deletes the
+// key and frees the worker ID for other instances.
+// It does nothing if no worker ID is held.
+func (a *Allocator) Release() error {
+	a.mtx.Lock()
+	id, lease, stop, done := a.id, a.lease, a.stop, a.done
+	a.id, a.gen = -1, nil
+	a.mtx.Unlock()
+
+	if id < 0 {
+		return nil
+	}
+
+	stop()
+	<-done
+
+	if err := a.revoke(lease); err != nil {
+		return fmt.Errorf("releasing worker ID %d: %w", id, err)
+	}
+
+	return nil
+}
+
+// Close releases the worker ID. It implements io.Closer.
+func (a *Allocator) Close() error {
+	return a.Release()
+}
+
+// Lost returns a channel that is closed when the lease is lost.
+func (a *Allocator) Lost() <-chan struct{} {
+	return a.lost
+}
+
+// Err returns the error wrapping ErrLeaseLost that closed the Lost channel,
+// or nil if the lease has not been lost.
+func (a *Allocator) Err() error {
+	a.mtx.Lock()
+	defer a.mtx.Unlock()
+	return a.err
+}
+
+// keepAlive drains the keep-alive responses of the lease on id. The client
+// closes alive when ctx is canceled or the lease can no longer be kept alive.
+func (a *Allocator) keepAlive(ctx context.Context, id int, alive <-chan *clientv3.LeaseKeepAliveResponse, done chan<- struct{}) {
+	defer close(done)
+
+	for range alive {
+	}
+
+	if ctx.Err() == nil {
+		a.lose(fmt.Errorf("%w: worker ID %d", ErrLeaseLost, id))
+	}
+}
+
+// lose records err, from keepAlive once the client closes its channel or
+// from Renew when etcd no longer knows the lease, and closes the Generator
+// of NewGenerator before calling onLost and closing lost. Only the first
+// call has any effect. The Generator is closed and onLost called on another
+// goroutine, as both may wait: Close for calls in progress, and onLost
+// may release the Allocator, which waits for keepAlive to return.
+func (a *Allocator) lose(err error) {
+	a.lostOnce.Do(func() {
+		a.mtx.Lock()
+		a.err = err
+		g := a.gen
+		a.mtx.Unlock()
+
+		go func() {
+			if g != nil {
+				g.Close()
+			}
+
+			if a.onLost != nil {
+				a.onLost(err)
+			}
+
+			close(a.lost)
+		}()
+	})
+}
+
+// revoke revokes lease, ignoring leases that already expired.
+func (a *Allocator) revoke(lease clientv3.LeaseID) error {
+	ctx, cancel := context.WithTimeout(context.Background(), a.ttl)
+	defer cancel()
+
+	if _, err := a.client.Revoke(ctx, lease); err != nil && !errors.Is(err, rpctypes.ErrLeaseNotFound) {
+		return err
+	}
+
+	return nil
+}
+
+func (a *Allocator) key(id int) string {
+	return a.prefix + strconv.Itoa(id)
+}
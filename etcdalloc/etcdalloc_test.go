@@ -0,0 +1,407 @@
+// MIT License
+
+// Copyright (c) 2022 Project-Sparrow
+// Copyright (c) 2023 Kelwing <kelwing@kelnet.org>
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package etcdalloc_test
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"go.etcd.io/etcd/api/v3/mvccpb"
+	"go.etcd.io/etcd/api/v3/v3rpc/rpctypes"
+	clientv3 "go.etcd.io/etcd/client/v3"
+	"wumpgo.dev/snowflake"
+	"wumpgo.dev/snowflake/etcdalloc"
+)
+
+// fakeEtcd implements the keys and leases an Allocator uses in memory.
+// Methods it does not use are left to the nil embedded interfaces.
+type fakeEtcd struct {
+	clientv3.KV
+	clientv3.Lease
+
+	mtx    sync.Mutex
+	keys   map[string]clientv3.LeaseID
+	leases map[clientv3.LeaseID]*fakeLease
+	next   clientv3.LeaseID
+
+	// stale makes Get list no keys, as if they were claimed after it.
+	stale bool
+}
+
+type fakeLease struct {
+	once  sync.Once
+	alive chan *clientv3.LeaseKeepAliveResponse
+}
+
+func newFakeEtcd() *fakeEtcd {
+	return &fakeEtcd{keys: make(map[string]clientv3.LeaseID), leases: make(map[clientv3.LeaseID]*fakeLease)}
+}
+
+func (f *fakeEtcd) Get(ctx context.Context, prefix string, opts ...clientv3.OpOption) (*clientv3.GetResponse, error) {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+
+	resp := new(clientv3.GetResponse)
+	if f.stale {
+		return resp, nil
+	}
+
+	for key := range f.keys {
+		if strings.HasPrefix(key, prefix) {
+			resp.Kvs = append(resp.Kvs, &mvccpb.KeyValue{Key: []byte(key)})
+		}
+	}
+
+	return resp, nil
+}
+
+func (f *fakeEtcd) Txn(ctx context.Context) clientv3.Txn {
+	return &fakeTxn{f: f}
+}
+
+func (f *fakeEtcd) Grant(ctx context.Context, ttl int64) (*clientv3.LeaseGrantResponse, error) {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+
+	f.next++
+	f.leases[f.next] = &fakeLease{}
+
+	return &clientv3.LeaseGrantResponse{ID: f.next, TTL: ttl}, nil
+}
+
+func (f *fakeEtcd) KeepAlive(ctx context.Context, id clientv3.LeaseID) (<-chan *clientv3.LeaseKeepAliveResponse, error) {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+
+	l := f.leases[id]
+	if l == nil {
+		return nil, rpctypes.ErrLeaseNotFound
+	}
+
+	l.alive = make(chan *clientv3.LeaseKeepAliveResponse)
+	go func() {
+		<-ctx.Done()
+		l.once.Do(func() { close(l.alive) })
+	}()
+
+	return l.alive, nil
+}
+
+func (f *fakeEtcd) KeepAliveOnce(ctx context.Context, id clientv3.LeaseID) (*clientv3.LeaseKeepAliveResponse, error) {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+
+	if f.leases[id] == nil {
+		return nil, rpctypes.ErrLeaseNotFound
+	}
+
+	return &clientv3.LeaseKeepAliveResponse{ID: id, TTL: 10}, nil
+}
+
+func (f *fakeEtcd) Revoke(ctx context.Context, id clientv3.LeaseID) (*clientv3.LeaseRevokeResponse, error) {
+	if !f.expire(id) {
+		return nil, rpctypes.ErrLeaseNotFound
+	}
+	return new(clientv3.LeaseRevokeResponse), nil
+}
+
+// expire ends the lease id, deleting its keys, and reports whether it existed.
+func (f *fakeEtcd) expire(id clientv3.LeaseID) bool {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+
+	l := f.leases[id]
+	if l == nil {
+		return false
+	}
+	delete(f.leases, id)
+
+	for key, lease := range f.keys {
+		if lease == id {
+			delete(f.keys, key)
+		}
+	}
+
+	if l.alive != nil {
+		l.once.Do(func() { close(l.alive) })
+	}
+
+	return true
+}
+
+func (f *fakeEtcd) lease(key string) clientv3.LeaseID {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+	return f.keys[key]
+}
+
+// fakeTxn supports the transaction an Allocator commits: putting keys
+// with a lease if other keys do not exist.
+type fakeTxn struct {
+	f    *fakeEtcd
+	cmps []clientv3.Cmp
+	ops  []clientv3.Op
+}
+
+func (t *fakeTxn) If(cs ...clientv3.Cmp) clientv3.Txn   { t.cmps = cs; return t }
+func (t *fakeTxn) Then(ops ...clientv3.Op) clientv3.Txn { t.ops = ops; return t }
+func (t *fakeTxn) Else(ops ...clientv3.Op) clientv3.Txn { return t }
+
+func (t *fakeTxn) Commit() (*clientv3.TxnResponse, error) {
+	t.f.mtx.Lock()
+	defer t.f.mtx.Unlock()
+
+	for _, cmp := range t.cmps {
+		if _, ok := t.f.keys[string(cmp.KeyBytes())]; ok {
+			return &clientv3.TxnResponse{Succeeded: false}, nil
+		}
+	}
+
+	for _, op := range t.ops {
+		// The Allocator stores the lease ID as the value.
+		lease, err := strconv.ParseInt(string(op.ValueBytes()), 16, 64)
+		if err != nil {
+			return nil, err
+		}
+		t.f.keys[string(op.KeyBytes())] = clientv3.LeaseID(lease)
+	}
+
+	return &clientv3.TxnResponse{Succeeded: true}, nil
+}
+
+func newAllocator(t *testing.T, client etcdalloc.Client, opts ...etcdalloc.Option) *etcdalloc.Allocator {
+	t.Helper()
+
+	a, err := etcdalloc.New(client, opts...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { a.Close() })
+
+	return a
+}
+
+func waitLost(t *testing.T, a *etcdalloc.Allocator) {
+	t.Helper()
+
+	select {
+	case <-a.Lost():
+	case <-time.After(5 * time.Second):
+		t.Fatal("lease loss was not detected")
+	}
+}
+
+func TestAcquireLowestFree(t *testing.T) {
+	f := newFakeEtcd()
+	ctx := context.Background()
+
+	allocs := make([]*etcdalloc.Allocator, 4)
+	for i := range allocs {
+		allocs[i] = newAllocator(t, f, etcdalloc.WithBits(2))
+
+		id, err := allocs[i].Acquire(ctx)
+		if err != nil || int(id) != i {
+			t.Fatalf("Acquire() #%d = %d, %v, want %d", i, id, err, i)
+		}
+	}
+
+	last := newAllocator(t, f, etcdalloc.WithBits(2))
+	if _, err := last.Acquire(ctx); !errors.Is(err, etcdalloc.ErrNoFreeID) {
+		t.Fatalf("Acquire() with no free ID = %v, want ErrNoFreeID", err)
+	}
+
+	if err := allocs[2].Release(); err != nil {
+		t.Fatal(err)
+	}
+
+	if f.lease("/snowflake/workers/2") != 0 {
+		t.Error("Release did not delete the key")
+	}
+
+	if id, err := last.Acquire(ctx); err != nil || id != 2 {
+		t.Errorf("Acquire() after release = %d, %v, want 2", id, err)
+	}
+
+	if _, err := last.Acquire(ctx); err == nil {
+		t.Error("second Acquire succeeded, want error")
+	}
+
+	// Only the leases of held worker IDs are left.
+	f.mtx.Lock()
+	leases := len(f.leases)
+	f.mtx.Unlock()
+	if leases != 4 {
+		t.Errorf("%d leases left, want 4", leases)
+	}
+}
+
+func TestAcquireRace(t *testing.T) {
+	f := newFakeEtcd()
+	ctx := context.Background()
+
+	if _, err := newAllocator(t, f).Acquire(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	// The listing misses worker ID 0, so the transaction must catch it.
+	f.stale = true
+	if id, err := newAllocator(t, f).Acquire(ctx); err != nil || id != 1 {
+		t.Errorf("Acquire() with a stale listing = %d, %v, want 1", id, err)
+	}
+}
+
+func TestAcquireContention(t *testing.T) {
+	f := newFakeEtcd()
+
+	const goroutines = 16
+
+	ids := make(chan uint8, goroutines)
+	var wg sync.WaitGroup
+	var mtx sync.Mutex
+	var errs []error
+	for i := 0; i < goroutines; i++ {
+		a := newAllocator(t, f, etcdalloc.WithBits(3))
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			id, err := a.Acquire(context.Background())
+			if err != nil {
+				mtx.Lock()
+				errs = append(errs, err)
+				mtx.Unlock()
+				return
+			}
+			ids <- id
+		}()
+	}
+	wg.Wait()
+	close(ids)
+
+	seen := make(map[uint8]bool)
+	for id := range ids {
+		if seen[id] {
+			t.Errorf("worker ID %d acquired twice", id)
+		}
+		seen[id] = true
+	}
+
+	if len(seen) != 8 || len(errs) != goroutines-8 {
+		t.Errorf("%d IDs acquired and %d failures, want 8 and %d", len(seen), len(errs), goroutines-8)
+	}
+
+	for _, err := range errs {
+		if !errors.Is(err, etcdalloc.ErrNoFreeID) {
+			t.Errorf("Acquire() = %v, want ErrNoFreeID", err)
+		}
+	}
+}
+
+func TestLeaseLoss(t *testing.T) {
+	f := newFakeEtcd()
+	ctx := context.Background()
+
+	var lostErr error
+	a := newAllocator(t, f, etcdalloc.WithOnLost(func(err error) { lostErr = err }))
+	if err := a.Renew(ctx); !errors.Is(err, etcdalloc.ErrNotAcquired) {
+		t.Errorf("Renew() before Acquire = %v, want ErrNotAcquired", err)
+	}
+
+	g, err := a.NewGenerator(ctx, snowflake.WithEpoch(snowflake.EpochDiscord))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := a.Renew(ctx); err != nil {
+		t.Fatalf("Renew() = %v", err)
+	}
+
+	f.expire(f.lease("/snowflake/workers/0"))
+	waitLost(t, a)
+
+	if !errors.Is(a.Err(), etcdalloc.ErrLeaseLost) || !errors.Is(lostErr, etcdalloc.ErrLeaseLost) {
+		t.Errorf("Err() = %v and OnLost got %v, want ErrLeaseLost", a.Err(), lostErr)
+	}
+
+	if _, err := g.TryGenerate(); !errors.Is(err, snowflake.ErrClosed) {
+		t.Errorf("TryGenerate() after loss = %v, want ErrClosed", err)
+	}
+
+	if err := a.Renew(ctx); !errors.Is(err, etcdalloc.ErrLeaseLost) {
+		t.Errorf("Renew() after loss = %v, want ErrLeaseLost", err)
+	}
+
+	if err := a.Release(); err != nil {
+		t.Errorf("Release() after loss = %v", err)
+	}
+
+	if _, err := a.Acquire(ctx); !errors.Is(err, etcdalloc.ErrLeaseLost) {
+		t.Errorf("Acquire() after loss = %v, want ErrLeaseLost", err)
+	}
+}
+
+func TestCloseOnLost(t *testing.T) {
+	f := newFakeEtcd()
+
+	closed := make(chan error, 1)
+	var a *etcdalloc.Allocator
+	a = newAllocator(t, f, etcdalloc.WithOnLost(func(error) {
+		closed <- a.Close()
+	}))
+	g, err := a.NewGenerator(context.Background(), snowflake.WithEpoch(snowflake.EpochDiscord))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	f.expire(f.lease("/snowflake/workers/0"))
+
+	select {
+	case err := <-closed:
+		if err != nil {
+			t.Errorf("Close() from OnLost = %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Close() from OnLost did not return")
+	}
+
+	waitLost(t, a)
+
+	if _, err := g.TryGenerate(); !errors.Is(err, snowflake.ErrClosed) {
+		t.Errorf("TryGenerate() after loss = %v, want ErrClosed", err)
+	}
+}
+
+func TestNewInvalid(t *testing.T) {
+	for _, opt := range []etcdalloc.Option{etcdalloc.WithBits(0), etcdalloc.WithBits(9), etcdalloc.WithTTL(0)} {
+		if _, err := etcdalloc.New(newFakeEtcd(), opt); err == nil {
+			t.Error("New succeeded, want error")
+		}
+	}
+}
@@ -0,0 +1,189 @@
+// MIT License
+
+// Copyright (c) 2022 Project-Sparrow
+// Copyright (c) 2023 Kelwing <kelwing@kelnet.org>
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+//go:build integration
+
+package etcdalloc_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"sync"
+	"testing"
+	"time"
+
+	clientv3 "go.etcd.io/etcd/client/v3"
+	"go.etcd.io/etcd/server/v3/embed"
+	"wumpgo.dev/snowflake/etcdalloc"
+)
+
+// freeURL returns a URL on a free local port.
+func freeURL(t *testing.T) url.URL {
+	t.Helper()
+
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+
+	return url.URL{Scheme: "http", Host: l.Addr().String()}
+}
+
+// startEtcd runs an embedded single-node etcd server for the test.
+func startEtcd(t *testing.T) *clientv3.Client {
+	t.Helper()
+
+	cfg := embed.NewConfig()
+	cfg.Dir = t.TempDir()
+	cfg.LogLevel = "error"
+
+	client, peer := freeURL(t), freeURL(t)
+	cfg.ListenClientUrls, cfg.AdvertiseClientUrls = []url.URL{client}, []url.URL{client}
+	cfg.ListenPeerUrls, cfg.AdvertisePeerUrls = []url.URL{peer}, []url.URL{peer}
+	cfg.InitialCluster = fmt.Sprintf("%s=%s", cfg.Name, peer.String())
+
+	e, err := embed.StartEtcd(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(e.Close)
+
+	select {
+	case <-e.Server.ReadyNotify():
+	case <-time.After(30 * time.Second):
+		t.Fatal("etcd did not start")
+	}
+
+	c, err := clientv3.New(clientv3.Config{Endpoints: []string{client.String()}, DialTimeout: 5 * time.Second})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { c.Close() })
+
+	return c
+}
+
+func TestEtcdAcquire(t *testing.T) {
+	c := startEtcd(t)
+	ctx := context.Background()
+
+	const goroutines = 8
+
+	ids := make(chan uint8, goroutines)
+	var wg sync.WaitGroup
+	var mtx sync.Mutex
+	var errs []error
+	for i := 0; i < goroutines; i++ {
+		a := newAllocator(t, c, etcdalloc.WithBits(2))
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			id, err := a.Acquire(ctx)
+			if err != nil {
+				mtx.Lock()
+				errs = append(errs, err)
+				mtx.Unlock()
+				return
+			}
+			ids <- id
+		}()
+	}
+	wg.Wait()
+	close(ids)
+
+	seen := make(map[uint8]bool)
+	for id := range ids {
+		if seen[id] {
+			t.Errorf("worker ID %d acquired twice", id)
+		}
+		seen[id] = true
+	}
+
+	if len(seen) != 4 || len(errs) != goroutines-4 {
+		t.Errorf("%d IDs acquired and %d failures, want 4 and %d", len(seen), len(errs), goroutines-4)
+	}
+
+	for _, err := range errs {
+		if !errors.Is(err, etcdalloc.ErrNoFreeID) {
+			t.Errorf("Acquire() = %v, want ErrNoFreeID", err)
+		}
+	}
+}
+
+func TestEtcdRelease(t *testing.T) {
+	c := startEtcd(t)
+	ctx := context.Background()
+
+	a := newAllocator(t, c)
+	if id, err := a.Acquire(ctx); err != nil || id != 0 {
+		t.Fatalf("Acquire() = %d, %v, want 0", id, err)
+	}
+
+	b := newAllocator(t, c)
+	if id, err := b.Acquire(ctx); err != nil || id != 1 {
+		t.Fatalf("Acquire() = %d, %v, want 1", id, err)
+	}
+
+	if err := a.Release(); err != nil {
+		t.Fatal(err)
+	}
+
+	// The lowest free worker ID is reused.
+	if id, err := newAllocator(t, c).Acquire(ctx); err != nil || id != 0 {
+		t.Errorf("Acquire() after release = %d, %v, want 0", id, err)
+	}
+}
+
+func TestEtcdLeaseLoss(t *testing.T) {
+	c := startEtcd(t)
+	ctx := context.Background()
+
+	a := newAllocator(t, c)
+	if _, err := a.Acquire(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	resp, err := c.Get(ctx, etcdalloc.DefaultPrefix+"0")
+	if err != nil || len(resp.Kvs) != 1 {
+		t.Fatalf("Get() = %v, %v", resp, err)
+	}
+
+	if _, err := c.Revoke(ctx, clientv3.LeaseID(resp.Kvs[0].Lease)); err != nil {
+		t.Fatal(err)
+	}
+
+	waitLost(t, a)
+	if !errors.Is(a.Err(), etcdalloc.ErrLeaseLost) {
+		t.Errorf("Err() = %v, want ErrLeaseLost", a.Err())
+	}
+
+	if err := a.Renew(ctx); !errors.Is(err, etcdalloc.ErrLeaseLost) {
+		t.Errorf("Renew() = %v, want ErrLeaseLost", err)
+	}
+}
@@ -0,0 +1,143 @@
+// MIT License
+
+// Copyright (c) 2022 Project-Sparrow
+// Copyright (c) 2023 Kelwing <kelwing@kelnet.org>
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package snowflake
+
+import (
+	"net"
+	"sync/atomic"
+	"time"
+)
+
+// FreezeClock pins the clock of the package-level generator to t
+// until the returned function is called.
+func FreezeClock(t time.Time) (restore func()) {
+	g := defaultGenerator.Load()
+	prev := g.clock
+	g.clock = NewManualClock(t)
+	return func() { g.clock = prev }
+}
+
+// FakeClock is a ManualClock that advances when slept on.
+type FakeClock struct {
+	*ManualClock
+	sleeps atomic.Int64
+}
+
+// NewFakeClock creates a FakeClock starting at t.
+func NewFakeClock(t time.Time) *FakeClock {
+	return &FakeClock{ManualClock: NewManualClock(t)}
+}
+
+// Sleep advances the fake time by d, or a nanosecond if d is not positive.
+func (c *FakeClock) Sleep(d time.Duration) {
+	if d <= 0 {
+		d = time.Nanosecond
+	}
+	c.Advance(d)
+	c.sleeps.Add(1)
+}
+
+// Sleeps returns how many times Sleep was called.
+func (c *FakeClock) Sleeps() int {
+	return int(c.sleeps.Load())
+}
+
+// SteppingClock is a Clock with separate wall and monotonic readings,
+// so the wall clock can be stepped without affecting the monotonic one.
+type SteppingClock struct {
+	*ManualClock
+	mono atomic.Int64
+}
+
+// NewSteppingClock creates a SteppingClock with its wall clock at t.
+func NewSteppingClock(t time.Time) *SteppingClock {
+	return &SteppingClock{ManualClock: NewManualClock(t)}
+}
+
+// Monotonic returns the monotonic reading.
+func (c *SteppingClock) Monotonic() time.Duration {
+	return time.Duration(c.mono.Load())
+}
+
+// Advance moves both the wall and monotonic readings forward by d.
+func (c *SteppingClock) Advance(d time.Duration) {
+	c.ManualClock.Advance(d)
+	c.mono.Add(int64(d))
+}
+
+// StepWall moves only the wall clock by d.
+func (c *SteppingClock) StepWall(d time.Duration) {
+	c.ManualClock.Advance(d)
+}
+
+// CloseFuncs returns how many functions g calls on Close.
+func CloseFuncs(g *Generator) int {
+	g.closer.mtx.Lock()
+	defer g.closer.mtx.Unlock()
+	return len(g.closer.funcs)
+}
+
+// HistoricalLen returns how many time units g remembers for GenerateAt.
+func HistoricalLen(g *Generator) int {
+	g.mtx.Lock()
+	defer g.mtx.Unlock()
+	return len(g.historical)
+}
+
+// ResetDefault restores the package-level generator to its state
+// before Init was called.
+func ResetDefault() {
+	defaultGenerator.Store(nil)
+}
+
+// ResetRegistry removes every registered Generator.
+func ResetRegistry() {
+	registry.mtx.Lock()
+	defer registry.mtx.Unlock()
+	registry.generators = make(map[string]*Generator)
+}
+
+// SetInterfaceAddrs makes WorkerIDFromIP list addrs and err instead of
+// the host's interface addresses until the returned function is called.
+func SetInterfaceAddrs(addrs []net.Addr, err error) (restore func()) {
+	prev := interfaceAddrs
+	interfaceAddrs = func() ([]net.Addr, error) { return addrs, err }
+	return func() { interfaceAddrs = prev }
+}
+
+// SetHostname makes WorkerIDFromHostname use name and err instead of
+// the host name until the returned function is called.
+func SetHostname(name string, err error) (restore func()) {
+	prev := hostname
+	hostname = func() (string, error) { return name, err }
+	return func() { hostname = prev }
+}
+
+// SetInterfaces makes WorkerIDFromMAC list ifaces and err instead of
+// the host's interfaces until the returned function is called.
+func SetInterfaces(ifaces []net.Interface, err error) (restore func()) {
+	prev := interfaces
+	interfaces = func() ([]net.Interface, error) { return ifaces, err }
+	return func() { interfaces = prev }
+}
@@ -0,0 +1,76 @@
+// MIT License
+
+// Copyright (c) 2022 Project-Sparrow
+// Copyright (c) 2023 Kelwing <kelwing@kelnet.org>
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package snowflake
+
+import "flag"
+
+var (
+	_ flag.Getter = (*Snowflake)(nil)
+	_ flag.Getter = (*NullSnowflake)(nil)
+)
+
+// Set implements flag.Value interface by parsing value with ParseStrict.
+// With String, which returns "0" for the zero Snowflake, this lets a
+// Snowflake be used as a command-line flag:
+//
+//	var id snowflake.Snowflake
+//	flag.Var(&id, "message-id", "message to delete")
+func (s *Snowflake) Set(value string) error {
+	v, err := ParseStrict(value)
+	if err != nil {
+		return err
+	}
+
+	*s = v
+	return nil
+}
+
+// Get implements flag.Getter interface
+func (s *Snowflake) Get() any {
+	return *s
+}
+
+// Set implements flag.Value interface by parsing value with ParseStrict,
+// or making the NullSnowflake invalid if value is "null". A flag that is
+// never set leaves the NullSnowflake as it was, which is invalid for the
+// zero value.
+func (s *NullSnowflake) Set(value string) error {
+	if value == "null" {
+		s.Snowflake, s.Valid = Snowflake(0), false
+		return nil
+	}
+
+	if err := s.Snowflake.Set(value); err != nil {
+		return err
+	}
+
+	s.Valid = true
+
+	return nil
+}
+
+// Get implements flag.Getter interface
+func (s *NullSnowflake) Get() any {
+	return *s
+}
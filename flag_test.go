@@ -0,0 +1,139 @@
+// MIT License
+
+// Copyright (c) 2022 Project-Sparrow
+// Copyright (c) 2023 Kelwing <kelwing@kelnet.org>
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package snowflake_test
+
+import (
+	"errors"
+	"flag"
+	"io"
+	"strings"
+	"testing"
+
+	"wumpgo.dev/snowflake"
+)
+
+func newFlagSet() (*flag.FlagSet, *snowflake.Snowflake, *snowflake.NullSnowflake) {
+	fs := flag.NewFlagSet("admin", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+
+	var id snowflake.Snowflake
+	var parent snowflake.NullSnowflake
+	fs.Var(&id, "message-id", "message to delete")
+	fs.Var(&parent, "parent-id", "parent of the message")
+
+	return fs, &id, &parent
+}
+
+func TestFlag(t *testing.T) {
+	fs, id, parent := newFlagSet()
+	if err := fs.Parse([]string{"-message-id", "175928847299117063", "-parent-id=42"}); err != nil {
+		t.Fatal(err)
+	}
+
+	if *id != 175928847299117063 {
+		t.Errorf("-message-id = %d, want 175928847299117063", *id)
+	}
+
+	if want := snowflake.NewNullSnowflake(42, true); *parent != want {
+		t.Errorf("-parent-id = %+v, want %+v", *parent, want)
+	}
+
+	if got := fs.Lookup("message-id").Value.(flag.Getter).Get(); got != snowflake.Snowflake(175928847299117063) {
+		t.Errorf("Get() = %v (%T), want Snowflake 175928847299117063", got, got)
+	}
+
+	if got := fs.Lookup("parent-id").Value.(flag.Getter).Get(); got != snowflake.NewNullSnowflake(42, true) {
+		t.Errorf("Get() = %v (%T), want NullSnowflake 42", got, got)
+	}
+
+	if got := fs.Lookup("message-id").Value.String(); got != "175928847299117063" {
+		t.Errorf("String() = %q, want %q", got, "175928847299117063")
+	}
+}
+
+func TestFlagUnset(t *testing.T) {
+	fs, id, parent := newFlagSet()
+	if err := fs.Parse(nil); err != nil {
+		t.Fatal(err)
+	}
+
+	if *id != 0 || parent.Valid {
+		t.Errorf("unset flags = %d, %+v, want 0 and invalid", *id, *parent)
+	}
+
+	if got := fs.Lookup("message-id").Value.String(); got != "0" {
+		t.Errorf("unset -message-id String() = %q, want %q", got, "0")
+	}
+
+	if got := fs.Lookup("parent-id").Value.String(); got != "null" {
+		t.Errorf("unset -parent-id String() = %q, want %q", got, "null")
+	}
+
+	// Zero values are not printed as defaults.
+	var usage strings.Builder
+	fs.SetOutput(&usage)
+	fs.PrintDefaults()
+	if strings.Contains(usage.String(), "default") {
+		t.Errorf("PrintDefaults() = %q, want no defaults", usage.String())
+	}
+}
+
+func TestFlagNull(t *testing.T) {
+	fs, _, parent := newFlagSet()
+	if err := fs.Parse([]string{"-parent-id=42", "-parent-id=null"}); err != nil {
+		t.Fatal(err)
+	}
+
+	if parent.Valid {
+		t.Errorf("-parent-id=null = %+v, want invalid", *parent)
+	}
+}
+
+func TestFlagInvalid(t *testing.T) {
+	for _, arg := range []string{
+		"-message-id=",
+		"-message-id=abc",
+		"-message-id=-1",
+		"-message-id=0123",
+		"-message-id=18446744073709551616",
+		"-parent-id=",
+		"-parent-id=NULL",
+		"-parent-id=1.5",
+	} {
+		fs, id, parent := newFlagSet()
+		err := fs.Parse([]string{arg})
+		if err == nil || !strings.Contains(err.Error(), "invalid value") {
+			t.Errorf("Parse(%q) = %v, want an invalid value error", arg, err)
+		}
+
+		if *id != 0 || parent.Valid {
+			t.Errorf("Parse(%q) set the flag to %d, %+v", arg, *id, *parent)
+		}
+	}
+
+	var id snowflake.Snowflake
+	if err := id.Set("12x"); !errors.Is(err, snowflake.ErrSyntax) {
+		t.Errorf("Set(%q) = %v, want ErrSyntax", "12x", err)
+	}
+}
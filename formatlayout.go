@@ -0,0 +1,271 @@
+// MIT License
+
+// Copyright (c) 2022 Project-Sparrow
+// Copyright (c) 2023 Kelwing <kelwing@kelnet.org>
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package snowflake
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ErrLayout reports a layout that FormatLayout and ParseFormat cannot use.
+var ErrLayout = errors.New("invalid layout")
+
+// formatLayout is a parsed layout for FormatLayout and ParseFormat.
+type formatLayout struct {
+	prefix, suffix string
+	verb           string
+	group          int
+	sep            string
+}
+
+// layoutVerbs are the encodings a layout directive may name.
+var layoutVerbs = map[string]bool{
+	"dec":       true,
+	"dec20":     true,
+	"hex":       true,
+	"HEX":       true,
+	"base32":    true,
+	"base32pad": true,
+}
+
+func parseFormatLayout(layout string) (formatLayout, error) {
+	var l formatLayout
+	var text strings.Builder
+	found := false
+
+	for i := 0; i < len(layout); i++ {
+		switch c := layout[i]; {
+		case (c == '{' || c == '}') && i+1 < len(layout) && layout[i+1] == c:
+			text.WriteByte(c)
+			i++
+		case c == '}':
+			return l, fmt.Errorf("%w %q: unmatched }", ErrLayout, layout)
+		case c == '{':
+			end := strings.IndexByte(layout[i:], '}')
+			if end < 0 {
+				return l, fmt.Errorf("%w %q: unterminated directive", ErrLayout, layout)
+			}
+
+			if found {
+				return l, fmt.Errorf("%w %q: more than one directive", ErrLayout, layout)
+			}
+
+			if err := l.parseDirective(layout[i+1 : i+end]); err != nil {
+				return l, fmt.Errorf("%w %q: %v", ErrLayout, layout, err)
+			}
+
+			l.prefix = text.String()
+			text.Reset()
+			found = true
+			i += end
+		default:
+			text.WriteByte(c)
+		}
+	}
+
+	if !found {
+		return l, fmt.Errorf("%w %q: no directive", ErrLayout, layout)
+	}
+
+	l.suffix = text.String()
+	return l, nil
+}
+
+// parseDirective parses the inside of a directive, NAME or NAME:SIZESEP.
+func (l *formatLayout) parseDirective(d string) error {
+	name, grouping, grouped := strings.Cut(d, ":")
+	if !layoutVerbs[name] {
+		return fmt.Errorf("unknown verb %q", name)
+	}
+	l.verb = name
+
+	if !grouped {
+		return nil
+	}
+
+	n := 0
+	for n < len(grouping) && grouping[n] >= '0' && grouping[n] <= '9' {
+		n++
+	}
+
+	size, err := strconv.Atoi(grouping[:n])
+	if err != nil || size < 1 || size > maxDecimalLen {
+		return fmt.Errorf("group size in %q is not between 1 and %d", d, maxDecimalLen)
+	}
+
+	l.group, l.sep = size, grouping[n:]
+	if l.sep == "" {
+		return fmt.Errorf("no separator in %q", d)
+	}
+
+	// Letters and digits could not be told apart from the encoded digits.
+	if strings.IndexFunc(l.sep, isASCIIAlnum) >= 0 {
+		return fmt.Errorf("separator %q contains a letter or digit", l.sep)
+	}
+
+	return nil
+}
+
+func isASCIIAlnum(r rune) bool {
+	return '0' <= r && r <= '9' || 'a' <= r|0x20 && r|0x20 <= 'z'
+}
+
+func (l *formatLayout) encode(s Snowflake) string {
+	var digits string
+	switch l.verb {
+	case "dec":
+		digits = s.String()
+	case "dec20":
+		digits = s.SortableString()
+	case "hex":
+		digits = s.Hex()
+	case "HEX":
+		digits = strings.ToUpper(s.Hex())
+	case "base32":
+		digits = s.EncodeBase32()
+	case "base32pad":
+		digits = strings.Repeat("0", base32Len-len(s.EncodeBase32())) + s.EncodeBase32()
+	}
+
+	var b strings.Builder
+	b.WriteString(l.prefix)
+	for i := 0; i < len(digits); i++ {
+		// Groups are counted from the right, like thousands separators.
+		if l.group > 0 && i > 0 && (len(digits)-i)%l.group == 0 {
+			b.WriteString(l.sep)
+		}
+		b.WriteByte(digits[i])
+	}
+	b.WriteString(l.suffix)
+
+	return b.String()
+}
+
+// maxLen returns the length of the largest Snowflake formatted with l.
+func (l *formatLayout) maxLen() int {
+	digits := base32Len
+	switch l.verb {
+	case "dec", "dec20":
+		digits = maxDecimalLen
+	case "hex", "HEX":
+		digits = hexLen
+	}
+
+	n := len(l.prefix) + digits + len(l.suffix)
+	if l.group > 0 {
+		n += (digits - 1) / l.group * len(l.sep)
+	}
+
+	return n
+}
+
+func (l *formatLayout) decode(in string) (Snowflake, error) {
+	if max := l.maxLen(); len(in) > max {
+		return 0, fmt.Errorf("%w: formatted Snowflake of %d bytes, want at most %d", ErrTooLong, len(in), max)
+	}
+
+	s, ok := strings.CutPrefix(in, l.prefix)
+	if ok {
+		s, ok = strings.CutSuffix(s, l.suffix)
+	}
+
+	if !ok {
+		return 0, fmt.Errorf("%w: %q does not match %q", ErrSyntax, in, l.prefix+"{"+l.verb+"}"+l.suffix)
+	}
+
+	if l.group > 0 {
+		groups := strings.Split(s, l.sep)
+		for i, g := range groups {
+			if len(g) == 0 || len(g) > l.group || i > 0 && len(g) != l.group {
+				return 0, fmt.Errorf("%w: %q is not in groups of %d separated by %q", ErrSyntax, in, l.group, l.sep)
+			}
+		}
+		s = strings.Join(groups, "")
+	}
+
+	switch l.verb {
+	case "dec":
+		return ParseStrict(s)
+	case "dec20":
+		return ParseSortable(s)
+	case "hex", "HEX":
+		if len(s) != hexLen || strings.ContainsAny(s, "xX") {
+			return 0, fmt.Errorf("%w: %q does not have %d hex digits", ErrSyntax, in, hexLen)
+		}
+		return ParseHex(s)
+	case "base32pad":
+		if len(s) != base32Len {
+			return 0, fmt.Errorf("%w: %q does not have %d base32 digits", ErrSyntax, in, base32Len)
+		}
+	}
+
+	if strings.Contains(s, "-") {
+		return 0, fmt.Errorf("%w: %q has a hyphen that is not a separator", ErrSyntax, in)
+	}
+
+	return ParseBase32(s)
+}
+
+// FormatLayout returns the Snowflake formatted according to layout, which
+// is literal text around exactly one directive in braces. "{{" and "}}"
+// stand for literal braces. The directive names an encoding:
+//
+//	{dec}        decimal, as returned by String
+//	{dec20}      decimal zero-padded to 20 digits, as by SortableString
+//	{hex}        16 lowercase hex digits, as returned by Hex
+//	{HEX}        16 uppercase hex digits
+//	{base32}     Crockford base32, as returned by EncodeBase32
+//	{base32pad}  Crockford base32 zero-padded to 13 digits
+//
+// and may be followed by a colon, a group size and a separator to split the
+// digits into groups counted from the right: "{base32pad:4-}" formats
+// 175928847299117063 as "0-4W86-BB0G-4007", and "id {dec:3,}" as
+// "id 175,928,847,299,117,063".
+//
+// It is named FormatLayout because Format implements fmt.Formatter.
+// Layout errors wrap ErrLayout.
+func (s Snowflake) FormatLayout(layout string) (string, error) {
+	l, err := parseFormatLayout(layout)
+	if err != nil {
+		return "", err
+	}
+
+	return l.encode(s), nil
+}
+
+// ParseFormat parses a Snowflake formatted by FormatLayout with layout.
+// The literal text and the groups must match exactly, while the digits are
+// parsed as by ParseStrict, ParseSortable, ParseHex and ParseBase32, so hex
+// and base32 digits may be in either case. Layout errors wrap ErrLayout,
+// and errors for s wrap ErrSyntax, ErrNegative or ErrOverflow.
+func ParseFormat(layout, s string) (Snowflake, error) {
+	l, err := parseFormatLayout(layout)
+	if err != nil {
+		return 0, err
+	}
+
+	return l.decode(s)
+}
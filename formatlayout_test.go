@@ -0,0 +1,180 @@
+// MIT License
+
+// Copyright (c) 2022 Project-Sparrow
+// Copyright (c) 2023 Kelwing <kelwing@kelnet.org>
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package snowflake_test
+
+import (
+	"errors"
+	"math"
+	"testing"
+	"testing/quick"
+
+	"wumpgo.dev/snowflake"
+)
+
+func TestFormatLayout(t *testing.T) {
+	const id = snowflake.Snowflake(175928847299117063)
+
+	for _, tt := range []struct {
+		layout string
+		s      snowflake.Snowflake
+		want   string
+	}{
+		{"{dec}", id, "175928847299117063"},
+		{"{dec}", 0, "0"},
+		{"{dec20}", id, "00175928847299117063"},
+		{"{hex}", id, "0271065ac1020007"},
+		{"{HEX}", id, "0271065AC1020007"},
+		{"{base32}", id, "4W86BB0G4007"},
+		{"{base32pad}", id, "04W86BB0G4007"},
+		{"{base32pad}", 0, "0000000000000"},
+
+		{"{dec:3,}", id, "175,928,847,299,117,063"},
+		{"{dec:3,}", 1234, "1,234"},
+		{"{dec:3,}", 123, "123"},
+		{"{dec:3 }", math.MaxUint64, "18 446 744 073 709 551 615"},
+		{"{dec20:5-}", 42, "00000-00000-00000-00042"},
+		{"{hex:4:}", id, "0271:065a:c102:0007"},
+		{"{HEX:8 - }", id, "0271065A - C1020007"},
+		{"{base32:4-}", id, "4W86-BB0G-4007"},
+		{"{base32pad:4-}", id, "0-4W86-BB0G-4007"},
+		{"{base32:4-}", 31, "Z"},
+
+		{"id:{dec}", id, "id:175928847299117063"},
+		{"<{hex}>", 1, "<0000000000000001>"},
+		{"{{{dec}}}", 7, "{7}"},
+		{"msg-{base32:4-}-v1", id, "msg-4W86-BB0G-4007-v1"},
+	} {
+		got, err := tt.s.FormatLayout(tt.layout)
+		if err != nil || got != tt.want {
+			t.Errorf("FormatLayout(%q) of %d = %q, %v, want %q", tt.layout, tt.s, got, err, tt.want)
+			continue
+		}
+
+		if back, err := snowflake.ParseFormat(tt.layout, got); err != nil || back != tt.s {
+			t.Errorf("ParseFormat(%q, %q) = %d, %v, want %d", tt.layout, got, back, err, tt.s)
+		}
+	}
+}
+
+func TestFormatLayoutInvalid(t *testing.T) {
+	for _, layout := range []string{
+		"",
+		"plain text",
+		"{}",
+		"{decimal}",
+		"{Hex}",
+		"{dec",
+		"dec}",
+		"{dec}}",
+		"{dec}{hex}",
+		"{dec:}",
+		"{dec:3}",
+		"{dec:0,}",
+		"{dec:21,}",
+		"{dec:,}",
+		"{hex:4a}",
+		"{hex:4 9}",
+		"{{dec}}",
+	} {
+		if got, err := snowflake.Snowflake(1).FormatLayout(layout); !errors.Is(err, snowflake.ErrLayout) {
+			t.Errorf("FormatLayout(%q) = %q, %v, want ErrLayout", layout, got, err)
+		}
+
+		if got, err := snowflake.ParseFormat(layout, "1"); !errors.Is(err, snowflake.ErrLayout) {
+			t.Errorf("ParseFormat(%q) = %d, %v, want ErrLayout", layout, got, err)
+		}
+	}
+}
+
+func TestParseFormat(t *testing.T) {
+	for _, tt := range []struct {
+		layout, in string
+		want       snowflake.Snowflake
+	}{
+		{"{hex}", "0271065AC1020007", 175928847299117063},
+		{"{HEX}", "0271065ac1020007", 175928847299117063},
+		{"{base32:4-}", "4w86-bb0g-4oo7", 175928847299117063},
+		{"{base32pad}", "04W86BB0G4007", 175928847299117063},
+	} {
+		if got, err := snowflake.ParseFormat(tt.layout, tt.in); err != nil || got != tt.want {
+			t.Errorf("ParseFormat(%q, %q) = %d, %v, want %d", tt.layout, tt.in, got, err, tt.want)
+		}
+	}
+
+	for _, tt := range []struct {
+		layout, in string
+		err        error
+	}{
+		{"{dec}", "", snowflake.ErrSyntax},
+		{"{dec}", "0042", snowflake.ErrSyntax},
+		{"{dec}", "-42", snowflake.ErrNegative},
+		{"{dec}", "18446744073709551616", snowflake.ErrOverflow},
+		{"{dec20}", "42", snowflake.ErrSyntax},
+		{"{hex}", "271065ac1020007", snowflake.ErrSyntax},
+		{"{hex}", "0x71065ac1020007", snowflake.ErrSyntax},
+		{"{base32}", "4W86-BB0G4007", snowflake.ErrSyntax},
+		{"{base32}", "4W86-BB0G-4007", snowflake.ErrTooLong},
+		{"{base32pad:4-}", "00-4W86-BB0G-4007", snowflake.ErrTooLong},
+		{"{dec}", "018446744073709551615", snowflake.ErrTooLong},
+		{"{dec:3,}", "18,446,744,073,709,551,615,1", snowflake.ErrTooLong},
+		{"<{hex}>", "<0x0000000000000001>", snowflake.ErrTooLong},
+		{"{base32pad}", "4W86BB0G4007", snowflake.ErrSyntax},
+		{"{dec:3,}", "175928847,299,117,063", snowflake.ErrSyntax},
+		{"{dec:3,}", "1,75,928", snowflake.ErrSyntax},
+		{"{dec:3,}", ",123", snowflake.ErrSyntax},
+		{"{dec:3,}", "123,", snowflake.ErrSyntax},
+		{"{dec:3,}", "1234", snowflake.ErrSyntax},
+		{"id:{dec}", "ID:42", snowflake.ErrSyntax},
+		{"id:{dec}", "id:42 ", snowflake.ErrSyntax},
+		{"<{hex}>", "<0000000000000001", snowflake.ErrSyntax},
+	} {
+		if got, err := snowflake.ParseFormat(tt.layout, tt.in); !errors.Is(err, tt.err) {
+			t.Errorf("ParseFormat(%q, %q) = %d, %v, want %v", tt.layout, tt.in, got, err, tt.err)
+		}
+	}
+}
+
+func TestFormatLayoutRoundTrip(t *testing.T) {
+	layouts := []string{"{dec}", "{dec20:4_}", "{hex:2 }", "{HEX}", "{base32:3.}", "{base32pad:4-}", "x{dec:3,}y"}
+
+	roundTrip := func(v uint64) bool {
+		s := snowflake.Snowflake(v)
+		for _, layout := range layouts {
+			formatted, err := s.FormatLayout(layout)
+			if err != nil {
+				return false
+			}
+
+			if got, err := snowflake.ParseFormat(layout, formatted); err != nil || got != s {
+				t.Logf("ParseFormat(%q, %q) = %d, %v", layout, formatted, got, err)
+				return false
+			}
+		}
+		return true
+	}
+
+	if err := quick.Check(roundTrip, nil); err != nil {
+		t.Error(err)
+	}
+}
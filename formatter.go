@@ -0,0 +1,111 @@
+// MIT License
+
+// Copyright (c) 2022 Project-Sparrow
+// Copyright (c) 2023 Kelwing <kelwing@kelnet.org>
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package snowflake
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Format implements fmt.Formatter interface.
+//
+// %d, %s and %v print the decimal form and %q quotes it. %x and %X print
+// at least 16 hex digits, zero-padded so that they sort like the Snowflakes,
+// and %#x adds a 0x prefix. %+v prints the breakdown returned by
+// DebugString, and %#v prints the value as a Go hex literal.
+// The integer verbs %b, %o, %O, %c and %U print the value as a uint64.
+//
+// Width and the '-' and '0' flags are honored by every verb, and precision
+// by the integer verbs, where it is the minimum number of digits.
+func (s Snowflake) Format(f fmt.State, verb rune) {
+	switch verb {
+	case 'd', 'b', 'o', 'O', 'c', 'U':
+		fmt.Fprintf(f, fmt.FormatString(f, verb), uint64(s))
+	case 'x', 'X':
+		fmt.Fprintf(f, hexDirective(f, verb), uint64(s))
+	case 's', 'q':
+		fmt.Fprintf(f, directive(f, verb), s.String())
+	case 'v':
+		switch {
+		case f.Flag('+'):
+			fmt.Fprintf(f, directive(f, 's'), s.DebugString())
+		case f.Flag('#'):
+			fmt.Fprintf(f, fmt.FormatString(f, verb), uint64(s))
+		default:
+			fmt.Fprintf(f, directive(f, 's'), s.String())
+		}
+	default:
+		fmt.Fprintf(f, "%%!%c(snowflake.Snowflake=%d)", verb, uint64(s))
+	}
+}
+
+// directive rebuilds the flags and width of f for verb, without precision,
+// which would truncate the decimal form.
+func directive(f fmt.State, verb rune) string {
+	var b strings.Builder
+	b.WriteByte('%')
+	for _, c := range "-0" {
+		if f.Flag(int(c)) {
+			b.WriteRune(c)
+		}
+	}
+
+	if f.Flag('#') && verb == 'q' {
+		b.WriteByte('#')
+	}
+
+	if w, ok := f.Width(); ok {
+		b.WriteString(strconv.Itoa(w))
+	}
+
+	b.WriteRune(verb)
+	return b.String()
+}
+
+// hexDirective rebuilds the directive of f for verb with a precision
+// of at least hexLen digits.
+func hexDirective(f fmt.State, verb rune) string {
+	p, ok := f.Precision()
+	if !ok || p < hexLen {
+		p = hexLen
+	}
+
+	var b strings.Builder
+	b.WriteByte('%')
+	for _, c := range "-+# " {
+		if f.Flag(int(c)) {
+			b.WriteRune(c)
+		}
+	}
+
+	if w, ok := f.Width(); ok {
+		b.WriteString(strconv.Itoa(w))
+	}
+
+	b.WriteByte('.')
+	b.WriteString(strconv.Itoa(p))
+	b.WriteRune(verb)
+	return b.String()
+}
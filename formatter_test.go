@@ -0,0 +1,117 @@
+// MIT License
+
+// Copyright (c) 2022 Project-Sparrow
+// Copyright (c) 2023 Kelwing <kelwing@kelnet.org>
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package snowflake_test
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"wumpgo.dev/snowflake"
+)
+
+var _ fmt.Formatter = snowflake.Snowflake(0)
+
+func TestFormat(t *testing.T) {
+	defer snowflake.ResetDefault()
+	snowflake.Init(time.Date(2015, 1, 1, 0, 0, 0, 0, time.UTC), 0, 0)
+
+	const id = snowflake.Snowflake(175928847299117063)
+
+	for _, tt := range []struct {
+		format string
+		s      snowflake.Snowflake
+		want   string
+	}{
+		{"%d", id, "175928847299117063"},
+		{"%22d", id, "    175928847299117063"},
+		{"%-22d|", id, "175928847299117063    |"},
+		{"%022d", id, "0000175928847299117063"},
+		{"%+d", id, "+175928847299117063"},
+		{"%.5d", 42, "00042"},
+		{"%d", snowflake.Snowflake(1<<64 - 1), "18446744073709551615"},
+
+		{"%s", id, "175928847299117063"},
+		{"%22s", id, "    175928847299117063"},
+		{"%-22s|", id, "175928847299117063    |"},
+		{"%05s", 42, "00042"},
+		{"%.3s", id, "175928847299117063"}, // precision does not truncate
+
+		{"%v", id, "175928847299117063"},
+		{"%v", snowflake.Snowflake(0), "0"},
+		{"%6v", 42, "    42"},
+		{"%-6v|", 42, "42    |"},
+		{"%#v", id, "0x271065ac1020007"},
+
+		{"%+v", id, "id=175928847299117063 time=2016-04-30T11:18:25.796Z worker=1 process=0 seq=7"},
+		{"%+v", 0, "id=0 time=2015-01-01T00:00:00Z worker=0 process=0 seq=0"},
+		{"%+60v", 0, "     id=0 time=2015-01-01T00:00:00Z worker=0 process=0 seq=0"},
+
+		{"%x", id, "0271065ac1020007"},
+		{"%X", id, "0271065AC1020007"},
+		{"%x", 0, "0000000000000000"},
+		{"%x", snowflake.Snowflake(1<<64 - 1), "ffffffffffffffff"},
+		{"%#x", id, "0x0271065ac1020007"},
+		{"%#X", id, "0X0271065AC1020007"},
+		{"%20x", id, "    0271065ac1020007"},
+		{"%-20x|", id, "0271065ac1020007    |"},
+		{"%.4x", id, "0271065ac1020007"}, // at least 16 digits
+		{"%.18x", id, "000271065ac1020007"},
+
+		{"%q", id, `"175928847299117063"`},
+		{"%#q", id, "`175928847299117063`"},
+		{"%24q", id, `    "175928847299117063"`},
+
+		{"%b", 5, "101"},
+		{"%o", 8, "10"},
+		{"%O", 8, "0o10"},
+		{"%c", 'A', "A"},
+		{"%U", 'A', "U+0041"},
+
+		{"%t", 42, "%!t(snowflake.Snowflake=42)"},
+		{"%f", 42, "%!f(snowflake.Snowflake=42)"},
+	} {
+		if got := fmt.Sprintf(tt.format, tt.s); got != tt.want {
+			t.Errorf("Sprintf(%q, %d) = %q, want %q", tt.format, uint64(tt.s), got, tt.want)
+		}
+	}
+}
+
+func TestFormatMatchesString(t *testing.T) {
+	for _, s := range []snowflake.Snowflake{0, 1, 175928847299117063, 1<<64 - 1} {
+		for _, format := range []string{"%d", "%s", "%v"} {
+			if got := fmt.Sprintf(format, s); got != s.String() {
+				t.Errorf("Sprintf(%q) = %q, want %q", format, got, s.String())
+			}
+		}
+
+		if got := fmt.Sprint(s); got != s.String() {
+			t.Errorf("Sprint() = %q, want %q", got, s.String())
+		}
+
+		if got := fmt.Sprintf("%x", s); got != s.Hex() {
+			t.Errorf(`Sprintf("%%x") = %q, want %q`, got, s.Hex())
+		}
+	}
+}
@@ -0,0 +1,663 @@
+// MIT License
+
+// Copyright (c) 2022 Project-Sparrow
+// Copyright (c) 2023 Kelwing <kelwing@kelnet.org>
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package snowflake
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+var (
+	// ErrSequenceExhausted is returned by TryGenerate when every sequence
+	// number for the current time unit has already been used.
+	ErrSequenceExhausted = errors.New("sequence exhausted for the current time unit")
+
+	// ErrTimestampOverflow is returned when a time is too far past the epoch
+	// to fit in the timestamp bits.
+	ErrTimestampOverflow = errors.New("timestamp overflows the timestamp bits")
+
+	// ErrClockBackwards is returned when the clock reads earlier than
+	// the last generated Snowflake and the Policy does not allow waiting.
+	ErrClockBackwards = errors.New("clock moved backwards")
+
+	// ErrClockBeforeEpoch is returned when the clock reads earlier than the epoch,
+	// either by New or while generating.
+	ErrClockBeforeEpoch = errors.New("clock is before the epoch")
+)
+
+// Generator generates Snowflakes for a single epoch, worker ID and process ID.
+// Multiple Generators with different configurations can be used side by side.
+type Generator struct {
+	mtx           sync.Mutex
+	epoch         time.Time
+	layout        Layout
+	unit          time.Duration
+	signed        bool
+	workerID      uint16
+	processID     uint16
+	clock         Clock
+	policy        Policy
+	maxWait       time.Duration
+	anchorElapsed time.Duration
+	anchorMono    time.Duration
+	lastTimestamp int64
+	sequence      uint16
+
+	// seqBase and seqMax restrict the sequence to seqBase|0 through seqBase|seqMax,
+	// giving each shard of a Generator using WithShards its own range.
+	seqBase uint16
+	seqMax  uint16
+
+	// With WithRandomSequenceStart, sequence counts the Snowflakes issued in
+	// the current time unit, and the sequence used starts at seqStart and wraps.
+	randomStart bool
+	seqStart    uint16
+
+	// shards are the sub-Generators used by Generate with WithShards, and
+	// nextShard the counter used to spread goroutines across them.
+	shardCount int
+	shards     []*Generator
+	nextShard  atomic.Uint32
+
+	// With WithLockFree, packed holds the last timestamp plus one and the
+	// sequence in place of lastTimestamp and sequence, and lastExhausted the
+	// last timestamp whose exhaustion was reported.
+	lockFree      bool
+	packed        atomic.Uint64
+	lastExhausted int64
+
+	// With WithBorrowing, maxBorrow is how far timestamps may run
+	// ahead of the clock, and borrowTop the highest timestamp borrowed.
+	maxBorrow time.Duration
+	borrowTop int64
+
+	// reservedTop is the last timestamp of the latest Block reserved ahead
+	// of the clock, and reservedAt the clock reading when it was reserved.
+	reservedTop int64
+	reservedAt  int64
+
+	// coarse caches clock readings with WithCoarseClock.
+	coarse *coarseClock
+
+	// allocator hands out the worker ID with WithAllocator.
+	allocator WorkerIDAllocator
+
+	// startTs is the timestamp when the Generator was created,
+	// which GenerateAt stays below so as not to collide with Generate.
+	startTs int64
+
+	// historical tracks the next sequence for each of the last maxHistorical
+	// time units used by GenerateAt, in the order of histOrder from histNext,
+	// and histFloor is the timestamp after the latest one forgotten.
+	historical map[int64]uint16
+	histOrder  []int64
+	histNext   int
+	histFloor  int64
+
+	// given records which options were passed to New.
+	given map[string]bool
+
+	stats generatorStats
+
+	onBackwards func(time.Duration)
+	onExhausted func(time.Time)
+
+	closer closer
+}
+
+// Option configures a Generator created by New.
+type Option func(*Generator) error
+
+// WithEpoch sets the epoch timestamps are relative to.
+// The default is the Unix epoch.
+func WithEpoch(e time.Time) Option {
+	return func(g *Generator) error {
+		if err := g.once("epoch"); err != nil {
+			return err
+		}
+
+		if e.IsZero() {
+			return errors.New("epoch is the zero time")
+		}
+
+		g.epoch = e.Round(0)
+
+		return nil
+	}
+}
+
+// WithWorkerID sets the worker ID embedded in every Snowflake.
+// It must fit in the worker bits of the Generator's Layout.
+// The default is 0.
+func WithWorkerID(id uint16) Option {
+	return func(g *Generator) error {
+		if err := g.once("worker ID"); err != nil {
+			return err
+		}
+
+		g.workerID = id
+
+		return nil
+	}
+}
+
+// WithProcessID sets the process ID embedded in every Snowflake.
+// It must fit in the process bits of the Generator's Layout.
+// The default is 0.
+func WithProcessID(id uint16) Option {
+	return func(g *Generator) error {
+		if err := g.once("process ID"); err != nil {
+			return err
+		}
+
+		g.processID = id
+
+		return nil
+	}
+}
+
+// WithClock makes the Generator read the time from c
+// instead of the system clock.
+func WithClock(c Clock) Option {
+	return func(g *Generator) error {
+		if err := g.once("clock"); err != nil {
+			return err
+		}
+
+		if c == nil {
+			return errors.New("clock is nil")
+		}
+
+		g.clock = c
+
+		return nil
+	}
+}
+
+// WithTimeUnit sets the length of one tick of the timestamp field.
+// Shorter units order Snowflakes more finely within a burst, longer ones
+// make the timestamp bits last longer; the sequence restarts every unit.
+// The default is time.Millisecond.
+func WithTimeUnit(d time.Duration) Option {
+	return func(g *Generator) error {
+		if err := g.once("time unit"); err != nil {
+			return err
+		}
+
+		if d <= 0 {
+			return fmt.Errorf("time unit %v is not positive", d)
+		}
+
+		g.unit = d
+
+		return nil
+	}
+}
+
+// once records that the named option was given,
+// returning an error if it already was.
+func (g *Generator) once(name string) error {
+	if g.given[name] {
+		return fmt.Errorf("%s set more than once", name)
+	}
+
+	g.given[name] = true
+
+	return nil
+}
+
+// New creates a new Generator configured by opts.
+// Without options it uses the Unix epoch, DefaultLayout,
+// worker and process ID 0 and the system clock.
+// An error is returned if an option is invalid or given more than once,
+// and one wrapping ErrClockBeforeEpoch if the epoch is in the future.
+func New(opts ...Option) (*Generator, error) {
+	return newGenerator().configure(opts)
+}
+
+// newGenerator returns a Generator with the defaults documented on New.
+func newGenerator() *Generator {
+	return &Generator{
+		epoch:         EpochUnix,
+		layout:        DefaultLayout,
+		unit:          time.Millisecond,
+		clock:         systemClock{},
+		policy:        PolicyWait,
+		maxWait:       DefaultMaxBackwardsWait,
+		lastTimestamp: -1,
+		borrowTop:     -1,
+		reservedTop:   -1,
+		given:         make(map[string]bool),
+	}
+}
+
+// configure applies opts to g and checks the result.
+func (g *Generator) configure(opts []Option) (*Generator, error) {
+	for _, opt := range opts {
+		if err := opt(g); err != nil {
+			return nil, err
+		}
+	}
+
+	g.given = nil
+
+	if g.workerID > g.layout.MaxWorkerID() {
+		return nil, fmt.Errorf("worker ID %d exceeds maximum %d", g.workerID, g.layout.MaxWorkerID())
+	}
+
+	if g.processID > g.layout.MaxProcessID() {
+		return nil, fmt.Errorf("process ID %d exceeds maximum %d", g.processID, g.layout.MaxProcessID())
+	}
+
+	now := g.clock.Now().Round(0)
+	if now.Before(g.epoch) {
+		return nil, fmt.Errorf("%w: epoch %v is after the current time %v", ErrClockBeforeEpoch, g.epoch, now)
+	}
+
+	g.startTs = g.ticks(now.Sub(g.epoch))
+
+	if m, ok := g.clock.(monotonic); ok {
+		g.anchorElapsed = now.Sub(g.epoch)
+		g.anchorMono = m.Monotonic()
+	}
+
+	g.seqMax = g.layout.MaxSequence()
+
+	if err := g.shard(); err != nil {
+		return nil, err
+	}
+
+	if err := g.checkLockFree(); err != nil {
+		return nil, err
+	}
+
+	if err := g.acquire(); err != nil {
+		return nil, err
+	}
+
+	g.startCoarse()
+
+	return g, nil
+}
+
+// Generate generates a new Snowflake.
+// This function is thread-safe: concurrent calls never return the same
+// timestamp and sequence pair. If the sequence for the current time unit
+// is exhausted, Generate waits for the next time unit.
+// If the clock moves backwards, Generate follows the Generator's Policy
+// and panics where GenerateContext would return ErrClockBackwards.
+func (g *Generator) Generate() Snowflake {
+	s, err := g.generate(context.Background(), true)
+	if err != nil {
+		panic("snowflake: " + err.Error())
+	}
+
+	return s
+}
+
+// GenerateContext is like Generate but stops waiting for the clock
+// and returns ctx.Err() if ctx is done before a Snowflake can be generated.
+func (g *Generator) GenerateContext(ctx context.Context) (Snowflake, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+
+	return g.generate(ctx, true)
+}
+
+// TryGenerate is like Generate but never waits.
+// It returns ErrSequenceExhausted if the sequence for the current time unit
+// is exhausted, and ErrClockBackwards if the clock reads earlier than the
+// last generated Snowflake, unless the Policy is PolicyPanic.
+func (g *Generator) TryGenerate() (Snowflake, error) {
+	return g.generate(context.Background(), false)
+}
+
+// GenerateN generates n Snowflakes at once, holding the Generator's lock
+// for the whole batch rather than once per Snowflake.
+// The Snowflakes are strictly increasing, waiting for following time units
+// as needed. If n is not positive, an empty slice is returned.
+// Like Generate, it panics if the clock moves backwards further than allowed.
+func (g *Generator) GenerateN(n int) []Snowflake {
+	return g.AppendN(make([]Snowflake, 0, max(n, 0)), n)
+}
+
+// AppendN is like GenerateN but appends the Snowflakes to dst
+// and returns the extended slice, only allocating if dst lacks capacity.
+func (g *Generator) AppendN(dst []Snowflake, n int) []Snowflake {
+	if n <= 0 {
+		return dst
+	}
+
+	if err := g.enter(); err != nil {
+		panic("snowflake: " + err.Error())
+	}
+
+	dst = slices.Grow(dst, n)
+
+	var ev events
+	defer g.fire(&ev)
+	defer g.exit()
+
+	if g.lockFree {
+		for i := 0; i < n; i++ {
+			s, err := g.generateLockFree(context.Background(), true, &ev)
+			if err != nil {
+				panic("snowflake: " + err.Error())
+			}
+			dst = append(dst, s)
+		}
+
+		return dst
+	}
+
+	sh := g.lock()
+	defer sh.mtx.Unlock()
+
+	for i := 0; i < n; i++ {
+		s, err := sh.generateLocked(context.Background(), true, &ev)
+		if err != nil {
+			panic("snowflake: " + err.Error())
+		}
+		dst = append(dst, s)
+	}
+
+	return dst
+}
+
+// GenerateAt generates a Snowflake with the timestamp of t instead of
+// the current time, for backfilling historical records.
+// Each time unit passed to GenerateAt gets its own sequence, kept apart
+// from the one used by Generate, and t must be in a time unit before the
+// Generator was created, which Generate never uses.
+// The Generator only remembers the last 1024 distinct time units passed to
+// GenerateAt, and rejects times at or before any it has forgotten, so
+// backfills must go through their records in roughly ascending time order.
+// An error is returned if t is before the epoch, overflows the timestamp bits,
+// is not before the Generator was created or was forgotten, and
+// ErrSequenceExhausted if the sequence for its time unit is exhausted.
+func (g *Generator) GenerateAt(t time.Time) (Snowflake, error) {
+	if err := g.enter(); err != nil {
+		return 0, err
+	}
+
+	var ev events
+	defer g.fire(&ev)
+	defer g.exit()
+
+	ts, err := g.timestampAt(t)
+	if err != nil {
+		return 0, err
+	}
+
+	g.mtx.Lock()
+	defer g.mtx.Unlock()
+
+	if ts >= g.startTs {
+		return 0, fmt.Errorf("time %v is not before the Generator was created at %v", t, g.epoch.Add(time.Duration(g.startTs)*g.unit))
+	}
+
+	if ts < g.histFloor {
+		return 0, fmt.Errorf("time %v is no later than time units GenerateAt has forgotten", t)
+	}
+
+	seq, used := g.historical[ts]
+	if used && seq == 0 {
+		g.exhausted(&ev, ts)
+		return 0, ErrSequenceExhausted
+	}
+
+	if !used {
+		g.remember(ts)
+	}
+
+	g.historical[ts] = (seq + 1) & g.layout.MaxSequence()
+	g.stats.record(seq, seq == g.layout.MaxSequence())
+
+	return g.compose(ts, seq), nil
+}
+
+// maxHistorical is how many time units GenerateAt remembers.
+const maxHistorical = 1024
+
+// remember makes room in g.historical for the new timestamp ts, forgetting
+// the time unit used longest ago once maxHistorical are remembered.
+// The caller must hold g.mtx.
+func (g *Generator) remember(ts int64) {
+	if g.historical == nil {
+		g.historical = make(map[int64]uint16)
+	}
+
+	if len(g.histOrder) < maxHistorical {
+		g.histOrder = append(g.histOrder, ts)
+		return
+	}
+
+	old := g.histOrder[g.histNext]
+	delete(g.historical, old)
+	g.histFloor = max(g.histFloor, old+1)
+
+	g.histOrder[g.histNext] = ts
+	g.histNext = (g.histNext + 1) % maxHistorical
+}
+
+// timestampAt returns the timestamp field for t,
+// or an error if t is before the epoch or overflows the timestamp bits.
+func (g *Generator) timestampAt(t time.Time) (int64, error) {
+	if t.Before(g.epoch) {
+		return 0, fmt.Errorf("time %v is before the epoch %v", t, g.epoch)
+	}
+
+	ts := int64(t.Sub(g.epoch) / g.unit)
+	if uint64(ts) > g.maxTimestamp() {
+		return 0, fmt.Errorf("%w: time %v", ErrTimestampOverflow, t)
+	}
+
+	return ts, nil
+}
+
+// generate mints a new Snowflake, waiting for the clock if wait is true.
+// Hooks for events that occurred are called once g.mtx is released.
+func (g *Generator) generate(ctx context.Context, wait bool) (Snowflake, error) {
+	if err := g.enter(); err != nil {
+		return 0, err
+	}
+
+	var ev events
+	defer g.fire(&ev)
+	defer g.exit()
+
+	if g.lockFree {
+		return g.generateLockFree(ctx, wait, &ev)
+	}
+
+	sh := g.lock()
+	defer sh.mtx.Unlock()
+
+	return sh.generateLocked(ctx, wait, &ev)
+}
+
+// generateLocked is like generate but the caller must hold g.mtx,
+// and events are recorded in ev for the caller to fire.
+func (g *Generator) generateLocked(ctx context.Context, wait bool, ev *events) (Snowflake, error) {
+	now := g.timestamp()
+	if now < 0 {
+		return 0, fmt.Errorf("%w: epoch %v is after the current time %v", ErrClockBeforeEpoch, g.epoch, g.clock.Now())
+	}
+
+	ts := now
+	if ts < g.lastTimestamp {
+		if g.borrowedAhead(now) {
+			ts = g.lastTimestamp
+		} else {
+			var err error
+			if ts, err = g.clockBackwards(ctx, ts, wait, ev); err != nil {
+				return 0, err
+			}
+		}
+	}
+
+	if ts == g.lastTimestamp && g.sequence == g.seqMax {
+		if g.borrow(now) {
+			ts = g.lastTimestamp + 1
+		} else {
+			g.exhausted(ev, ts)
+
+			if !wait {
+				return 0, ErrSequenceExhausted
+			}
+
+			var err error
+			if ts, err = g.waitFor(ctx, g.lastTimestamp+1); err != nil {
+				return 0, err
+			}
+		}
+	}
+
+	if uint64(ts) > g.maxTimestamp() {
+		return 0, fmt.Errorf("%w: timestamp %d", ErrTimestampOverflow, ts)
+	}
+
+	return g.next(ts), nil
+}
+
+// next mints the Snowflake following the last generated one at timestamp ts,
+// which must not be before the last generated timestamp.
+// The caller must hold g.mtx.
+func (g *Generator) next(ts int64) Snowflake {
+	if ts == g.lastTimestamp {
+		g.sequence++
+	} else {
+		g.sequence = 0
+		if g.randomStart {
+			g.seqStart = randomSequence(g.seqMax)
+		}
+	}
+
+	g.lastTimestamp = ts
+
+	seq := g.seqBase | (g.seqStart+g.sequence)&g.seqMax
+	g.stats.record(g.sequence, g.sequence == g.seqMax)
+
+	return g.compose(ts, seq)
+}
+
+// compose builds a Snowflake from the Generator's worker and process IDs.
+func (g *Generator) compose(ts int64, seq uint16) Snowflake {
+	return g.layout.compose(uint64(ts), g.workerID, g.processID, seq)
+}
+
+// Layout returns the Layout of the Snowflakes the Generator generates.
+func (g *Generator) Layout() Layout {
+	return g.layout
+}
+
+// TimeUnit returns the length of one tick of the Generator's timestamp field.
+func (g *Generator) TimeUnit() time.Duration {
+	return g.unit
+}
+
+// Time decodes the time component of a Snowflake generated by g,
+// truncated to the Generator's time unit.
+func (g *Generator) Time(s Snowflake) time.Time {
+	return g.epoch.Add(time.Duration(g.layout.Timestamp(s)) * g.unit)
+}
+
+// Deconstruct decodes every component of a Snowflake generated by g.
+func (g *Generator) Deconstruct(s Snowflake) Parts {
+	return Parts{
+		Time:      g.Time(s),
+		WorkerID:  g.layout.WorkerID(s),
+		ProcessID: g.layout.ProcessID(s),
+		Sequence:  g.layout.Sequence(s),
+	}
+}
+
+// timestamp returns the time units elapsed since the epoch.
+// If the clock has a monotonic reading, the time advances by it from an anchor
+// taken from the wall clock, and the anchor is only moved forward to the
+// wall clock when it is ahead, so steps of the wall clock backwards are ignored.
+// With WithLockFree the anchor never moves, so timestamp only reads g.
+// Otherwise the caller must hold g.mtx.
+func (g *Generator) timestamp() int64 {
+	now, mono, ok := g.read()
+
+	wall := now.Round(0).Sub(g.epoch)
+	if !ok {
+		return g.ticks(wall)
+	}
+
+	elapsed := g.anchorElapsed + (mono - g.anchorMono)
+	if wall > elapsed {
+		if !g.lockFree {
+			g.anchorElapsed, g.anchorMono = wall, mono
+		}
+		elapsed = wall
+	}
+
+	return g.ticks(elapsed)
+}
+
+// ticks converts d to time units, rounding down so that
+// durations before the epoch give negative timestamps.
+func (g *Generator) ticks(d time.Duration) int64 {
+	ts := d / g.unit
+	if d%g.unit < 0 {
+		ts--
+	}
+
+	return int64(ts)
+}
+
+// waitFor sleeps until the clock reaches the timestamp target and returns
+// the new timestamp, or returns ctx.Err() if ctx is done first.
+// Sleeps are capped at a millisecond so cancellation is noticed promptly.
+// The caller must hold g.mtx.
+func (g *Generator) waitFor(ctx context.Context, target int64) (int64, error) {
+	next := g.epoch.Add(time.Duration(target) * g.unit)
+
+	g.coarse.refresh()
+
+	ts := g.timestamp()
+	if ts < target {
+		g.stats.waits.Add(1)
+	}
+
+	for ts < target {
+		if err := ctx.Err(); err != nil {
+			return 0, err
+		}
+
+		sleepOn(g.clock, min(next.Sub(g.clock.Now()), time.Millisecond))
+		g.coarse.refresh()
+		ts = g.timestamp()
+	}
+
+	return ts, nil
+}
@@ -0,0 +1,646 @@
+// MIT License
+
+// Copyright (c) 2022 Project-Sparrow
+// Copyright (c) 2023 Kelwing <kelwing@kelnet.org>
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package snowflake_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"wumpgo.dev/snowflake"
+)
+
+func TestNew(t *testing.T) {
+	g, err := snowflake.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	before := time.Now()
+	s := g.Generate()
+	if s.WorkerID() != 0 || s.ProcessID() != 0 {
+		t.Errorf("default Generate() = %+v, want worker and process 0", s.Deconstruct())
+	}
+
+	if ts := s.TimeWithEpoch(time.UnixMilli(0)); ts.Before(before.Add(-time.Millisecond)) || ts.After(time.Now()) {
+		t.Errorf("default Generate() time = %v, want relative to the Unix epoch", ts)
+	}
+}
+
+func TestNewInvalid(t *testing.T) {
+	epoch := time.Now()
+
+	tests := []struct {
+		name string
+		opts []snowflake.Option
+	}{
+		{"zero epoch", []snowflake.Option{snowflake.WithEpoch(time.Time{})}},
+		{"two epochs", []snowflake.Option{snowflake.WithEpoch(epoch), snowflake.WithEpoch(epoch.Add(-time.Hour))}},
+		{"worker too large", []snowflake.Option{snowflake.WithWorkerID(32)}},
+		{"two worker IDs", []snowflake.Option{snowflake.WithWorkerID(1), snowflake.WithWorkerID(2)}},
+		{"process too large", []snowflake.Option{snowflake.WithProcessID(32)}},
+		{"two process IDs", []snowflake.Option{snowflake.WithProcessID(1), snowflake.WithProcessID(1)}},
+		{"two clocks", []snowflake.Option{snowflake.WithClock(snowflake.NewManualClock(epoch)), snowflake.WithClock(snowflake.NewManualClock(epoch))}},
+		{"future epoch", []snowflake.Option{snowflake.WithEpoch(time.Now().Add(time.Hour))}},
+		{"zero time unit", []snowflake.Option{snowflake.WithTimeUnit(0)}},
+		{"negative time unit", []snowflake.Option{snowflake.WithTimeUnit(-time.Millisecond)}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := snowflake.New(tt.opts...); err == nil {
+				t.Error("expected error")
+			}
+		})
+	}
+}
+
+func TestGeneratorsConcurrent(t *testing.T) {
+	epoch := time.Now().Add(-time.Hour)
+	const n = 1000
+
+	a, err := snowflake.New(snowflake.WithEpoch(epoch), snowflake.WithWorkerID(1))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	b, err := snowflake.New(snowflake.WithEpoch(epoch), snowflake.WithWorkerID(2))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	results := make([][]snowflake.Snowflake, 2)
+	var wg sync.WaitGroup
+	for i, g := range []*snowflake.Generator{a, b} {
+		wg.Add(1)
+		go func(i int, g *snowflake.Generator) {
+			defer wg.Done()
+			for j := 0; j < n; j++ {
+				results[i] = append(results[i], g.Generate())
+			}
+		}(i, g)
+	}
+	wg.Wait()
+
+	seen := make(map[snowflake.Snowflake]bool, 2*n)
+	for i, ids := range results {
+		for _, s := range ids {
+			if s.WorkerID() != uint8(i+1) {
+				t.Fatalf("WorkerID() = %d, want %d", s.WorkerID(), i+1)
+			}
+
+			if seen[s] {
+				t.Fatalf("duplicate Snowflake %d", s)
+			}
+			seen[s] = true
+		}
+	}
+}
+
+func TestGenerateConcurrentUnique(t *testing.T) {
+	g, err := snowflake.New(snowflake.WithEpoch(time.Now().Add(-time.Hour)), snowflake.WithWorkerID(1), snowflake.WithProcessID(1))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	const goroutines = 32
+	const n = 10000
+
+	results := make([][]snowflake.Snowflake, goroutines)
+	var wg sync.WaitGroup
+	for i := range results {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			ids := make([]snowflake.Snowflake, n)
+			for j := range ids {
+				ids[j] = g.Generate()
+			}
+			results[i] = ids
+		}(i)
+	}
+	wg.Wait()
+
+	seen := make(map[snowflake.Snowflake]bool, goroutines*n)
+	for _, ids := range results {
+		for _, s := range ids {
+			if seen[s] {
+				t.Fatalf("duplicate Snowflake %d", s)
+			}
+			seen[s] = true
+		}
+	}
+}
+
+func TestGenerateSequenceExhausted(t *testing.T) {
+	epoch := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	clock := snowflake.NewFakeClock(epoch.Add(time.Hour))
+
+	g, err := snowflake.New(snowflake.WithEpoch(epoch), snowflake.WithWorkerID(1), snowflake.WithProcessID(1), snowflake.WithClock(clock))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	seen := make(map[snowflake.Snowflake]bool)
+	var last snowflake.Snowflake
+	for i := 0; i < 4097; i++ {
+		s := g.Generate()
+		if seen[s] {
+			t.Fatalf("duplicate Snowflake %d", s)
+		}
+		seen[s] = true
+		last = s
+	}
+
+	if clock.Sleeps() == 0 {
+		t.Error("Generate did not wait for the next millisecond")
+	}
+
+	if want := epoch.Add(time.Hour + time.Millisecond); !last.TimeWithEpoch(epoch).Equal(want) {
+		t.Errorf("4097th Snowflake time = %v, want %v", last.TimeWithEpoch(epoch), want)
+	}
+
+	if last.Sequence() != 0 {
+		t.Errorf("4097th Snowflake sequence = %d, want 0", last.Sequence())
+	}
+}
+
+func TestTryGenerate(t *testing.T) {
+	epoch := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	start := epoch.Add(time.Hour)
+	clock := snowflake.NewFakeClock(start)
+
+	g, err := snowflake.New(snowflake.WithEpoch(epoch), snowflake.WithWorkerID(1), snowflake.WithProcessID(1), snowflake.WithClock(clock))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 0; i < 4096; i++ {
+		if _, err := g.TryGenerate(); err != nil {
+			t.Fatalf("TryGenerate() #%d = %v", i, err)
+		}
+	}
+
+	if _, err := g.TryGenerate(); !errors.Is(err, snowflake.ErrSequenceExhausted) {
+		t.Errorf("TryGenerate() = %v, want ErrSequenceExhausted", err)
+	}
+
+	clock.Set(start.Add(-5 * time.Millisecond))
+	if _, err := g.TryGenerate(); !errors.Is(err, snowflake.ErrClockBackwards) {
+		t.Errorf("TryGenerate() = %v, want ErrClockBackwards", err)
+	}
+
+	clock.Set(start.Add(time.Millisecond))
+	s, err := g.TryGenerate()
+	if err != nil {
+		t.Fatalf("TryGenerate() = %v", err)
+	}
+
+	if s.Sequence() != 0 {
+		t.Errorf("Sequence() = %d, want 0", s.Sequence())
+	}
+
+	if clock.Sleeps() != 0 {
+		t.Error("TryGenerate waited for the clock")
+	}
+}
+
+func TestGenerateContext(t *testing.T) {
+	epoch := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	g, err := snowflake.New(snowflake.WithEpoch(epoch), snowflake.WithWorkerID(1), snowflake.WithProcessID(1))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if _, err := g.GenerateContext(ctx); !errors.Is(err, context.Canceled) {
+		t.Errorf("GenerateContext(cancelled) = %v, want context.Canceled", err)
+	}
+
+	if _, err := g.GenerateContext(context.Background()); err != nil {
+		t.Errorf("GenerateContext() = %v", err)
+	}
+}
+
+func TestGenerateContextCancelWhileWaiting(t *testing.T) {
+	epoch := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	clock := snowflake.NewManualClock(epoch.Add(time.Hour))
+
+	g, err := snowflake.New(snowflake.WithEpoch(epoch), snowflake.WithWorkerID(1), snowflake.WithProcessID(1), snowflake.WithClock(clock))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 0; i < 4096; i++ {
+		g.Generate()
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(10*time.Millisecond, cancel)
+
+	if _, err := g.GenerateContext(ctx); !errors.Is(err, context.Canceled) {
+		t.Errorf("GenerateContext() = %v, want context.Canceled", err)
+	}
+}
+
+func TestWithClock(t *testing.T) {
+	epoch := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	clock := snowflake.NewManualClock(epoch.Add(time.Hour))
+
+	g, err := snowflake.New(snowflake.WithEpoch(epoch), snowflake.WithWorkerID(1), snowflake.WithProcessID(1), snowflake.WithClock(clock))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if s := g.Generate(); !s.TimeWithEpoch(epoch).Equal(clock.Now()) {
+		t.Errorf("Generate() time = %v, want %v", s.TimeWithEpoch(epoch), clock.Now())
+	}
+
+	clock.Advance(time.Minute)
+	if s := g.Generate(); !s.TimeWithEpoch(epoch).Equal(clock.Now()) {
+		t.Errorf("Generate() time after Advance = %v, want %v", s.TimeWithEpoch(epoch), clock.Now())
+	}
+
+	if _, err := snowflake.New(snowflake.WithEpoch(epoch), snowflake.WithWorkerID(1), snowflake.WithProcessID(1), snowflake.WithClock(nil)); err == nil {
+		t.Error("expected error for nil clock")
+	}
+}
+
+func TestManualClockUnblocksGenerate(t *testing.T) {
+	epoch := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	clock := snowflake.NewManualClock(epoch.Add(time.Hour))
+
+	g, err := snowflake.New(snowflake.WithEpoch(epoch), snowflake.WithWorkerID(1), snowflake.WithProcessID(1), snowflake.WithClock(clock))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 0; i < 4096; i++ {
+		g.Generate()
+	}
+
+	time.AfterFunc(10*time.Millisecond, func() { clock.Advance(time.Millisecond) })
+
+	if s := g.Generate(); !s.TimeWithEpoch(epoch).Equal(epoch.Add(time.Hour + time.Millisecond)) {
+		t.Errorf("Generate() time = %v, want next millisecond", s.TimeWithEpoch(epoch))
+	}
+}
+
+func TestGenerateMonotonic(t *testing.T) {
+	epoch := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	start := epoch.Add(time.Hour)
+	clock := snowflake.NewSteppingClock(start)
+
+	g, err := snowflake.New(snowflake.WithEpoch(epoch), snowflake.WithWorkerID(1), snowflake.WithProcessID(1), snowflake.WithClock(clock))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	at := func(d time.Duration) time.Time { return start.Add(d) }
+
+	s1 := g.Generate()
+	clock.Advance(time.Millisecond)
+	s2 := g.Generate()
+
+	// A step of the wall clock backwards is ignored.
+	clock.StepWall(-time.Second)
+	s3 := g.Generate()
+	clock.Advance(time.Millisecond)
+	s4 := g.Generate()
+
+	// A step of the wall clock forwards is followed.
+	clock.StepWall(time.Hour)
+	s5 := g.Generate()
+
+	want := []time.Time{at(0), at(time.Millisecond), at(time.Millisecond), at(2 * time.Millisecond), at(time.Hour - time.Second + 2*time.Millisecond)}
+	for i, s := range []snowflake.Snowflake{s1, s2, s3, s4, s5} {
+		if got := s.TimeWithEpoch(epoch); !got.Equal(want[i]) {
+			t.Errorf("Snowflake %d time = %v, want %v", i+1, got, want[i])
+		}
+	}
+
+	if !(s1 < s2 && s2 < s3 && s3 < s4 && s4 < s5) {
+		t.Errorf("Snowflakes out of order: %d %d %d %d %d", s1, s2, s3, s4, s5)
+	}
+}
+
+func TestGenerateAt(t *testing.T) {
+	epoch := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	g, err := snowflake.New(snowflake.WithEpoch(epoch), snowflake.WithWorkerID(3), snowflake.WithProcessID(4))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	historical := epoch.Add(24 * time.Hour)
+
+	var prev snowflake.Snowflake
+	for i := 0; i < 10; i++ {
+		s, err := g.GenerateAt(historical)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if !s.TimeWithEpoch(epoch).Equal(historical) {
+			t.Errorf("GenerateAt() time = %v, want %v", s.TimeWithEpoch(epoch), historical)
+		}
+
+		if s.Sequence() != uint16(i) || s.WorkerID() != 3 || s.ProcessID() != 4 {
+			t.Errorf("GenerateAt() = %+v", s.Deconstruct())
+		}
+
+		if i > 0 && s <= prev {
+			t.Errorf("GenerateAt() = %d, want > %d", s, prev)
+		}
+		prev = s
+	}
+
+	// Interleaving another millisecond keeps both sequences going.
+	if s, _ := g.GenerateAt(historical.Add(time.Millisecond)); s.Sequence() != 0 {
+		t.Errorf("GenerateAt(next ms) sequence = %d, want 0", s.Sequence())
+	}
+
+	if s, _ := g.GenerateAt(historical); s.Sequence() != 10 {
+		t.Errorf("GenerateAt() sequence = %d, want 10", s.Sequence())
+	}
+
+	// The Generate path is untouched.
+	if s := g.Generate(); s.Sequence() != 0 {
+		t.Errorf("Generate() sequence = %d, want 0", s.Sequence())
+	}
+}
+
+func TestGenerateAtInvalid(t *testing.T) {
+	epoch := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	g, err := snowflake.New(snowflake.WithEpoch(epoch))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := g.GenerateAt(epoch.Add(-time.Millisecond)); err == nil {
+		t.Error("expected error for time before epoch")
+	}
+
+	if _, err := g.GenerateAt(epoch.Add(1 << 42 * time.Millisecond)); err == nil {
+		t.Error("expected error for time overflowing the timestamp bits")
+	}
+
+	// Times Generate may use are left to it.
+	if _, err := g.GenerateAt(time.Now()); err == nil {
+		t.Error("expected error for time after the Generator was created")
+	}
+
+	for i := 0; i < 4096; i++ {
+		if _, err := g.GenerateAt(epoch); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if _, err := g.GenerateAt(epoch); !errors.Is(err, snowflake.ErrSequenceExhausted) {
+		t.Errorf("GenerateAt() = %v, want ErrSequenceExhausted", err)
+	}
+}
+
+func TestGenerateAtBounded(t *testing.T) {
+	epoch := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	g, err := snowflake.New(snowflake.WithEpoch(epoch))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// A day of backfill at one record a second.
+	start := epoch.Add(24 * time.Hour)
+	for i := 0; i < 86400; i++ {
+		if _, err := g.GenerateAt(start.Add(time.Duration(i) * time.Second)); err != nil {
+			t.Fatalf("GenerateAt() #%d = %v", i, err)
+		}
+	}
+
+	if n := snowflake.HistoricalLen(g); n != 1024 {
+		t.Errorf("GenerateAt remembers %d time units, want 1024", n)
+	}
+
+	// Forgotten time units must not have their sequence reused.
+	if _, err := g.GenerateAt(start); err == nil {
+		t.Error("GenerateAt() of a forgotten time unit succeeded, want error")
+	}
+
+	last := start.Add(86399 * time.Second)
+	if s, err := g.GenerateAt(last); err != nil || s.Sequence() != 1 {
+		t.Errorf("GenerateAt() of a remembered time unit = %v, %v, want sequence 1", s.Sequence(), err)
+	}
+}
+
+func TestGenerateN(t *testing.T) {
+	epoch := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	clock := snowflake.NewFakeClock(epoch.Add(time.Hour))
+
+	g, err := snowflake.New(snowflake.WithEpoch(epoch), snowflake.WithWorkerID(1), snowflake.WithProcessID(1), snowflake.WithClock(clock))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ids := g.GenerateN(10000)
+	if len(ids) != 10000 {
+		t.Fatalf("len(GenerateN(10000)) = %d", len(ids))
+	}
+
+	for i := 1; i < len(ids); i++ {
+		if ids[i] <= ids[i-1] {
+			t.Fatalf("GenerateN()[%d] = %d, want > %d", i, ids[i], ids[i-1])
+		}
+	}
+
+	if d := ids[len(ids)-1].TimeWithEpoch(epoch).Sub(ids[0].TimeWithEpoch(epoch)); d != 2*time.Millisecond {
+		t.Errorf("GenerateN(10000) spanned %v, want 2ms", d)
+	}
+
+	for _, n := range []int{0, -1} {
+		if ids := g.GenerateN(n); ids == nil || len(ids) != 0 {
+			t.Errorf("GenerateN(%d) = %v, want empty slice", n, ids)
+		}
+	}
+}
+
+func TestAppendN(t *testing.T) {
+	epoch := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	g, err := snowflake.New(snowflake.WithEpoch(epoch), snowflake.WithWorkerID(1), snowflake.WithProcessID(1), snowflake.WithClock(snowflake.NewFakeClock(epoch.Add(time.Hour))))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dst := g.AppendN([]snowflake.Snowflake{42}, 3)
+	if len(dst) != 4 || dst[0] != 42 || !(dst[1] < dst[2] && dst[2] < dst[3]) {
+		t.Errorf("AppendN() = %v", dst)
+	}
+
+	if got := g.AppendN(dst, 0); len(got) != len(dst) {
+		t.Errorf("AppendN(dst, 0) changed length to %d", len(got))
+	}
+
+	buf := make([]snowflake.Snowflake, 0, 100)
+	allocs := testing.AllocsPerRun(100, func() {
+		buf = g.AppendN(buf[:0], 100)
+	})
+
+	if allocs != 0 {
+		t.Errorf("AppendN() with capacity allocated %v times, want 0", allocs)
+	}
+}
+
+// benchGenerator returns a Generator whose clock skips ahead instead of
+// sleeping, so benchmarks aren't bound by the 4096 Snowflakes per millisecond.
+func TestGenerateClockBeforeEpoch(t *testing.T) {
+	epoch := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	clock := snowflake.NewManualClock(epoch)
+
+	if _, err := snowflake.New(snowflake.WithEpoch(epoch), snowflake.WithClock(snowflake.NewManualClock(epoch.Add(-time.Nanosecond)))); !errors.Is(err, snowflake.ErrClockBeforeEpoch) {
+		t.Errorf("New() with the clock before the epoch = %v, want ErrClockBeforeEpoch", err)
+	}
+
+	g, err := snowflake.New(snowflake.WithEpoch(epoch), snowflake.WithClock(clock))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	clock.Set(epoch.Add(-time.Microsecond))
+
+	if _, err := g.TryGenerate(); !errors.Is(err, snowflake.ErrClockBeforeEpoch) {
+		t.Errorf("TryGenerate() = %v, want ErrClockBeforeEpoch", err)
+	}
+
+	if _, err := g.GenerateContext(context.Background()); !errors.Is(err, snowflake.ErrClockBeforeEpoch) {
+		t.Errorf("GenerateContext() = %v, want ErrClockBeforeEpoch", err)
+	}
+
+	clock.Set(epoch)
+
+	if s, err := g.TryGenerate(); err != nil || !g.Time(s).Equal(epoch) {
+		t.Errorf("TryGenerate() at the epoch = %d, %v", s, err)
+	}
+}
+
+func TestWithTimeUnit(t *testing.T) {
+	epoch := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	for _, unit := range []time.Duration{time.Millisecond, 10 * time.Millisecond, 100 * time.Microsecond} {
+		t.Run(unit.String(), func(t *testing.T) {
+			start := epoch.Add(time.Hour + 3*unit)
+			clock := snowflake.NewFakeClock(start.Add(unit / 2))
+
+			g, err := snowflake.New(snowflake.WithEpoch(epoch), snowflake.WithTimeUnit(unit), snowflake.WithClock(clock))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if g.TimeUnit() != unit {
+				t.Errorf("TimeUnit() = %v, want %v", g.TimeUnit(), unit)
+			}
+
+			ids := g.GenerateN(4097)
+			if got := g.Time(ids[0]); !got.Equal(start) {
+				t.Errorf("Time() = %v, want %v", got, start)
+			}
+
+			if got, want := g.Time(ids[4096]), start.Add(unit); !got.Equal(want) || g.Deconstruct(ids[4096]).Sequence != 0 {
+				t.Errorf("4097th Snowflake = %+v, want time %v and sequence 0", g.Deconstruct(ids[4096]), want)
+			}
+
+			at := epoch.Add(30*time.Minute + 7*unit)
+			s, err := g.GenerateAt(at.Add(unit - 1))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if got := g.Time(s); !got.Equal(at) {
+				t.Errorf("GenerateAt Time() = %v, want %v", got, at)
+			}
+
+			lo, err := g.FirstForTime(at)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			hi, err := g.LastForTime(at)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if s < lo || s > hi {
+				t.Errorf("GenerateAt = %d, want between %d and %d", s, lo, hi)
+			}
+
+			if !g.Time(lo).Equal(at) || !g.Time(hi).Equal(at) {
+				t.Errorf("FirstForTime and LastForTime times = %v, %v, want %v", g.Time(lo), g.Time(hi), at)
+			}
+		})
+	}
+}
+
+func benchGenerator(b *testing.B) *snowflake.Generator {
+	epoch := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	g, err := snowflake.New(snowflake.WithEpoch(epoch), snowflake.WithWorkerID(1), snowflake.WithProcessID(1), snowflake.WithClock(snowflake.NewFakeClock(epoch.Add(time.Hour))))
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	return g
+}
+
+func BenchmarkGenerate(b *testing.B) {
+	g := benchGenerator(b)
+
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			g.Generate()
+		}
+	})
+}
+
+func BenchmarkGenerateLoop100(b *testing.B) {
+	g := benchGenerator(b)
+
+	b.RunParallel(func(pb *testing.PB) {
+		ids := make([]snowflake.Snowflake, 100)
+		for pb.Next() {
+			for i := range ids {
+				ids[i] = g.Generate()
+			}
+		}
+	})
+}
+
+func BenchmarkGenerateN100(b *testing.B) {
+	g := benchGenerator(b)
+
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			g.GenerateN(100)
+		}
+	})
+}
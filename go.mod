@@ -1,3 +1,3 @@
 module wumpgo.dev/snowflake
 
-go 1.20
+go 1.21
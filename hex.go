@@ -0,0 +1,66 @@
+// MIT License
+
+// Copyright (c) 2022 Project-Sparrow
+// Copyright (c) 2023 Kelwing <kelwing@kelnet.org>
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package snowflake
+
+import (
+	"encoding/hex"
+	"fmt"
+	"strconv"
+)
+
+// hexLen is the length of a Snowflake in zero-padded hexadecimal.
+const hexLen = 16
+
+// Hex returns the Snowflake as 16 lowercase hexadecimal digits, zero-padded
+// so that Hex strings sort in the same order as the Snowflakes.
+func (s Snowflake) Hex() string {
+	b := s.Bytes()
+	return hex.EncodeToString(b[:])
+}
+
+// ParseHex parses a Snowflake written in hexadecimal, in either case and
+// with an optional "0x" prefix. Unpadded input such as "0x1f" is accepted,
+// but more than 16 digits is an error even if the extra ones are zeros.
+// Errors wrap ErrSyntax, or ErrTooLong for input longer than 18 bytes.
+func ParseHex(s string) (Snowflake, error) {
+	if len(s) > hexLen+2 {
+		return 0, fmt.Errorf("%w: hex Snowflake of %d bytes, want at most %d", ErrTooLong, len(s), hexLen+2)
+	}
+
+	digits := s
+	if len(digits) >= 2 && digits[0] == '0' && (digits[1] == 'x' || digits[1] == 'X') {
+		digits = digits[2:]
+	}
+
+	if len(digits) == 0 || len(digits) > hexLen {
+		return 0, fmt.Errorf("%w: hex Snowflake %q has %d digits, want 1 to %d", ErrSyntax, s, len(digits), hexLen)
+	}
+
+	n, err := strconv.ParseUint(digits, 16, 64)
+	if err != nil {
+		return 0, fmt.Errorf("%w: hex Snowflake %q is not hexadecimal", ErrSyntax, s)
+	}
+
+	return Snowflake(n), nil
+}
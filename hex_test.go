@@ -0,0 +1,127 @@
+// MIT License
+
+// Copyright (c) 2022 Project-Sparrow
+// Copyright (c) 2023 Kelwing <kelwing@kelnet.org>
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package snowflake_test
+
+import (
+	"errors"
+	"math"
+	"math/rand"
+	"slices"
+	"testing"
+
+	"wumpgo.dev/snowflake"
+)
+
+func TestHex(t *testing.T) {
+	for _, tt := range []struct {
+		s    snowflake.Snowflake
+		want string
+	}{
+		{0, "0000000000000000"},
+		{1, "0000000000000001"},
+		{0xabcdef, "0000000000abcdef"},
+		{175928847299117063, "0271065ac1020007"},
+		{math.MaxUint64, "ffffffffffffffff"},
+	} {
+		if got := tt.s.Hex(); got != tt.want {
+			t.Errorf("Hex(%d) = %q, want %q", tt.s, got, tt.want)
+		}
+
+		if got, err := snowflake.ParseHex(tt.want); err != nil || got != tt.s {
+			t.Errorf("ParseHex(%q) = %d, %v, want %d", tt.want, got, err, tt.s)
+		}
+	}
+}
+
+func TestParseHex(t *testing.T) {
+	for _, tt := range []struct {
+		in   string
+		want snowflake.Snowflake
+	}{
+		{"0271065AC1020007", 175928847299117063},
+		{"0x0271065ac1020007", 175928847299117063},
+		{"0X0271065AC1020007", 175928847299117063},
+		{"271065ac1020007", 175928847299117063},
+		{"0x1f", 31},
+		{"0", 0},
+		{"0x0", 0},
+		{"FFFFFFFFFFFFFFFF", math.MaxUint64},
+	} {
+		if got, err := snowflake.ParseHex(tt.in); err != nil || got != tt.want {
+			t.Errorf("ParseHex(%q) = %d, %v, want %d", tt.in, got, err, tt.want)
+		}
+	}
+
+	for _, in := range []string{
+		"",
+		"0x",
+		"x1f",
+		"0x0x1f",
+		"00000000000000000", // 17 digits
+		"-1",
+		"+1",
+		"0x_1f",
+		"1g",
+		" 1f",
+	} {
+		if got, err := snowflake.ParseHex(in); !errors.Is(err, snowflake.ErrSyntax) {
+			t.Errorf("ParseHex(%q) = %d, %v, want ErrSyntax", in, got, err)
+		}
+	}
+
+	// 16 digits after the prefix is as long as hex input gets.
+	if got, err := snowflake.ParseHex("0x000000000000001f"); err != nil || got != 0x1f {
+		t.Errorf(`ParseHex("0x000000000000001f") = %d, %v, want 31`, got, err)
+	}
+
+	for _, in := range []string{"0x00000000000000001", "0000000000000000001"} {
+		if got, err := snowflake.ParseHex(in); !errors.Is(err, snowflake.ErrTooLong) {
+			t.Errorf("ParseHex(%q) = %d, %v, want ErrTooLong", in, got, err)
+		}
+	}
+}
+
+func TestHexSortOrder(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	sample := []snowflake.Snowflake{0, 1, 15, 16, 255, 256, math.MaxUint64}
+	for i := 0; i < 1000; i++ {
+		// Mix widths so the sample has values of every hex length.
+		sample = append(sample, snowflake.Snowflake(r.Uint64()>>r.Intn(64)))
+	}
+	r.Shuffle(len(sample), func(i, j int) { sample[i], sample[j] = sample[j], sample[i] })
+
+	encoded := make([]string, len(sample))
+	for i, s := range sample {
+		encoded[i] = s.Hex()
+	}
+
+	slices.Sort(encoded)
+	slices.Sort(sample)
+
+	for i, s := range sample {
+		if encoded[i] != s.Hex() {
+			t.Fatalf("sorted Hex()[%d] = %q, want %q", i, encoded[i], s.Hex())
+		}
+	}
+}
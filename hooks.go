@@ -0,0 +1,98 @@
+// MIT License
+
+// Copyright (c) 2022 Project-Sparrow
+// Copyright (c) 2023 Kelwing <kelwing@kelnet.org>
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package snowflake
+
+import (
+	"errors"
+	"time"
+)
+
+// WithOnClockBackwards makes the Generator call f with how far behind
+// the clock is every time it reads earlier than the last generated Snowflake,
+// before the Policy is applied.
+// f is called on the goroutine that was generating, once the Generator's
+// lock is released, so it may use the Generator.
+func WithOnClockBackwards(f func(delta time.Duration)) Option {
+	return func(g *Generator) error {
+		if err := g.once("clock backwards hook"); err != nil {
+			return err
+		}
+
+		if f == nil {
+			return errors.New("clock backwards hook is nil")
+		}
+
+		g.onBackwards = f
+
+		return nil
+	}
+}
+
+// WithOnSequenceExhausted makes the Generator call f with the time unit
+// whose sequence ran out every time a Snowflake had to wait for the next
+// time unit or could not be generated because of it.
+// f is called on the goroutine that was generating, once the Generator's
+// lock is released, so it may use the Generator.
+func WithOnSequenceExhausted(f func(t time.Time)) Option {
+	return func(g *Generator) error {
+		if err := g.once("sequence exhausted hook"); err != nil {
+			return err
+		}
+
+		if f == nil {
+			return errors.New("sequence exhausted hook is nil")
+		}
+
+		g.onExhausted = f
+
+		return nil
+	}
+}
+
+// events collects the events that occurred while the Generator's lock
+// was held, so their hooks can be called after it is released.
+type events struct {
+	backwards []time.Duration
+	exhausted []time.Time
+}
+
+// exhausted records that the sequence of timestamp ts ran out.
+// The caller must hold g.mtx.
+func (g *Generator) exhausted(ev *events, ts int64) {
+	if g.onExhausted != nil {
+		ev.exhausted = append(ev.exhausted, g.epoch.Add(time.Duration(ts)*g.unit))
+	}
+}
+
+// fire calls the hooks for the events in ev.
+// The caller must not hold g.mtx.
+func (g *Generator) fire(ev *events) {
+	for _, d := range ev.backwards {
+		g.onBackwards(d)
+	}
+
+	for _, t := range ev.exhausted {
+		g.onExhausted(t)
+	}
+}
@@ -0,0 +1,120 @@
+// MIT License
+
+// Copyright (c) 2022 Project-Sparrow
+// Copyright (c) 2023 Kelwing <kelwing@kelnet.org>
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package snowflake_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"wumpgo.dev/snowflake"
+)
+
+func TestOnClockBackwards(t *testing.T) {
+	var g *snowflake.Generator
+	var deltas []time.Duration
+	hook := func(d time.Duration) {
+		deltas = append(deltas, d)
+		// The Generator must be usable from the hook.
+		g.Stats()
+	}
+
+	g, clock := backwardsGenerator(t, 5*time.Millisecond, snowflake.WithOnClockBackwards(hook))
+
+	g.Generate()
+
+	clock.Set(clock.Now().Add(-time.Second))
+	if _, err := g.TryGenerate(); !errors.Is(err, snowflake.ErrClockBackwards) {
+		t.Errorf("TryGenerate() = %v, want ErrClockBackwards", err)
+	}
+
+	if len(deltas) != 2 || deltas[0] != 5*time.Millisecond || deltas[1] != time.Second {
+		t.Errorf("hook called with %v, want [5ms 1s]", deltas)
+	}
+}
+
+func TestOnClockBackwardsPanic(t *testing.T) {
+	calls := 0
+	g, _ := backwardsGenerator(t, time.Millisecond,
+		snowflake.WithClockBackwardsPolicy(snowflake.PolicyPanic),
+		snowflake.WithOnClockBackwards(func(time.Duration) { calls++ }))
+
+	func() {
+		defer func() { _ = recover() }()
+		g.Generate()
+	}()
+
+	if calls != 1 {
+		t.Errorf("hook called %d times, want 1", calls)
+	}
+}
+
+func TestOnSequenceExhausted(t *testing.T) {
+	epoch := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	start := epoch.Add(time.Hour)
+	clock := snowflake.NewFakeClock(start)
+
+	var g *snowflake.Generator
+	var times []time.Time
+	hook := func(t time.Time) {
+		times = append(times, t)
+		g.Stats()
+	}
+
+	g, err := snowflake.New(snowflake.WithEpoch(epoch), snowflake.WithClock(clock), snowflake.WithOnSequenceExhausted(hook))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	g.GenerateN(2*4096 + 1)
+
+	for i := 0; i < 4095; i++ {
+		g.Generate()
+	}
+
+	if _, err := g.TryGenerate(); !errors.Is(err, snowflake.ErrSequenceExhausted) {
+		t.Fatalf("TryGenerate() = %v, want ErrSequenceExhausted", err)
+	}
+
+	want := []time.Time{start, start.Add(time.Millisecond), start.Add(2 * time.Millisecond)}
+	if len(times) != len(want) {
+		t.Fatalf("hook called with %v, want %v", times, want)
+	}
+
+	for i := range want {
+		if !times[i].Equal(want[i]) {
+			t.Errorf("hook call %d with %v, want %v", i, times[i], want[i])
+		}
+	}
+}
+
+func TestHooksInvalid(t *testing.T) {
+	if _, err := snowflake.New(snowflake.WithOnClockBackwards(nil)); err == nil {
+		t.Error("New with a nil clock backwards hook succeeded")
+	}
+
+	if _, err := snowflake.New(snowflake.WithOnSequenceExhausted(nil)); err == nil {
+		t.Error("New with a nil sequence exhausted hook succeeded")
+	}
+}
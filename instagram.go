@@ -0,0 +1,53 @@
+// MIT License
+
+// Copyright (c) 2022 Project-Sparrow
+// Copyright (c) 2023 Kelwing <kelwing@kelnet.org>
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package snowflake
+
+import "time"
+
+// EpochInstagram is the epoch of Instagram IDs, 2011-08-24 21:07:01.721 UTC.
+var EpochInstagram = time.UnixMilli(1314220021721).UTC()
+
+// LayoutInstagram is the bit layout of Instagram's sharded Postgres IDs:
+// a 41-bit timestamp, a 13-bit logical shard ID held in the worker field
+// and a 10-bit per-shard sequence.
+var LayoutInstagram = Layout{TimestampBits: 41, WorkerBits: 13, SequenceBits: 10}
+
+// NewInstagram creates a Generator compatible with Instagram's scheme,
+// using LayoutInstagram and EpochInstagram, that generates IDs for one shard.
+// Its Time, Deconstruct and ShardID decode Instagram IDs.
+// opts are applied afterwards, so WithEpoch may be used to pick another epoch.
+func NewInstagram(shardID uint16, opts ...Option) (*Generator, error) {
+	g := newGenerator()
+	g.epoch = EpochInstagram
+	g.layout = LayoutInstagram
+	g.workerID = shardID
+
+	return g.configure(opts)
+}
+
+// ShardID decodes the logical shard ID of a Snowflake generated by a Generator
+// from NewInstagram. It is the field reported as WorkerID by Deconstruct.
+func (g *Generator) ShardID(s Snowflake) uint16 {
+	return g.layout.WorkerID(s)
+}
@@ -0,0 +1,87 @@
+// MIT License
+
+// Copyright (c) 2022 Project-Sparrow
+// Copyright (c) 2023 Kelwing <kelwing@kelnet.org>
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package snowflake_test
+
+import (
+	"testing"
+	"time"
+
+	"wumpgo.dev/snowflake"
+)
+
+func TestInstagramDecode(t *testing.T) {
+	g, err := snowflake.NewInstagram(0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		id      snowflake.Snowflake
+		created time.Time
+		shard   uint16
+		seq     uint16
+	}{
+		// The worked example from Instagram's "Sharding & IDs at Instagram":
+		// 1387263000ms after the epoch, shard 1341 and sequence 5001 % 1024.
+		{"engineering blog", 11637205501278089, time.Date(2011, time.September, 9, 22, 28, 4, 721e6, time.UTC), 1341, 905},
+		{"epoch", 0, snowflake.EpochInstagram, 0, 0},
+		{"largest", 1<<64 - 1, snowflake.EpochInstagram.Add((1<<41 - 1) * time.Millisecond), 8191, 1023},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := g.Time(tt.id); !got.Equal(tt.created) {
+				t.Errorf("Time() = %v, want %v", got, tt.created)
+			}
+
+			if got := g.ShardID(tt.id); got != tt.shard {
+				t.Errorf("ShardID() = %d, want %d", got, tt.shard)
+			}
+
+			p := g.Deconstruct(tt.id)
+			if p.WorkerID != tt.shard || p.ProcessID != 0 || p.Sequence != tt.seq {
+				t.Errorf("Deconstruct() = %+v, want shard %d and sequence %d", p, tt.shard, tt.seq)
+			}
+		})
+	}
+}
+
+func TestInstagramGenerate(t *testing.T) {
+	now := time.Date(2011, time.September, 9, 22, 28, 4, 721e6, time.UTC)
+	clock := snowflake.NewFakeClock(now)
+
+	g, err := snowflake.NewInstagram(1341, snowflake.WithClock(clock))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if s := g.Generate(); s != 11637205501277184 {
+		t.Errorf("Generate() = %d, want 11637205501277184", s)
+	}
+
+	if _, err := snowflake.NewInstagram(8192); err == nil {
+		t.Error("NewInstagram(8192) succeeded, want error")
+	}
+}
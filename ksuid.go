@@ -0,0 +1,126 @@
+// MIT License
+
+// Copyright (c) 2022 Project-Sparrow
+// Copyright (c) 2023 Kelwing <kelwing@kelnet.org>
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package snowflake
+
+import (
+	"fmt"
+	"math"
+	"time"
+)
+
+// ksuidLen is the length of a KSUID in base62.
+const ksuidLen = 27
+
+// ksuid holds the 160 bits of a KSUID as big-endian 32-bit words: a timestamp
+// in seconds since EpochKSUID followed by 128 bits of payload.
+type ksuid [5]uint32
+
+// parseKSUID decodes the base62 form of a KSUID,
+// which uses the same alphabet as EncodeBase62.
+func parseKSUID(s string) (ksuid, error) {
+	var k ksuid
+	if len(s) > ksuidLen {
+		return k, fmt.Errorf("%w: KSUID of %d characters, want %d", ErrTooLong, len(s), ksuidLen)
+	}
+
+	if len(s) < ksuidLen {
+		return k, fmt.Errorf("%w: KSUID %q has %d characters, want %d", ErrSyntax, s, len(s), ksuidLen)
+	}
+
+	for i := 0; i < len(s); i++ {
+		d := base62.digits[s[i]]
+		if d == 0xFF {
+			return k, fmt.Errorf("%w: KSUID %q has invalid character %q", ErrSyntax, s, s[i])
+		}
+
+		carry := uint64(d)
+		for j := len(k) - 1; j >= 0; j-- {
+			x := uint64(k[j])*62 + carry
+			k[j], carry = uint32(x), x>>32
+		}
+
+		if carry != 0 {
+			return k, fmt.Errorf("%w: KSUID %q", ErrOverflow, s)
+		}
+	}
+
+	return k, nil
+}
+
+// String returns the base62 form of k, zero-padded to 27 characters.
+func (k ksuid) String() string {
+	var buf [ksuidLen]byte
+	for i := ksuidLen - 1; i >= 0; i-- {
+		var rem uint64
+		for j := range k {
+			x := rem<<32 | uint64(k[j])
+			k[j], rem = uint32(x/62), x%62
+		}
+		buf[i] = base62.alphabet[rem]
+	}
+
+	return string(buf[:])
+}
+
+// TimeFromKSUID returns the timestamp of a KSUID, which has a resolution
+// of one second, in UTC. Errors wrap ErrSyntax or ErrOverflow.
+func TimeFromKSUID(s string) (time.Time, error) {
+	k, err := parseKSUID(s)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	return EpochKSUID.Add(time.Duration(k[0]) * time.Second), nil
+}
+
+// KSUIDBounds returns the smallest and largest Snowflakes created in the
+// second of the KSUID s, as FirstForTime and LastForTime do, for comparing
+// creation times across KSUIDs and Snowflakes.
+func KSUIDBounds(s string) (lo, hi Snowflake, err error) {
+	t, err := TimeFromKSUID(s)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	return BoundsForRange(t, t.Add(time.Second-time.Millisecond))
+}
+
+// KSUIDRange returns the smallest and largest KSUIDs with the timestamp of
+// the second the Snowflake was created in, relative to the epoch passed to
+// Init. Every KSUID from that second sorts between lo and hi inclusive.
+// It returns an error if the Snowflake is before EpochKSUID or after the
+// last second a KSUID can hold.
+func (s Snowflake) KSUIDRange() (lo, hi string, err error) {
+	t := s.Time()
+	if t.Before(EpochKSUID) {
+		return "", "", fmt.Errorf("time %v of %d is before the KSUID epoch %v", t, s, EpochKSUID)
+	}
+
+	secs := int64(t.Sub(EpochKSUID) / time.Second)
+	if secs > math.MaxUint32 {
+		return "", "", fmt.Errorf("%w: time %v of %d is beyond the KSUID range", ErrTimestampOverflow, t, s)
+	}
+
+	return ksuid{uint32(secs)}.String(), ksuid{uint32(secs), math.MaxUint32, math.MaxUint32, math.MaxUint32, math.MaxUint32}.String(), nil
+}
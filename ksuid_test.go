@@ -0,0 +1,146 @@
+// MIT License
+
+// Copyright (c) 2022 Project-Sparrow
+// Copyright (c) 2023 Kelwing <kelwing@kelnet.org>
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package snowflake_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"wumpgo.dev/snowflake"
+)
+
+func TestTimeFromKSUID(t *testing.T) {
+	for _, tt := range []struct {
+		in   string
+		want time.Time
+	}{
+		// From the segmentio/ksuid README.
+		{"0ujtsYcgvSTl8PAuAdqWYSMnLOv", time.Date(2017, 10, 10, 4, 0, 47, 0, time.UTC)},
+		{"000000000000000000000000000", snowflake.EpochKSUID},
+		{"aWgEPTl1tmebfsQzFP4bxwgy80V", snowflake.EpochKSUID.Add((1<<32 - 1) * time.Second)},
+		{"0WhNuekno9o0zDhvids8SVqJGb2", time.Date(2016, 4, 30, 11, 18, 25, 0, time.UTC)},
+	} {
+		got, err := snowflake.TimeFromKSUID(tt.in)
+		if err != nil || !got.Equal(tt.want) || got.Location() != time.UTC {
+			t.Errorf("TimeFromKSUID(%q) = %v, %v, want %v", tt.in, got, err, tt.want)
+		}
+	}
+
+	for _, tt := range []struct {
+		in  string
+		err error
+	}{
+		{"", snowflake.ErrSyntax},
+		{"0ujtsYcgvSTl8PAuAdqWYSMnLO", snowflake.ErrSyntax},
+		{"0ujtsYcgvSTl8PAuAdqWYSMnLOvv", snowflake.ErrTooLong},
+		{"0ujtsYcgvSTl8PAuAdqWYSMnLO-", snowflake.ErrSyntax},
+		{"aWgEPTl1tmebfsQzFP4bxwgy80W", snowflake.ErrOverflow}, // 2^160
+		{"zzzzzzzzzzzzzzzzzzzzzzzzzzz", snowflake.ErrOverflow},
+	} {
+		if got, err := snowflake.TimeFromKSUID(tt.in); !errors.Is(err, tt.err) {
+			t.Errorf("TimeFromKSUID(%q) = %v, %v, want %v", tt.in, got, err, tt.err)
+		}
+	}
+}
+
+func TestKSUIDBounds(t *testing.T) {
+	defer snowflake.ResetDefault()
+	snowflake.InitDiscord(0, 0)
+
+	lo, hi, err := snowflake.KSUIDBounds("0ujtsYcgvSTl8PAuAdqWYSMnLOv")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	second := time.Date(2017, 10, 10, 4, 0, 47, 0, time.UTC)
+	if !lo.Time().Equal(second) || lo.WorkerID() != 0 || lo.Sequence() != 0 {
+		t.Errorf("lo = %v, want the first Snowflake at %v", lo.DebugString(), second)
+	}
+
+	if want := second.Add(999 * time.Millisecond); !hi.Time().Equal(want) || hi.WorkerID() != 31 || hi.ProcessID() != 31 || hi.Sequence() != 4095 {
+		t.Errorf("hi = %v, want the last Snowflake at %v", hi.DebugString(), want)
+	}
+
+	// Every Snowflake from that second falls within the bounds.
+	for _, ms := range []int{0, 500, 999} {
+		s, _ := snowflake.Compose(second.Add(time.Duration(ms)*time.Millisecond), 7, 3, 42)
+		if s < lo || s > hi {
+			t.Errorf("Snowflake at +%dms = %d is outside [%d, %d]", ms, s, lo, hi)
+		}
+	}
+
+	// A KSUID from before the Discord epoch.
+	if _, _, err := snowflake.KSUIDBounds("000000000000000000000000000"); err == nil {
+		t.Error("KSUIDBounds() before the epoch succeeded")
+	}
+
+	if _, _, err := snowflake.KSUIDBounds("invalid"); !errors.Is(err, snowflake.ErrSyntax) {
+		t.Errorf("KSUIDBounds(%q) = %v, want ErrSyntax", "invalid", err)
+	}
+}
+
+func TestKSUIDRange(t *testing.T) {
+	defer snowflake.ResetDefault()
+	snowflake.InitDiscord(0, 0)
+
+	// 2016-04-30T11:18:25.796Z, in KSUID second 62015105.
+	s := snowflake.Snowflake(175928847299117063)
+	lo, hi, err := s.KSUIDRange()
+	if err != nil || lo != "0WhNuekno9o0zDhvids8SVqJGb2" || hi != "0WhNumXrqN4N4hNhSnfHFOxYyD9" {
+		t.Errorf("KSUIDRange() = %q, %q, %v", lo, hi, err)
+	}
+
+	for _, k := range []string{lo, hi} {
+		if got, err := snowflake.TimeFromKSUID(k); err != nil || !got.Equal(s.Time().Truncate(time.Second)) {
+			t.Errorf("TimeFromKSUID(%q) = %v, %v, want %v", k, got, err, s.Time().Truncate(time.Second))
+		}
+	}
+
+	// Round trip: the Snowflake falls within the bounds of its own range.
+	blo, bhi, err := snowflake.KSUIDBounds(lo)
+	if err != nil || s < blo || s > bhi {
+		t.Errorf("KSUIDBounds(%q) = %d, %d, %v, want bounds around %d", lo, blo, bhi, err, s)
+	}
+
+	// A Snowflake 2^32 seconds after EpochKSUID is beyond the KSUID range.
+	end := snowflake.EpochKSUID.Add(1 << 32 * time.Second)
+	late, err := snowflake.Compose(end, 0, 0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, _, err := late.KSUIDRange(); !errors.Is(err, snowflake.ErrTimestampOverflow) {
+		t.Errorf("KSUIDRange() at %v = %v, want ErrTimestampOverflow", end, err)
+	}
+
+	if _, _, err := (late - 1<<22).KSUIDRange(); err != nil {
+		t.Errorf("KSUIDRange() a millisecond earlier = %v", err)
+	}
+
+	snowflake.Init(time.Date(2010, 1, 1, 0, 0, 0, 0, time.UTC), 0, 0)
+	if _, _, err := snowflake.Snowflake(0).KSUIDRange(); err == nil {
+		t.Error("KSUIDRange() before the KSUID epoch succeeded")
+	}
+}
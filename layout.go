@@ -0,0 +1,170 @@
+// MIT License
+
+// Copyright (c) 2022 Project-Sparrow
+// Copyright (c) 2023 Kelwing <kelwing@kelnet.org>
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package snowflake
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Layout describes how the bits of a Snowflake are split between its
+// components, from most to least significant: timestamp, worker ID,
+// process ID and sequence, or timestamp, sequence, worker ID and process ID
+// if SequenceHigh is set.
+//
+// The widths must add up to 63 or 64. With 63 the sign bit is never set,
+// so Snowflakes stay positive when stored as signed 64-bit integers.
+// The worker, process and sequence fields may be at most 16 bits wide.
+type Layout struct {
+	TimestampBits uint8 `json:"timestamp_bits"`
+	WorkerBits    uint8 `json:"worker_bits"`
+	ProcessBits   uint8 `json:"process_bits"`
+	SequenceBits  uint8 `json:"sequence_bits"`
+
+	// SequenceHigh places the sequence directly below the timestamp,
+	// above the worker and process IDs, as Sonyflake does.
+	SequenceHigh bool `json:"sequence_high,omitempty"`
+}
+
+// DefaultLayout is the layout used by Generate and the Snowflake accessors:
+// 42 bits of timestamp, 5 bits of worker ID, 5 bits of process ID
+// and 12 bits of sequence.
+var DefaultLayout = Layout{
+	TimestampBits: timestampBits,
+	WorkerBits:    workerBits,
+	ProcessBits:   processBits,
+	SequenceBits:  sequenceBits,
+}
+
+// Validate reports whether the Layout is usable.
+func (l Layout) Validate() error {
+	if l.TimestampBits == 0 {
+		return errors.New("layout has no timestamp bits")
+	}
+
+	if l.SequenceBits == 0 {
+		return errors.New("layout has no sequence bits")
+	}
+
+	if l.WorkerBits > 16 || l.ProcessBits > 16 || l.SequenceBits > 16 {
+		return fmt.Errorf("layout %v has a worker, process or sequence field wider than 16 bits", l)
+	}
+
+	if sum := int(l.TimestampBits) + int(l.WorkerBits) + int(l.ProcessBits) + int(l.SequenceBits); sum != 63 && sum != 64 {
+		return fmt.Errorf("layout %v adds up to %d bits, want 63 or 64", l, sum)
+	}
+
+	return nil
+}
+
+// String implements fmt.Stringer interface
+func (l Layout) String() string {
+	if l.SequenceHigh {
+		return fmt.Sprintf("%d/%d(seq)/%d/%d", l.TimestampBits, l.SequenceBits, l.WorkerBits, l.ProcessBits)
+	}
+	return fmt.Sprintf("%d/%d/%d/%d", l.TimestampBits, l.WorkerBits, l.ProcessBits, l.SequenceBits)
+}
+
+func (l Layout) timestampShift() uint8 { return l.SequenceBits + l.ProcessBits + l.WorkerBits }
+
+func (l Layout) sequenceShift() uint8 {
+	if l.SequenceHigh {
+		return l.WorkerBits + l.ProcessBits
+	}
+	return 0
+}
+
+func (l Layout) workerShift() uint8 {
+	if l.SequenceHigh {
+		return l.ProcessBits
+	}
+	return l.SequenceBits + l.ProcessBits
+}
+
+func (l Layout) processShift() uint8 {
+	if l.SequenceHigh {
+		return 0
+	}
+	return l.SequenceBits
+}
+
+// MaxTimestamp returns the largest timestamp the Layout can hold.
+func (l Layout) MaxTimestamp() uint64 { return 1<<l.TimestampBits - 1 }
+
+// MaxWorkerID returns the largest worker ID the Layout can hold.
+func (l Layout) MaxWorkerID() uint16 { return 1<<l.WorkerBits - 1 }
+
+// MaxProcessID returns the largest process ID the Layout can hold.
+func (l Layout) MaxProcessID() uint16 { return 1<<l.ProcessBits - 1 }
+
+// MaxSequence returns the largest sequence the Layout can hold.
+func (l Layout) MaxSequence() uint16 { return 1<<l.SequenceBits - 1 }
+
+// Timestamp decodes the timestamp bits of s, in time units since the epoch.
+func (l Layout) Timestamp(s Snowflake) uint64 {
+	return uint64(s>>l.timestampShift()) & l.MaxTimestamp()
+}
+
+// WorkerID decodes the worker ID bits of s.
+func (l Layout) WorkerID(s Snowflake) uint16 {
+	return uint16(s>>l.workerShift()) & l.MaxWorkerID()
+}
+
+// ProcessID decodes the process ID bits of s.
+func (l Layout) ProcessID(s Snowflake) uint16 {
+	return uint16(s>>l.processShift()) & l.MaxProcessID()
+}
+
+// Sequence decodes the sequence bits of s.
+func (l Layout) Sequence(s Snowflake) uint16 {
+	return uint16(s>>l.sequenceShift()) & l.MaxSequence()
+}
+
+// compose builds a Snowflake from components that are known to fit.
+func (l Layout) compose(ts uint64, worker, process, seq uint16) Snowflake {
+	s := Snowflake(ts) << l.timestampShift()
+	s |= Snowflake(worker) << l.workerShift()
+	s |= Snowflake(process) << l.processShift()
+	s |= Snowflake(seq) << l.sequenceShift()
+
+	return s
+}
+
+// WithLayout makes the Generator split the bits of its Snowflakes
+// according to l instead of DefaultLayout.
+func WithLayout(l Layout) Option {
+	return func(g *Generator) error {
+		if err := g.once("layout"); err != nil {
+			return err
+		}
+
+		if err := l.Validate(); err != nil {
+			return err
+		}
+
+		g.layout = l
+
+		return nil
+	}
+}
@@ -0,0 +1,111 @@
+// MIT License
+
+// Copyright (c) 2022 Project-Sparrow
+// Copyright (c) 2023 Kelwing <kelwing@kelnet.org>
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package snowflake_test
+
+import (
+	"testing"
+	"time"
+
+	"wumpgo.dev/snowflake"
+)
+
+func TestLayoutValidate(t *testing.T) {
+	tests := []struct {
+		name   string
+		layout snowflake.Layout
+		valid  bool
+	}{
+		{"default", snowflake.DefaultLayout, true},
+		{"63 bits", snowflake.Layout{TimestampBits: 41, WorkerBits: 10, ProcessBits: 0, SequenceBits: 12}, true},
+		{"62 bits", snowflake.Layout{TimestampBits: 40, WorkerBits: 10, ProcessBits: 0, SequenceBits: 12}, false},
+		{"65 bits", snowflake.Layout{TimestampBits: 43, WorkerBits: 5, ProcessBits: 5, SequenceBits: 12}, false},
+		{"no timestamp", snowflake.Layout{TimestampBits: 0, WorkerBits: 16, ProcessBits: 16, SequenceBits: 16}, false},
+		{"no sequence", snowflake.Layout{TimestampBits: 44, WorkerBits: 10, ProcessBits: 10, SequenceBits: 0}, false},
+		{"wide worker", snowflake.Layout{TimestampBits: 40, WorkerBits: 17, ProcessBits: 0, SequenceBits: 7}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.layout.Validate(); (err == nil) != tt.valid {
+				t.Errorf("Validate() = %v, want valid %v", err, tt.valid)
+			}
+		})
+	}
+}
+
+func TestDefaultLayoutMatchesAccessors(t *testing.T) {
+	snowflake.Init(time.Now().Add(-time.Hour), 21, 9)
+
+	s := snowflake.Generate()
+	l := snowflake.DefaultLayout
+
+	if l.WorkerID(s) != uint16(s.WorkerID()) || l.ProcessID(s) != uint16(s.ProcessID()) || l.Sequence(s) != s.Sequence() {
+		t.Errorf("DefaultLayout decoded %d/%d/%d, accessors %d/%d/%d",
+			l.WorkerID(s), l.ProcessID(s), l.Sequence(s), s.WorkerID(), s.ProcessID(), s.Sequence())
+	}
+}
+
+func TestGeneratorCustomLayout(t *testing.T) {
+	epoch := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	clock := snowflake.NewFakeClock(epoch.Add(90 * 24 * time.Hour))
+	layout := snowflake.Layout{TimestampBits: 41, WorkerBits: 10, ProcessBits: 0, SequenceBits: 12}
+
+	g, err := snowflake.New(
+		snowflake.WithEpoch(epoch),
+		snowflake.WithLayout(layout),
+		snowflake.WithWorkerID(300),
+		snowflake.WithClock(clock),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 0; i < 5000; i++ {
+		s := g.Generate()
+		p := g.Deconstruct(s)
+
+		want := snowflake.Parts{
+			Time:      clock.Now().Truncate(time.Millisecond),
+			WorkerID:  300,
+			ProcessID: 0,
+			Sequence:  uint16(i % 4096),
+		}
+
+		if !p.Time.Equal(want.Time) || p.WorkerID != want.WorkerID || p.ProcessID != want.ProcessID || p.Sequence != want.Sequence {
+			t.Fatalf("Deconstruct() #%d = %+v, want %+v", i, p, want)
+		}
+
+		if s >= 1<<63 {
+			t.Fatalf("Snowflake %d uses the sign bit of a 63-bit layout", s)
+		}
+	}
+
+	if _, err := snowflake.New(snowflake.WithLayout(layout), snowflake.WithProcessID(1)); err == nil {
+		t.Error("expected error for process ID without process bits")
+	}
+
+	if _, err := snowflake.New(snowflake.WithLayout(snowflake.Layout{TimestampBits: 1})); err == nil {
+		t.Error("expected error for invalid layout")
+	}
+}
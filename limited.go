@@ -0,0 +1,155 @@
+// MIT License
+
+// Copyright (c) 2022 Project-Sparrow
+// Copyright (c) 2023 Kelwing <kelwing@kelnet.org>
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package snowflake
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"sync"
+	"time"
+)
+
+// ErrRateLimited is returned by Limited.TryGenerate when no token is available.
+var ErrRateLimited = errors.New("rate limit exceeded")
+
+// Limited wraps a Generator with a token bucket, capping how many Snowflakes
+// it issues per second, for example to share a Generator fairly between tenants.
+// Time is read from the Generator's clock.
+type Limited struct {
+	g     *Generator
+	rate  float64
+	burst float64
+
+	mtx    sync.Mutex
+	tokens float64
+	last   time.Time
+}
+
+// NewLimited creates a Limited issuing up to rate Snowflakes per second from g
+// on average, and up to burst at once. The bucket starts full.
+func NewLimited(g *Generator, rate float64, burst int) (*Limited, error) {
+	if g == nil {
+		return nil, errors.New("generator is nil")
+	}
+
+	if !(rate > 0) || math.IsInf(rate, 1) {
+		return nil, fmt.Errorf("rate %v is not a positive number", rate)
+	}
+
+	if burst < 1 {
+		return nil, fmt.Errorf("burst %d is not positive", burst)
+	}
+
+	return &Limited{
+		g:      g,
+		rate:   rate,
+		burst:  float64(burst),
+		tokens: float64(burst),
+		last:   g.clock.Now(),
+	}, nil
+}
+
+// Generate is like GenerateContext without a deadline,
+// and panics where it would return an error.
+func (l *Limited) Generate() Snowflake {
+	s, err := l.GenerateContext(context.Background())
+	if err != nil {
+		panic("snowflake: " + err.Error())
+	}
+
+	return s
+}
+
+// GenerateContext waits for a token, then generates a Snowflake
+// as Generator.GenerateContext does. It returns ctx.Err() if ctx is done
+// first. The token is given back if generation fails.
+func (l *Limited) GenerateContext(ctx context.Context) (Snowflake, error) {
+	for {
+		if err := ctx.Err(); err != nil {
+			return 0, err
+		}
+
+		wait := l.take()
+		if wait == 0 {
+			break
+		}
+
+		sleepOn(l.g.clock, min(wait, time.Millisecond))
+	}
+
+	s, err := l.g.GenerateContext(ctx)
+	if err != nil {
+		l.refund()
+		return 0, err
+	}
+
+	return s, nil
+}
+
+// TryGenerate is like GenerateContext but never waits: it returns
+// ErrRateLimited if no token is available, and the errors of
+// Generator.TryGenerate otherwise, in which case the token is given back.
+func (l *Limited) TryGenerate() (Snowflake, error) {
+	if l.take() != 0 {
+		return 0, ErrRateLimited
+	}
+
+	s, err := l.g.TryGenerate()
+	if err != nil {
+		l.refund()
+		return 0, err
+	}
+
+	return s, nil
+}
+
+// take takes a token if one is available and returns 0,
+// or returns how long until one will be.
+func (l *Limited) take() time.Duration {
+	l.mtx.Lock()
+	defer l.mtx.Unlock()
+
+	now := l.g.clock.Now()
+	if elapsed := now.Sub(l.last); elapsed > 0 {
+		l.tokens = min(l.burst, l.tokens+elapsed.Seconds()*l.rate)
+	}
+	l.last = now
+
+	if l.tokens >= 1 {
+		l.tokens--
+		return 0
+	}
+
+	return max(time.Duration((1-l.tokens)/l.rate*float64(time.Second)), time.Nanosecond)
+}
+
+// refund gives back a token taken for a Snowflake that was not generated.
+func (l *Limited) refund() {
+	l.mtx.Lock()
+	defer l.mtx.Unlock()
+
+	l.tokens = min(l.burst, l.tokens+1)
+}
@@ -0,0 +1,164 @@
+// MIT License
+
+// Copyright (c) 2022 Project-Sparrow
+// Copyright (c) 2023 Kelwing <kelwing@kelnet.org>
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package snowflake_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"wumpgo.dev/snowflake"
+)
+
+func TestLimitedThroughput(t *testing.T) {
+	epoch := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	start := epoch.Add(time.Hour)
+	clock := snowflake.NewFakeClock(start)
+
+	g, err := snowflake.New(snowflake.WithEpoch(epoch), snowflake.WithClock(clock))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	l, err := snowflake.NewLimited(g, 10000, 100)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Only waiting for tokens moves the clock, so this simulates a second
+	// of callers generating as fast as they can.
+	n := 0
+	for clock.Now().Before(start.Add(time.Second)) {
+		if _, err := l.GenerateContext(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+		n++
+	}
+
+	if n < 10000 || n > 10000+100+1 {
+		t.Errorf("generated %d Snowflakes in a second, want about 10000 plus a burst of 100", n)
+	}
+}
+
+func TestLimitedTryGenerate(t *testing.T) {
+	epoch := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	clock := snowflake.NewManualClock(epoch.Add(time.Hour))
+
+	g, err := snowflake.New(snowflake.WithEpoch(epoch), snowflake.WithClock(clock))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	l, err := snowflake.NewLimited(g, 10, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 0; i < 3; i++ {
+		if _, err := l.TryGenerate(); err != nil {
+			t.Fatalf("TryGenerate() #%d = %v", i, err)
+		}
+	}
+
+	if _, err := l.TryGenerate(); !errors.Is(err, snowflake.ErrRateLimited) {
+		t.Errorf("TryGenerate() past the burst = %v, want ErrRateLimited", err)
+	}
+
+	clock.Advance(100 * time.Millisecond)
+	if _, err := l.TryGenerate(); err != nil {
+		t.Errorf("TryGenerate() after refilling a token = %v", err)
+	}
+}
+
+func TestLimitedRefund(t *testing.T) {
+	epoch := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	clock := snowflake.NewManualClock(epoch.Add(time.Hour))
+
+	g, err := snowflake.New(snowflake.WithEpoch(epoch), snowflake.WithClock(clock))
+	if err != nil {
+		t.Fatal(err)
+	}
+	g.GenerateN(4096)
+
+	l, err := snowflake.NewLimited(g, 1, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// A failed generation gives the token back.
+	if _, err := l.TryGenerate(); !errors.Is(err, snowflake.ErrSequenceExhausted) {
+		t.Fatalf("TryGenerate() = %v, want ErrSequenceExhausted", err)
+	}
+
+	clock.Advance(time.Millisecond)
+	if _, err := l.TryGenerate(); err != nil {
+		t.Errorf("TryGenerate() = %v, want the refunded token", err)
+	}
+}
+
+func TestLimitedContext(t *testing.T) {
+	l, err := snowflake.NewLimited(systemGenerator(t), 1, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	l.Generate()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	if _, err := l.GenerateContext(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("GenerateContext() = %v, want context.DeadlineExceeded", err)
+	}
+}
+
+func TestNewLimitedInvalid(t *testing.T) {
+	g, err := snowflake.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := snowflake.NewLimited(g, 0, 1); err == nil {
+		t.Error("NewLimited with rate 0 succeeded")
+	}
+
+	if _, err := snowflake.NewLimited(g, 1, 0); err == nil {
+		t.Error("NewLimited with burst 0 succeeded")
+	}
+
+	if _, err := snowflake.NewLimited(nil, 1, 1); err == nil {
+		t.Error("NewLimited with a nil Generator succeeded")
+	}
+}
+
+func systemGenerator(t *testing.T) *snowflake.Generator {
+	t.Helper()
+
+	g, err := snowflake.New(snowflake.WithEpoch(time.Now().Add(-time.Hour)))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return g
+}
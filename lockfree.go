@@ -0,0 +1,148 @@
+// MIT License
+
+// Copyright (c) 2022 Project-Sparrow
+// Copyright (c) 2023 Kelwing <kelwing@kelnet.org>
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package snowflake
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// packedTimestampBits is how many bits of the packed state of
+// a lock-free Generator hold the timestamp, above a 16-bit sequence.
+const packedTimestampBits = 48
+
+// WithLockFree makes the Generator advance its last timestamp and sequence,
+// packed into a single word, with compare-and-swap instead of a lock.
+// The lock is only taken to wait for the clock, when the sequence is exhausted
+// or the clock moves backwards.
+// The monotonic anchor is fixed when the Generator is created, so a step
+// of the wall clock forwards followed by one backwards is left to the Policy.
+// It cannot be combined with WithShards, WithBorrowing or WithRandomSequenceStart,
+// and the Layout must have fewer than 48 timestamp bits.
+func WithLockFree() Option {
+	return func(g *Generator) error {
+		if err := g.once("lock-free mode"); err != nil {
+			return err
+		}
+
+		g.lockFree = true
+
+		return nil
+	}
+}
+
+// checkLockFree reports whether WithLockFree can be used with
+// the rest of g's configuration.
+func (g *Generator) checkLockFree() error {
+	if !g.lockFree {
+		return nil
+	}
+
+	if g.shards != nil {
+		return errors.New("lock-free mode cannot be combined with shards")
+	}
+
+	if g.maxBorrow > 0 {
+		return errors.New("lock-free mode cannot be combined with borrowing")
+	}
+
+	if g.randomStart {
+		return errors.New("lock-free mode cannot be combined with random sequence starts")
+	}
+
+	if g.layout.TimestampBits >= packedTimestampBits {
+		return fmt.Errorf("lock-free mode needs fewer than %d timestamp bits, layout %v has %d", packedTimestampBits, g.layout, g.layout.TimestampBits)
+	}
+
+	g.lastExhausted = -1
+
+	return nil
+}
+
+// generateLockFree is like generateLocked for a Generator using WithLockFree,
+// and must be called without holding g.mtx.
+func (g *Generator) generateLockFree(ctx context.Context, wait bool, ev *events) (Snowflake, error) {
+	for {
+		state := g.packed.Load()
+		last, seq := int64(state>>16)-1, uint16(state)
+
+		ts := g.timestamp()
+		switch {
+		case ts < 0:
+			return 0, fmt.Errorf("%w: epoch %v is after the current time %v", ErrClockBeforeEpoch, g.epoch, g.clock.Now())
+		case ts > last:
+			if uint64(ts) > g.maxTimestamp() {
+				return 0, fmt.Errorf("%w: timestamp %d", ErrTimestampOverflow, ts)
+			}
+			seq = 0
+		case ts == last && seq < g.seqMax:
+			seq++
+		default:
+			if err := g.lockFreeWait(ctx, wait, ev, state, ts); err != nil {
+				return 0, err
+			}
+			continue
+		}
+
+		// Without shards or random starts, seq also counts the time unit's Snowflakes.
+		if g.packed.CompareAndSwap(state, uint64(ts+1)<<16|uint64(seq)) {
+			g.stats.record(seq, seq == g.seqMax)
+			return g.compose(ts, seq), nil
+		}
+	}
+}
+
+// lockFreeWait handles a clock reading ts that is behind the packed state,
+// or equal to it with the sequence exhausted, by waiting for the clock under
+// g.mtx. It returns nil once generation can be retried.
+func (g *Generator) lockFreeWait(ctx context.Context, wait bool, ev *events, state uint64, ts int64) error {
+	g.mtx.Lock()
+	defer g.mtx.Unlock()
+
+	// Another goroutine may have waited and generated in the meantime.
+	if g.packed.Load() != state {
+		return nil
+	}
+
+	g.lastTimestamp = int64(state>>16) - 1
+
+	if ts < g.lastTimestamp {
+		_, err := g.clockBackwards(ctx, ts, wait, ev)
+		return err
+	}
+
+	if g.lastExhausted != g.lastTimestamp {
+		g.lastExhausted = g.lastTimestamp
+		g.exhausted(ev, g.lastTimestamp)
+	}
+
+	if !wait {
+		return ErrSequenceExhausted
+	}
+
+	_, err := g.waitFor(ctx, g.lastTimestamp+1)
+
+	return err
+}
@@ -0,0 +1,149 @@
+// MIT License
+
+// Copyright (c) 2022 Project-Sparrow
+// Copyright (c) 2023 Kelwing <kelwing@kelnet.org>
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package snowflake_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"wumpgo.dev/snowflake"
+)
+
+func TestWithLockFreeInvalid(t *testing.T) {
+	if _, err := snowflake.New(snowflake.WithLockFree(), snowflake.WithShards(4)); err == nil {
+		t.Error("New with lock-free mode and shards succeeded, want error")
+	}
+
+	wide := snowflake.Layout{TimestampBits: 48, WorkerBits: 2, ProcessBits: 2, SequenceBits: 12}
+	if _, err := snowflake.New(snowflake.WithLockFree(), snowflake.WithLayout(wide)); err == nil {
+		t.Error("New with lock-free mode and 48 timestamp bits succeeded, want error")
+	}
+}
+
+func TestWithLockFreeUnique(t *testing.T) {
+	epoch := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	// The clock only moves when a goroutine sleeps on it, so every
+	// millisecond runs out of sequence numbers.
+	clock := snowflake.NewFakeClock(epoch.Add(time.Hour))
+
+	g, err := snowflake.New(snowflake.WithEpoch(epoch), snowflake.WithLockFree(), snowflake.WithClock(clock))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	const goroutines = 32
+	const n = 5000
+
+	results := make([][]snowflake.Snowflake, goroutines)
+	var wg sync.WaitGroup
+	for i := range results {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			ids := make([]snowflake.Snowflake, 0, n)
+			for len(ids) < n {
+				switch i % 3 {
+				case 0:
+					ids = append(ids, g.Generate())
+				case 1:
+					ids = g.AppendN(ids, 10)
+				default:
+					// Only sleeping moves the clock, so fall back to Generate.
+					s, err := g.TryGenerate()
+					if errors.Is(err, snowflake.ErrSequenceExhausted) {
+						s, err = g.Generate(), nil
+					}
+					if err != nil {
+						t.Errorf("TryGenerate() = %v", err)
+						return
+					}
+					ids = append(ids, s)
+				}
+			}
+			results[i] = ids
+		}(i)
+	}
+	wg.Wait()
+
+	seen := make(map[snowflake.Snowflake]bool, goroutines*n)
+	for _, ids := range results {
+		for j, s := range ids {
+			if seen[s] {
+				t.Fatalf("duplicate Snowflake %d", s)
+			}
+			seen[s] = true
+
+			if j > 0 && s <= ids[j-1] {
+				t.Fatalf("Snowflake %d after %d is not increasing", s, ids[j-1])
+			}
+		}
+	}
+
+	st := g.Stats()
+	if st.Generated != goroutines*n || st.MaxSequence != 4095 {
+		t.Errorf("Stats() = %+v, want %d generated and MaxSequence 4095", st, goroutines*n)
+	}
+}
+
+func TestWithLockFreeClockBackwards(t *testing.T) {
+	g, clock := backwardsGenerator(t, 5*time.Millisecond, snowflake.WithLockFree())
+
+	if _, err := g.TryGenerate(); !errors.Is(err, snowflake.ErrClockBackwards) {
+		t.Errorf("TryGenerate() = %v, want ErrClockBackwards", err)
+	}
+
+	before := clock.Now()
+	s := g.Generate()
+	if clock.Sleeps() == 0 {
+		t.Error("Generate did not wait for the clock")
+	}
+
+	if got := g.Time(s); got.Before(before.Add(5 * time.Millisecond)) {
+		t.Errorf("Time() = %v, want at least %v", got, before.Add(5*time.Millisecond))
+	}
+
+	clock.Set(clock.Now().Add(-time.Second))
+	if _, err := g.GenerateContext(context.Background()); !errors.Is(err, snowflake.ErrClockBackwards) {
+		t.Errorf("GenerateContext() = %v, want ErrClockBackwards", err)
+	}
+}
+
+func BenchmarkGenerateLockFree(b *testing.B) {
+	epoch := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	clock := &tickingClock{start: epoch.Add(time.Hour)}
+
+	g, err := snowflake.New(snowflake.WithEpoch(epoch), snowflake.WithLockFree(), snowflake.WithClock(clock))
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			g.Generate()
+		}
+	})
+}
@@ -0,0 +1,38 @@
+// MIT License
+
+// Copyright (c) 2022 Project-Sparrow
+// Copyright (c) 2023 Kelwing <kelwing@kelnet.org>
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package snowflake
+
+import "time"
+
+// LayoutMastodon is the bit layout of Mastodon IDs: a 48-bit timestamp in
+// milliseconds since the Unix epoch and 16 low bits derived from a sequence,
+// held in the sequence field.
+var LayoutMastodon = Layout{TimestampBits: 48, SequenceBits: 16}
+
+// MastodonTime returns the creation time of a Mastodon ID, such as a status
+// ID, in UTC. IDs created before Mastodon 2.0 in October 2017 are plain
+// sequence numbers and decode as times in January 1970.
+func MastodonTime(s Snowflake) time.Time {
+	return time.UnixMilli(int64(LayoutMastodon.Timestamp(s))).UTC()
+}
@@ -0,0 +1,89 @@
+// MIT License
+
+// Copyright (c) 2022 Project-Sparrow
+// Copyright (c) 2023 Kelwing <kelwing@kelnet.org>
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package snowflake_test
+
+import (
+	"testing"
+	"time"
+
+	"wumpgo.dev/snowflake"
+)
+
+func TestMastodonTime(t *testing.T) {
+	// Status IDs from the examples of the Mastodon API documentation, with
+	// the created_at published alongside them, which Mastodon sets a few
+	// milliseconds apart from the ID's timestamp and is compared to the second:
+	// https://docs.joinmastodon.org/entities/Status/#example
+	// https://docs.joinmastodon.org/entities/Notification/#example
+	tests := []struct {
+		id      snowflake.Snowflake
+		created time.Time
+	}{
+		{103270115826048975, time.Date(2019, time.December, 8, 3, 48, 33, 0, time.UTC)},
+		{103186126728896492, time.Date(2019, time.November, 23, 7, 49, 1, 0, time.UTC)},
+	}
+
+	for _, tt := range tests {
+		got := snowflake.MastodonTime(tt.id)
+		if !got.Truncate(time.Second).Equal(tt.created) || got.Location() != time.UTC {
+			t.Errorf("MastodonTime(%d) = %v, want %v to the second", tt.id, got, tt.created)
+		}
+
+		// Decoded with the default layout and Discord epoch, the time is wrong.
+		if got := tt.id.TimeWithEpoch(snowflake.EpochDiscord); got.Truncate(time.Second).Equal(tt.created) {
+			t.Errorf("%d decodes to %v with the default layout too", tt.id, got)
+		}
+	}
+
+	// The low 16 bits are the sequence field.
+	if got := snowflake.LayoutMastodon.Sequence(103270115826048975); got != 0x9fcf {
+		t.Errorf("Sequence() = %#x, want 0x9fcf", got)
+	}
+
+	// Statuses from before Mastodon 2.0 have sequential IDs.
+	if got := snowflake.MastodonTime(1); got.Year() != 1970 {
+		t.Errorf("MastodonTime(1) = %v, want a time in 1970", got)
+	}
+}
+
+func TestMastodonGenerator(t *testing.T) {
+	now := time.Date(2024, time.February, 29, 23, 59, 59, 999e6, time.UTC)
+	g, err := snowflake.New(
+		snowflake.WithLayout(snowflake.LayoutMastodon),
+		snowflake.WithEpoch(snowflake.EpochUnix),
+		snowflake.WithClock(snowflake.NewManualClock(now)),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s := g.Generate()
+	if got := snowflake.MastodonTime(s); !got.Equal(now) {
+		t.Errorf("MastodonTime(%d) = %v, want %v", s, got, now)
+	}
+
+	if got := g.Time(s); !got.Equal(now) {
+		t.Errorf("Generator.Time(%d) = %v, want %v", s, got, now)
+	}
+}
@@ -26,7 +26,6 @@ package snowflake
 import (
 	"bytes"
 	"database/sql/driver"
-	"encoding/json"
 )
 
 var nullBytes = []byte("null")
@@ -87,6 +86,15 @@ func (s NullSnowflake) Value() (driver.Value, error) {
 	return s.Snowflake.Value()
 }
 
+// String implements fmt.Stringer interface
+// by returning "null" if the NullSnowflake is invalid.
+func (s NullSnowflake) String() string {
+	if !s.Valid {
+		return "null"
+	}
+	return s.Snowflake.String()
+}
+
 // ValueOrZero returns the inner value if valid, otherwise zero.
 func (s NullSnowflake) ValueOrZero() Snowflake {
 	if !s.Valid {
@@ -96,13 +104,15 @@ func (s NullSnowflake) ValueOrZero() Snowflake {
 }
 
 // UnmarshalJSON implements json.Unmarshaler.
+// A JSON null makes the NullSnowflake invalid; anything else is decoded
+// like Snowflake's UnmarshalJSON, including its errors.
 func (s *NullSnowflake) UnmarshalJSON(data []byte) error {
 	if bytes.Equal(data, nullBytes) {
 		s.Valid = false
 		return nil
 	}
 
-	if err := json.Unmarshal(data, &s.Snowflake); err != nil {
+	if err := s.Snowflake.UnmarshalJSON(data); err != nil {
 		return err
 	}
 
@@ -119,3 +129,63 @@ func (s NullSnowflake) MarshalJSON() ([]byte, error) {
 
 	return s.Snowflake.MarshalJSON()
 }
+
+// MarshalBinary implements encoding.BinaryMarshaler.
+// A null NullSnowflake is encoded as no bytes, and a valid one as the
+// 8 bytes of its Snowflake.
+func (s NullSnowflake) MarshalBinary() ([]byte, error) {
+	if !s.Valid {
+		return []byte{}, nil
+	}
+
+	return s.Snowflake.MarshalBinary()
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler.
+// It accepts no bytes for null or ByteLen bytes for a valid Snowflake.
+func (s *NullSnowflake) UnmarshalBinary(data []byte) error {
+	if len(data) == 0 {
+		s.Snowflake, s.Valid = Snowflake(0), false
+		return nil
+	}
+
+	if err := s.Snowflake.UnmarshalBinary(data); err != nil {
+		return err
+	}
+
+	s.Valid = true
+
+	return nil
+}
+
+// MarshalText implements encoding.TextMarshaler interface.
+// A null NullSnowflake is encoded as "null", like String, and a valid one
+// like its Snowflake.
+func (s NullSnowflake) MarshalText() ([]byte, error) {
+	if !s.Valid {
+		return []byte("null"), nil
+	}
+
+	return s.Snowflake.MarshalText()
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler interface.
+// "null" makes the NullSnowflake invalid; anything else is decoded like
+// Snowflake's UnmarshalText.
+//
+// Formats without a null, such as TOML, never call UnmarshalText for a
+// missing key, so a NullSnowflake whose key is absent stays invalid.
+func (s *NullSnowflake) UnmarshalText(text []byte) error {
+	if bytes.Equal(text, nullBytes) {
+		s.Snowflake, s.Valid = Snowflake(0), false
+		return nil
+	}
+
+	if err := s.Snowflake.UnmarshalText(text); err != nil {
+		return err
+	}
+
+	s.Valid = true
+
+	return nil
+}
@@ -0,0 +1,88 @@
+// MIT License
+
+// Copyright (c) 2022 Project-Sparrow
+// Copyright (c) 2023 Kelwing <kelwing@kelnet.org>
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package snowflake
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrUnsafeInteger is returned by MarshalJSONNumberChecked for a Snowflake
+// above 2^53-1, JavaScript's Number.MAX_SAFE_INTEGER.
+var ErrUnsafeInteger = errors.New("snowflake exceeds JavaScript's Number.MAX_SAFE_INTEGER")
+
+// NumericSnowflake is a Snowflake that is encoded in JSON as a number
+// rather than a string, for peers that expect integer IDs. Convert between
+// the two with NumericSnowflake(s) and Snowflake(n), which costs nothing.
+//
+// JavaScript, and any JSON decoder that reads numbers into a float64,
+// silently rounds integers above 2^53-1. With DefaultLayout, Snowflakes pass
+// that point about 25 days after their epoch, so only use NumericSnowflake
+// with peers known to decode JSON numbers as 64-bit integers, or check each
+// value with IsJSSafe or MarshalJSONNumberChecked.
+type NumericSnowflake Snowflake
+
+// MarshalJSON implements json.Marshaler interface
+func (n NumericSnowflake) MarshalJSON() ([]byte, error) {
+	return Snowflake(n).AppendString(make([]byte, 0, maxDecimalLen)), nil
+}
+
+// UnmarshalJSON implements json.Unmarshaler interface.
+// It accepts both a JSON number and the string form used by Snowflake.
+func (n *NumericSnowflake) UnmarshalJSON(data []byte) error {
+	return (*Snowflake)(n).UnmarshalJSON(data)
+}
+
+// MarshalText implements encoding.TextMarshaler interface
+func (n NumericSnowflake) MarshalText() ([]byte, error) {
+	return Snowflake(n).MarshalText()
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler interface
+func (n *NumericSnowflake) UnmarshalText(text []byte) error {
+	return (*Snowflake)(n).UnmarshalText(text)
+}
+
+// String implements fmt.Stringer interface
+func (n NumericSnowflake) String() string {
+	return Snowflake(n).String()
+}
+
+// IsJSSafe reports whether s is at most 2^53-1, JavaScript's
+// Number.MAX_SAFE_INTEGER, so it survives being decoded as a JSON number
+// into a float64. Larger Snowflakes should be sent as strings.
+func (s Snowflake) IsJSSafe() bool {
+	return s <= maxExactFloat
+}
+
+// MarshalJSONNumberChecked returns s as a JSON number, like
+// NumericSnowflake's MarshalJSON, or an error wrapping ErrUnsafeInteger
+// if it is not IsJSSafe.
+func (s Snowflake) MarshalJSONNumberChecked() ([]byte, error) {
+	if !s.IsJSSafe() {
+		return nil, fmt.Errorf("%w: %d", ErrUnsafeInteger, s)
+	}
+
+	return NumericSnowflake(s).MarshalJSON()
+}
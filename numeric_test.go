@@ -0,0 +1,166 @@
+// MIT License
+
+// Copyright (c) 2022 Project-Sparrow
+// Copyright (c) 2023 Kelwing <kelwing@kelnet.org>
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package snowflake_test
+
+import (
+	"encoding/json"
+	"errors"
+	"math"
+	"testing"
+
+	"wumpgo.dev/snowflake"
+)
+
+func TestNumericSnowflakeJSON(t *testing.T) {
+	type record struct {
+		ID      snowflake.Snowflake        `json:"id"`
+		Numeric snowflake.NumericSnowflake `json:"numeric"`
+	}
+
+	for _, tt := range []struct {
+		in   snowflake.Snowflake
+		want string
+	}{
+		{0, `{"id":"0","numeric":0}`},
+		{175928847299117063, `{"id":"175928847299117063","numeric":175928847299117063}`},
+		{math.MaxUint64, `{"id":"18446744073709551615","numeric":18446744073709551615}`},
+	} {
+		in := record{ID: tt.in, Numeric: snowflake.NumericSnowflake(tt.in)}
+
+		got, err := json.Marshal(in)
+		if err != nil || string(got) != tt.want {
+			t.Errorf("json.Marshal(%d) = %s, %v, want %s", tt.in, got, err, tt.want)
+		}
+
+		var out record
+		if err := json.Unmarshal(got, &out); err != nil || out != in {
+			t.Errorf("json.Unmarshal(%s) = %+v, %v, want %+v", got, out, err, in)
+		}
+	}
+}
+
+func TestNumericSnowflakeUnmarshalJSON(t *testing.T) {
+	for _, tt := range []struct {
+		in   string
+		want snowflake.NumericSnowflake
+	}{
+		{`175928847299117063`, 175928847299117063},
+		{`"175928847299117063"`, 175928847299117063},
+		{`18446744073709551615`, math.MaxUint64},
+		{`9007199254740993`, 1<<53 + 1},
+		{`0`, 0},
+		{`"0"`, 0},
+	} {
+		var n snowflake.NumericSnowflake
+		if err := json.Unmarshal([]byte(tt.in), &n); err != nil || n != tt.want {
+			t.Errorf("json.Unmarshal(%s) = %d, %v, want %d", tt.in, n, err, tt.want)
+		}
+	}
+
+	for _, tt := range []struct {
+		in   string
+		want error
+	}{
+		{`1.5`, snowflake.ErrNonIntegerJSON},
+		{`1e3`, snowflake.ErrNonIntegerJSON},
+		{`-5`, snowflake.ErrNegative},
+		{`18446744073709551616`, snowflake.ErrOverflow},
+	} {
+		var n snowflake.NumericSnowflake = 7
+		if err := json.Unmarshal([]byte(tt.in), &n); !errors.Is(err, tt.want) || n != 7 {
+			t.Errorf("json.Unmarshal(%s) = %d, %v, want %v", tt.in, n, err, tt.want)
+		}
+	}
+
+	var n snowflake.NumericSnowflake
+	if err := json.Unmarshal([]byte(`true`), &n); err == nil {
+		t.Errorf("json.Unmarshal(true) = %d, want an error", n)
+	}
+}
+
+func TestNumericSnowflakeConversion(t *testing.T) {
+	s := snowflake.Snowflake(175928847299117063)
+	n := snowflake.NumericSnowflake(s)
+
+	if snowflake.Snowflake(n) != s || n.String() != s.String() {
+		t.Errorf("NumericSnowflake(%d) = %v, which does not convert back", s, n)
+	}
+
+	if allocs := testing.AllocsPerRun(100, func() {
+		n = snowflake.NumericSnowflake(s)
+		s = snowflake.Snowflake(n)
+	}); allocs != 0 {
+		t.Errorf("conversion allocates %v times, want 0", allocs)
+	}
+
+	// Text encodings, and so JSON map keys, are the same for both types.
+	got, err := json.Marshal(map[snowflake.NumericSnowflake]bool{n: true})
+	if want := `{"175928847299117063":true}`; err != nil || string(got) != want {
+		t.Errorf("json.Marshal(map) = %s, %v, want %s", got, err, want)
+	}
+}
+
+func TestIsJSSafe(t *testing.T) {
+	for _, tt := range []struct {
+		in   snowflake.Snowflake
+		want bool
+	}{
+		{0, true},
+		{1<<53 - 1, true},
+		{1 << 53, false},
+		{1<<53 + 1, false},
+		{math.MaxUint64, false},
+	} {
+		if got := tt.in.IsJSSafe(); got != tt.want {
+			t.Errorf("Snowflake(%d).IsJSSafe() = %v, want %v", tt.in, got, tt.want)
+		}
+
+		got, err := tt.in.MarshalJSONNumberChecked()
+		switch {
+		case tt.want && (err != nil || string(got) != tt.in.String()):
+			t.Errorf("Snowflake(%d).MarshalJSONNumberChecked() = %s, %v, want %d", tt.in, got, err, tt.in)
+		case !tt.want && (got != nil || !errors.Is(err, snowflake.ErrUnsafeInteger)):
+			t.Errorf("Snowflake(%d).MarshalJSONNumberChecked() = %s, %v, want ErrUnsafeInteger", tt.in, got, err)
+		}
+	}
+}
+
+// idForBrowser sends IDs as numbers when a browser can decode them exactly,
+// and as strings otherwise.
+type idForBrowser snowflake.Snowflake
+
+func (id idForBrowser) MarshalJSON() ([]byte, error) {
+	if b, err := snowflake.Snowflake(id).MarshalJSONNumberChecked(); err == nil {
+		return b, nil
+	}
+
+	return snowflake.Snowflake(id).MarshalJSON()
+}
+
+func TestMarshalJSONNumberCheckedFallback(t *testing.T) {
+	got, err := json.Marshal([]idForBrowser{1<<53 - 1, 1 << 53})
+	if want := `[9007199254740991,"9007199254740992"]`; err != nil || string(got) != want {
+		t.Errorf("json.Marshal() = %s, %v, want %s", got, err, want)
+	}
+}
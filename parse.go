@@ -0,0 +1,334 @@
+// MIT License
+
+// Copyright (c) 2022 Project-Sparrow
+// Copyright (c) 2023 Kelwing <kelwing@kelnet.org>
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package snowflake
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+)
+
+// Errors wrapped by a ParseError.
+var (
+	// ErrSyntax reports that the input is not a decimal number.
+	ErrSyntax = errors.New("invalid syntax")
+
+	// ErrNegative reports that the input is a negative number.
+	ErrNegative = errors.New("negative value")
+
+	// ErrOverflow reports that the input does not fit in 64 bits.
+	ErrOverflow = errors.New("value out of range")
+
+	// ErrInexact reports that a float passed to ParseAny is not an integer
+	// that float64 represents exactly.
+	ErrInexact = errors.New("float is not an exact integer")
+
+	// ErrTooLong reports that the input is longer than any encoding of a
+	// Snowflake needs, and was rejected without being parsed.
+	ErrTooLong = errors.New("input too long")
+
+	// ErrUnsupportedType reports that ParseAny cannot parse a value of its type.
+	ErrUnsupportedType = errors.New("unsupported type for a snowflake")
+)
+
+// maxExactFloat is the largest integer that no other integer rounds to
+// as a float64, JavaScript's Number.MAX_SAFE_INTEGER.
+const maxExactFloat = 1<<53 - 1
+
+// ParseError is returned when a Snowflake cannot be parsed.
+// It wraps ErrSyntax, ErrNegative or ErrOverflow, as well as strconv.ErrSyntax
+// or strconv.ErrRange for callers that check those.
+type ParseError struct {
+	Func  string // the function that failed, such as "SnowflakeFromString"
+	Input string // the input, truncated and followed by "..." if too long
+	Err   error  // ErrSyntax, ErrNegative, ErrOverflow, ErrInexact or ErrTooLong
+}
+
+// Error implements error interface
+func (e *ParseError) Error() string {
+	return e.Func + ": parsing " + strconv.Quote(e.Input) + ": " + e.Err.Error()
+}
+
+// Unwrap returns e.Err and the matching strconv error.
+func (e *ParseError) Unwrap() []error {
+	if e.Err == ErrOverflow {
+		return []error{e.Err, strconv.ErrRange}
+	}
+	return []error{e.Err, strconv.ErrSyntax}
+}
+
+// MustParse is like SnowflakeFromString but panics if s cannot be parsed.
+// It simplifies initializing Snowflakes in tests and fixtures.
+func MustParse(s string) Snowflake {
+	sf, err := SnowflakeFromString(s)
+	if err != nil {
+		panic(fmt.Sprintf("snowflake: MustParse(%q): %v", s, err))
+	}
+
+	return sf
+}
+
+// MustParseBytes is like MustParse but parses b.
+func MustParseBytes(b []byte) Snowflake {
+	sf, err := ParseBytes(b)
+	if err != nil {
+		panic(fmt.Sprintf("snowflake: MustParseBytes(%q): %v", b, err))
+	}
+
+	return sf
+}
+
+// ParseBytes is like SnowflakeFromString but parses the decimal digits in b
+// directly, without allocating unless b is invalid.
+func ParseBytes(b []byte) (Snowflake, error) {
+	return parseDecimal("ParseBytes", b)
+}
+
+// ParseStrict parses the canonical decimal form of a Snowflake, as returned
+// by String: a non-empty string of ASCII digits without leading zeros,
+// except for "0" itself. Unlike SnowflakeFromString it rejects "0000123".
+func ParseStrict(s string) (Snowflake, error) {
+	if len(s) > maxDecimalLen {
+		return 0, tooLong("ParseStrict", s, maxDecimalLen)
+	}
+
+	if len(s) > 1 && s[0] == '0' {
+		return 0, &ParseError{Func: "ParseStrict", Input: s, Err: ErrSyntax}
+	}
+
+	return parseDecimal("ParseStrict", s)
+}
+
+// ParseSortable parses the zero-padded form returned by SortableString,
+// which is exactly 20 ASCII digits.
+func ParseSortable(s string) (Snowflake, error) {
+	if len(s) > maxDecimalLen {
+		return 0, tooLong("ParseSortable", s, maxDecimalLen)
+	}
+
+	if len(s) != maxDecimalLen {
+		return 0, &ParseError{Func: "ParseSortable", Input: s, Err: ErrSyntax}
+	}
+
+	return parseDecimal("ParseSortable", s)
+}
+
+// ParseAny parses a Snowflake from a value decoded from a loosely typed
+// payload such as a map[string]any, where IDs may be strings or numbers.
+// It accepts:
+//
+//   - string, []byte and json.Number, in decimal as for SnowflakeFromString
+//   - every integer type, if not negative
+//   - float32 and float64, if they hold an integer no larger than 2^53-1,
+//     above which a float may have been rounded from a different ID
+//   - Snowflake
+//
+// Errors for values of these types are a *ParseError, and other types
+// return an error wrapping ErrUnsupportedType.
+func ParseAny(v any) (Snowflake, error) {
+	switch v := v.(type) {
+	case string:
+		return parseDecimal("ParseAny", v)
+	case []byte:
+		return parseDecimal("ParseAny", v)
+	case json.Number:
+		return parseDecimal("ParseAny", string(v))
+	case Snowflake:
+		return v, nil
+	case int:
+		return parseInt(int64(v))
+	case int8:
+		return parseInt(int64(v))
+	case int16:
+		return parseInt(int64(v))
+	case int32:
+		return parseInt(int64(v))
+	case int64:
+		return parseInt(v)
+	case uint:
+		return Snowflake(v), nil
+	case uint8:
+		return Snowflake(v), nil
+	case uint16:
+		return Snowflake(v), nil
+	case uint32:
+		return Snowflake(v), nil
+	case uint64:
+		return Snowflake(v), nil
+	case float32:
+		return parseFloat(float64(v))
+	case float64:
+		return parseFloat(v)
+	default:
+		return 0, fmt.Errorf("%w: %T", ErrUnsupportedType, v)
+	}
+}
+
+func parseInt(v int64) (Snowflake, error) {
+	if v < 0 {
+		return 0, &ParseError{Func: "ParseAny", Input: strconv.FormatInt(v, 10), Err: ErrNegative}
+	}
+	return Snowflake(v), nil
+}
+
+func parseFloat(v float64) (Snowflake, error) {
+	switch {
+	case v < 0:
+		return 0, &ParseError{Func: "ParseAny", Input: strconv.FormatFloat(v, 'g', -1, 64), Err: ErrNegative}
+	case v > maxExactFloat || v != math.Trunc(v):
+		// NaN fails v != math.Trunc(v).
+		return 0, &ParseError{Func: "ParseAny", Input: strconv.FormatFloat(v, 'g', -1, 64), Err: ErrInexact}
+	}
+	return Snowflake(v), nil
+}
+
+// IndexError reports the element of the input to ParseAll that failed.
+type IndexError struct {
+	Index int    // the index of the element
+	Input string // the element
+	Err   error  // the *ParseError for the element
+}
+
+// Error implements error interface
+func (e *IndexError) Error() string {
+	return "element " + strconv.Itoa(e.Index) + ": " + e.Err.Error()
+}
+
+// Unwrap returns e.Err.
+func (e *IndexError) Unwrap() error {
+	return e.Err
+}
+
+type parseAllOptions struct {
+	allErrors bool
+}
+
+// ParseAllOption configures ParseAll.
+type ParseAllOption func(*parseAllOptions)
+
+// WithAllErrors makes ParseAll parse every element and report all
+// that fail instead of stopping at the first.
+func WithAllErrors() ParseAllOption {
+	return func(o *parseAllOptions) {
+		o.allErrors = true
+	}
+}
+
+// ParseAll parses every element of ss as for SnowflakeFromString.
+//
+// By default it stops at the first element that fails and returns a nil
+// slice and an *IndexError for it. With WithAllErrors it returns every
+// Snowflake, with zeros for the elements that failed, and an error joining
+// an *IndexError for each of them.
+func ParseAll(ss []string, opts ...ParseAllOption) ([]Snowflake, error) {
+	// o escapes to the options, so only allocate it if there are any.
+	var o parseAllOptions
+	if len(opts) > 0 {
+		p := new(parseAllOptions)
+		for _, opt := range opts {
+			opt(p)
+		}
+		o = *p
+	}
+
+	out := make([]Snowflake, len(ss))
+	var errs []error
+	for i, s := range ss {
+		v, err := parseDecimal("ParseAll", s)
+		if err != nil {
+			err = &IndexError{Index: i, Input: s, Err: err}
+			if !o.allErrors {
+				return nil, err
+			}
+
+			errs = append(errs, err)
+			continue
+		}
+
+		out[i] = v
+	}
+
+	if len(errs) > 0 {
+		return out, errors.Join(errs...)
+	}
+
+	return out, nil
+}
+
+// parseDecimal parses the decimal digits in b, reporting errors as fn.
+func parseDecimal[T string | []byte](fn string, b T) (Snowflake, error) {
+	if len(b) > maxDecimalLen {
+		return 0, tooLong(fn, b, maxDecimalLen)
+	}
+
+	var n uint64
+	overflow := false
+	for i := 0; i < len(b); i++ {
+		c := b[i]
+		if c < '0' || c > '9' {
+			return 0, &ParseError{Func: fn, Input: string(b), Err: syntaxError(b)}
+		}
+
+		d := uint64(c - '0')
+		if n > (math.MaxUint64-d)/10 {
+			// Keep checking the syntax, which takes precedence.
+			overflow = true
+		}
+
+		n = n*10 + d
+	}
+
+	switch {
+	case len(b) == 0:
+		return 0, &ParseError{Func: fn, Input: "", Err: ErrSyntax}
+	case overflow:
+		return 0, &ParseError{Func: fn, Input: string(b), Err: ErrOverflow}
+	}
+
+	return Snowflake(n), nil
+}
+
+// tooLong returns a ParseError wrapping ErrTooLong for input longer than
+// max bytes, with the input truncated to max.
+func tooLong[T string | []byte](fn string, b T, max int) *ParseError {
+	return &ParseError{Func: fn, Input: string(b[:max]) + "...", Err: ErrTooLong}
+}
+
+// syntaxError returns ErrNegative if b is a minus sign followed by digits,
+// and ErrSyntax otherwise.
+func syntaxError[T string | []byte](b T) error {
+	if len(b) < 2 || b[0] != '-' {
+		return ErrSyntax
+	}
+
+	for i := 1; i < len(b); i++ {
+		if b[i] < '0' || b[i] > '9' {
+			return ErrSyntax
+		}
+	}
+
+	return ErrNegative
+}
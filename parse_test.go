@@ -0,0 +1,650 @@
+// MIT License
+
+// Copyright (c) 2022 Project-Sparrow
+// Copyright (c) 2023 Kelwing <kelwing@kelnet.org>
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package snowflake_test
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"math/rand"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"wumpgo.dev/snowflake"
+)
+
+// mustPanic calls f and returns the message it panicked with.
+func mustPanic(t *testing.T, f func()) (msg string) {
+	t.Helper()
+
+	defer func() {
+		r := recover()
+		if r == nil {
+			t.Error("did not panic")
+		}
+		msg = fmt.Sprint(r)
+	}()
+	f()
+
+	return ""
+}
+
+func TestMustParse(t *testing.T) {
+	if got := snowflake.MustParse("1069557246566533180"); got != 1069557246566533180 {
+		t.Errorf("MustParse() = %d, want 1069557246566533180", got)
+	}
+
+	if got := snowflake.MustParseBytes([]byte("1069557246566533180")); got != 1069557246566533180 {
+		t.Errorf("MustParseBytes() = %d, want 1069557246566533180", got)
+	}
+
+	for _, in := range []string{"abc", "", "-1"} {
+		want := fmt.Sprintf("MustParse(%q)", in)
+		if msg := mustPanic(t, func() { snowflake.MustParse(in) }); !strings.HasPrefix(msg, "snowflake: "+want) {
+			t.Errorf("MustParse(%q) panicked with %q, want it to start with %q", in, msg, "snowflake: "+want)
+		}
+
+		want = fmt.Sprintf("MustParseBytes(%q)", in)
+		if msg := mustPanic(t, func() { snowflake.MustParseBytes([]byte(in)) }); !strings.HasPrefix(msg, "snowflake: "+want) {
+			t.Errorf("MustParseBytes(%q) panicked with %q, want it to start with %q", in, msg, "snowflake: "+want)
+		}
+	}
+}
+
+func TestParseBytes(t *testing.T) {
+	for _, tt := range []struct {
+		in   string
+		want snowflake.Snowflake
+		err  error
+	}{
+		{"0", 0, nil},
+		{"1069557246566533180", 1069557246566533180, nil},
+		{"18446744073709551615", math.MaxUint64, nil},
+		{"18446744073709551616", 0, snowflake.ErrOverflow},
+		{"99999999999999999999", 0, snowflake.ErrOverflow},
+		{"00000000000000000001", 1, nil},
+		{"000000000000000000001", 0, snowflake.ErrTooLong},
+		{"99999999999999999999999", 0, snowflake.ErrTooLong},
+		{"", 0, snowflake.ErrSyntax},
+		{"12a", 0, snowflake.ErrSyntax},
+		{"-1", 0, snowflake.ErrNegative},
+		{" 1", 0, snowflake.ErrSyntax},
+	} {
+		got, err := snowflake.ParseBytes([]byte(tt.in))
+		if got != tt.want || !errors.Is(err, tt.err) || (tt.err == nil) != (err == nil) {
+			t.Errorf("ParseBytes(%q) = %d, %v, want %d, %v", tt.in, got, err, tt.want, tt.err)
+		}
+	}
+}
+
+func TestParseBytesAllocs(t *testing.T) {
+	b := []byte("1069557246566533180")
+	if n := testing.AllocsPerRun(100, func() { snowflake.ParseBytes(b) }); n != 0 {
+		t.Errorf("ParseBytes allocates %v times, want 0", n)
+	}
+}
+
+func FuzzParseBytes(f *testing.F) {
+	for _, s := range []string{"0", "1069557246566533180", "18446744073709551615", "18446744073709551616", "", "+1", "-0", "1_000", "00000000000000000001", "000000000000000000001"} {
+		f.Add([]byte(s))
+	}
+
+	f.Fuzz(func(t *testing.T, b []byte) {
+		got, err := snowflake.ParseBytes(b)
+		if len(b) > 20 {
+			if !errors.Is(err, snowflake.ErrTooLong) {
+				t.Errorf("ParseBytes(%d bytes) = %d, %v, want ErrTooLong", len(b), got, err)
+			}
+			return
+		}
+
+		want, wantErr := strconv.ParseUint(string(b), 10, 64)
+
+		if (err == nil) != (wantErr == nil) || uint64(got) != want && err == nil {
+			t.Errorf("ParseBytes(%q) = %d, %v, but strconv.ParseUint = %d, %v", b, got, err, want, wantErr)
+		}
+	})
+}
+
+func BenchmarkParseBytes(b *testing.B) {
+	in := []byte("1069557246566533180")
+	for i := 0; i < b.N; i++ {
+		snowflake.ParseBytes(in)
+	}
+}
+
+func TestParseStrict(t *testing.T) {
+	for _, tt := range []struct {
+		in   string
+		want snowflake.Snowflake
+	}{
+		{"0", 0},
+		{"7", 7},
+		{"1069557246566533180", 1069557246566533180},
+		{"18446744073709551615", math.MaxUint64},
+	} {
+		if got, err := snowflake.ParseStrict(tt.in); err != nil || got != tt.want {
+			t.Errorf("ParseStrict(%q) = %d, %v, want %d", tt.in, got, err, tt.want)
+		}
+	}
+
+	for _, in := range []string{
+		"",
+		" ",
+		" 123",
+		"123 ",
+		"\t123\n",
+		"+123",
+		"-123",
+		"-0",
+		"00",
+		"0123",
+		"0000123",
+		"1_000",
+		"0x1f",
+		"1e3",
+		"12.0",
+		"١٢٣",
+		"18446744073709551616",
+	} {
+		if got, err := snowflake.ParseStrict(in); err == nil {
+			t.Errorf("ParseStrict(%q) = %d, want error", in, got)
+		}
+	}
+
+	// SnowflakeFromString is lenient about leading zeros.
+	if got, err := snowflake.SnowflakeFromString("0000123"); err != nil || got != 123 {
+		t.Errorf("SnowflakeFromString(%q) = %d, %v, want 123", "0000123", got, err)
+	}
+}
+
+func TestParseSortable(t *testing.T) {
+	for _, tt := range []struct {
+		s    snowflake.Snowflake
+		want string
+	}{
+		{0, "00000000000000000000"},
+		{9, "00000000000000000009"},
+		{10, "00000000000000000010"},
+		{175928847299117063, "00175928847299117063"},
+		{math.MaxUint64, "18446744073709551615"},
+	} {
+		if got := tt.s.SortableString(); got != tt.want {
+			t.Errorf("SortableString(%d) = %q, want %q", tt.s, got, tt.want)
+		}
+
+		if got, err := snowflake.ParseSortable(tt.want); err != nil || got != tt.s {
+			t.Errorf("ParseSortable(%q) = %d, %v, want %d", tt.want, got, err, tt.s)
+		}
+	}
+
+	for _, tt := range []struct {
+		in  string
+		err error
+	}{
+		{"", snowflake.ErrSyntax},
+		{"0", snowflake.ErrSyntax},
+		{"175928847299117063", snowflake.ErrSyntax},
+		{"000175928847299117063", snowflake.ErrTooLong},
+		{" 0175928847299117063", snowflake.ErrSyntax},
+		{"+0175928847299117063", snowflake.ErrSyntax},
+		{"-0175928847299117063", snowflake.ErrNegative},
+		{"0017592884729911706x", snowflake.ErrSyntax},
+		{"18446744073709551616", snowflake.ErrOverflow},
+		{"99999999999999999999", snowflake.ErrOverflow},
+	} {
+		got, err := snowflake.ParseSortable(tt.in)
+
+		var perr *snowflake.ParseError
+		if !errors.Is(err, tt.err) || !errors.As(err, &perr) || perr.Func != "ParseSortable" {
+			t.Errorf("ParseSortable(%q) = %d, %v, want a ParseError wrapping %v", tt.in, got, err, tt.err)
+		}
+	}
+}
+
+func TestSortableStringOrder(t *testing.T) {
+	g, err := snowflake.New(snowflake.WithEpoch(time.Now().Add(-time.Hour)), snowflake.WithWorkerID(3))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	r := rand.New(rand.NewSource(1))
+	ids := []snowflake.Snowflake{0, 1, 9, 10, 99, 100, math.MaxUint64}
+	for i := 0; i < 500; i++ {
+		ids = append(ids, g.Generate(), snowflake.Snowflake(r.Uint64()>>r.Intn(64)))
+	}
+	r.Shuffle(len(ids), func(i, j int) { ids[i], ids[j] = ids[j], ids[i] })
+
+	keys := make([]string, len(ids))
+	for i, s := range ids {
+		keys[i] = s.SortableString()
+	}
+
+	sort.Strings(keys)
+	slices.Sort(ids)
+
+	for i, s := range ids {
+		if keys[i] != s.SortableString() {
+			t.Fatalf("sorted SortableString()[%d] = %q, want %q", i, keys[i], s.SortableString())
+		}
+	}
+}
+
+func TestParseAny(t *testing.T) {
+	for _, tt := range []struct {
+		in   any
+		want snowflake.Snowflake
+	}{
+		{"175928847299117063", 175928847299117063},
+		{"0042", 42},
+		{[]byte("175928847299117063"), 175928847299117063},
+		{json.Number("175928847299117063"), 175928847299117063},
+		{snowflake.Snowflake(42), 42},
+		{int(42), 42},
+		{int8(math.MaxInt8), math.MaxInt8},
+		{int16(math.MaxInt16), math.MaxInt16},
+		{int32(math.MaxInt32), math.MaxInt32},
+		{int64(math.MaxInt64), math.MaxInt64},
+		{uint(42), 42},
+		{uint8(math.MaxUint8), math.MaxUint8},
+		{uint16(math.MaxUint16), math.MaxUint16},
+		{uint32(math.MaxUint32), math.MaxUint32},
+		{uint64(math.MaxUint64), math.MaxUint64},
+		{float32(42), 42},
+		{float64(0), 0},
+		{math.Copysign(0, -1), 0},
+		{float64(175928847299), 175928847299},
+		{float64(1<<53 - 1), 1<<53 - 1},
+		{float32(1 << 24), 1 << 24},
+		{"18446744073709551615", math.MaxUint64},
+	} {
+		if got, err := snowflake.ParseAny(tt.in); err != nil || got != tt.want {
+			t.Errorf("ParseAny(%T(%v)) = %d, %v, want %d", tt.in, tt.in, got, err, tt.want)
+		}
+	}
+
+	for _, tt := range []struct {
+		in  any
+		err error
+	}{
+		{"", snowflake.ErrSyntax},
+		{"12.0", snowflake.ErrSyntax},
+		{"-1", snowflake.ErrNegative},
+		{"18446744073709551616", snowflake.ErrOverflow},
+		{[]byte("abc"), snowflake.ErrSyntax},
+		{json.Number("1e3"), snowflake.ErrSyntax},
+		{json.Number("-5"), snowflake.ErrNegative},
+		{int(-1), snowflake.ErrNegative},
+		{int8(-1), snowflake.ErrNegative},
+		{int16(-1), snowflake.ErrNegative},
+		{int32(-1), snowflake.ErrNegative},
+		{int64(math.MinInt64), snowflake.ErrNegative},
+		{float32(-1), snowflake.ErrNegative},
+		{float64(-1), snowflake.ErrNegative},
+		{float64(1.5), snowflake.ErrInexact},
+		{float32(0.1), snowflake.ErrInexact},
+		{float64(1 << 53), snowflake.ErrInexact},   // could be 2^53 + 1 rounded
+		{float64(1<<53 + 2), snowflake.ErrInexact}, // exact, but above the limit
+		{float64(175928847299117063), snowflake.ErrInexact},
+		{math.Inf(1), snowflake.ErrInexact},
+		{math.Inf(-1), snowflake.ErrNegative},
+		{math.NaN(), snowflake.ErrInexact},
+	} {
+		got, err := snowflake.ParseAny(tt.in)
+
+		var perr *snowflake.ParseError
+		if !errors.Is(err, tt.err) || !errors.As(err, &perr) || perr.Func != "ParseAny" {
+			t.Errorf("ParseAny(%T(%v)) = %d, %v, want a ParseError wrapping %v", tt.in, tt.in, got, err, tt.err)
+		}
+	}
+
+	for _, in := range []any{nil, true, complex(1, 0), []string{"1"}, new(int), struct{}{}} {
+		if got, err := snowflake.ParseAny(in); !errors.Is(err, snowflake.ErrUnsupportedType) {
+			t.Errorf("ParseAny(%T) = %d, %v, want ErrUnsupportedType", in, got, err)
+		}
+	}
+}
+
+func TestParseAnyJSON(t *testing.T) {
+	var payload map[string]any
+	if err := json.Unmarshal([]byte(`{"a": "175928847299117063", "b": 175928847299, "c": 1.5, "d": 175928847299117063}`), &payload); err != nil {
+		t.Fatal(err)
+	}
+
+	if got, err := snowflake.ParseAny(payload["a"]); err != nil || got != 175928847299117063 {
+		t.Errorf("ParseAny(a) = %d, %v, want 175928847299117063", got, err)
+	}
+
+	if got, err := snowflake.ParseAny(payload["b"]); err != nil || got != 175928847299 {
+		t.Errorf("ParseAny(b) = %d, %v, want 175928847299", got, err)
+	}
+
+	if _, err := snowflake.ParseAny(payload["c"]); !errors.Is(err, snowflake.ErrInexact) {
+		t.Errorf("ParseAny(c) = %v, want ErrInexact", err)
+	}
+
+	// Decoded as float64, the ID has lost precision and is rejected.
+	if _, err := snowflake.ParseAny(payload["d"]); !errors.Is(err, snowflake.ErrInexact) {
+		t.Errorf("ParseAny(d) = %v, want ErrInexact", err)
+	}
+
+	// With UseNumber it is exact.
+	dec := json.NewDecoder(strings.NewReader(`{"d": 175928847299117063}`))
+	dec.UseNumber()
+	if err := dec.Decode(&payload); err != nil {
+		t.Fatal(err)
+	}
+
+	if got, err := snowflake.ParseAny(payload["d"]); err != nil || got != 175928847299117063 {
+		t.Errorf("ParseAny(d) with UseNumber = %d, %v, want 175928847299117063", got, err)
+	}
+}
+
+func TestParseAll(t *testing.T) {
+	for _, in := range [][]string{nil, {}} {
+		got, err := snowflake.ParseAll(in)
+		if err != nil || got == nil || len(got) != 0 {
+			t.Errorf("ParseAll(%#v) = %#v, %v, want an empty slice", in, got, err)
+		}
+	}
+
+	in := []string{"0", "1", "175928847299117063", "18446744073709551615"}
+	want := []snowflake.Snowflake{0, 1, 175928847299117063, math.MaxUint64}
+	for _, opts := range [][]snowflake.ParseAllOption{nil, {snowflake.WithAllErrors()}} {
+		if got, err := snowflake.ParseAll(in, opts...); err != nil || !slices.Equal(got, want) {
+			t.Errorf("ParseAll(%q) = %v, %v, want %v", in, got, err, want)
+		}
+	}
+}
+
+func TestParseAllFirstError(t *testing.T) {
+	in := []string{"1", "2", "x3", "4", "-5"}
+
+	got, err := snowflake.ParseAll(in)
+	if got != nil {
+		t.Errorf("ParseAll(%q) = %v, want nil", in, got)
+	}
+
+	var ierr *snowflake.IndexError
+	if !errors.As(err, &ierr) || ierr.Index != 2 || ierr.Input != "x3" {
+		t.Fatalf("ParseAll(%q) = %v, want an IndexError for element 2", in, err)
+	}
+
+	if !errors.Is(err, snowflake.ErrSyntax) {
+		t.Errorf("ParseAll(%q) = %v, want ErrSyntax", in, err)
+	}
+
+	if want := `element 2: ParseAll: parsing "x3": invalid syntax`; err.Error() != want {
+		t.Errorf("Error() = %q, want %q", err.Error(), want)
+	}
+}
+
+func TestParseAllAllErrors(t *testing.T) {
+	in := []string{"1", "", "3", "-4", "18446744073709551616"}
+
+	got, err := snowflake.ParseAll(in, snowflake.WithAllErrors())
+	if want := []snowflake.Snowflake{1, 0, 3, 0, 0}; !slices.Equal(got, want) {
+		t.Errorf("ParseAll(%q) = %v, want %v", in, got, want)
+	}
+
+	joined, ok := err.(interface{ Unwrap() []error })
+	if !ok {
+		t.Fatalf("ParseAll(%q) = %v, want joined errors", in, err)
+	}
+
+	wantErrs := []struct {
+		index int
+		err   error
+	}{
+		{1, snowflake.ErrSyntax},
+		{3, snowflake.ErrNegative},
+		{4, snowflake.ErrOverflow},
+	}
+
+	errs := joined.Unwrap()
+	if len(errs) != len(wantErrs) {
+		t.Fatalf("ParseAll(%q) returned %d errors, want %d: %v", in, len(errs), len(wantErrs), err)
+	}
+
+	for i, want := range wantErrs {
+		var ierr *snowflake.IndexError
+		if !errors.As(errs[i], &ierr) || ierr.Index != want.index || ierr.Input != in[want.index] || !errors.Is(ierr, want.err) {
+			t.Errorf("error %d = %v, want element %d wrapping %v", i, errs[i], want.index, want.err)
+		}
+	}
+}
+
+func TestParseAllAllocs(t *testing.T) {
+	in := []string{"1", "175928847299117063", "18446744073709551615"}
+
+	if n := testing.AllocsPerRun(100, func() { snowflake.ParseAll(in) }); n != 1 {
+		t.Errorf("ParseAll allocates %v times, want 1", n)
+	}
+}
+
+func BenchmarkParseAll(b *testing.B) {
+	r := rand.New(rand.NewSource(1))
+	in := make([]string, 10000)
+	for i := range in {
+		in[i] = strconv.FormatUint(r.Uint64(), 10)
+	}
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		if _, err := snowflake.ParseAll(in); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func TestSnowflakeFromStringErrors(t *testing.T) {
+	for _, tt := range []struct {
+		in         string
+		err        error
+		strconvErr error
+	}{
+		{"", snowflake.ErrSyntax, strconv.ErrSyntax},
+		{"abc", snowflake.ErrSyntax, strconv.ErrSyntax},
+		{"+5", snowflake.ErrSyntax, strconv.ErrSyntax},
+		{"-", snowflake.ErrSyntax, strconv.ErrSyntax},
+		{"-5x", snowflake.ErrSyntax, strconv.ErrSyntax},
+		{"-5", snowflake.ErrNegative, strconv.ErrSyntax},
+		{"-9999999999999999999", snowflake.ErrNegative, strconv.ErrSyntax},
+		{"18446744073709551616", snowflake.ErrOverflow, strconv.ErrRange},
+		{"99999999999999999999", snowflake.ErrOverflow, strconv.ErrRange},
+		{"9999999999999999999x", snowflake.ErrSyntax, strconv.ErrSyntax},
+	} {
+		got, err := snowflake.SnowflakeFromString(tt.in)
+		if got != 0 || !errors.Is(err, tt.err) || !errors.Is(err, tt.strconvErr) {
+			t.Errorf("SnowflakeFromString(%q) = %d, %v, want %v", tt.in, got, err, tt.err)
+		}
+
+		var pe *snowflake.ParseError
+		if !errors.As(err, &pe) || pe.Func != "SnowflakeFromString" || pe.Input != tt.in {
+			t.Errorf("SnowflakeFromString(%q) error = %#v, want a ParseError for the input", tt.in, err)
+		}
+	}
+
+	_, err := snowflake.SnowflakeFromString("-5")
+	if want := `SnowflakeFromString: parsing "-5": negative value`; err.Error() != want {
+		t.Errorf("Error() = %q, want %q", err.Error(), want)
+	}
+}
+
+func FuzzSnowflakeFromString(f *testing.F) {
+	for _, s := range []string{"0", "-5", "18446744073709551615", "18446744073709551616", "99999999999999999999999", "", "+1", "-", "007"} {
+		f.Add(s)
+	}
+
+	maxUint64 := new(big.Int).SetUint64(math.MaxUint64)
+	digits := func(s string) bool {
+		for _, c := range []byte(s) {
+			if c < '0' || c > '9' {
+				return false
+			}
+		}
+		return s != ""
+	}
+
+	f.Fuzz(func(t *testing.T, s string) {
+		got, err := snowflake.SnowflakeFromString(s)
+
+		var want error
+		switch {
+		case len(s) > 20:
+			want = snowflake.ErrTooLong
+		case strings.HasPrefix(s, "-") && digits(s[1:]):
+			want = snowflake.ErrNegative
+		case !digits(s):
+			want = snowflake.ErrSyntax
+		default:
+			n, _ := new(big.Int).SetString(s, 10)
+			if n.Cmp(maxUint64) > 0 {
+				want = snowflake.ErrOverflow
+			} else if err != nil || uint64(got) != n.Uint64() {
+				t.Fatalf("SnowflakeFromString(%q) = %d, %v, want %v", s, got, err, n)
+			}
+		}
+
+		if want != nil && (got != 0 || !errors.Is(err, want)) {
+			t.Fatalf("SnowflakeFromString(%q) = %d, %v, want %v", s, got, err, want)
+		}
+	})
+}
+
+func TestParseTooLong(t *testing.T) {
+	long := strings.Repeat("0", 1<<20) + "1"
+
+	for name, parse := range map[string]func(string) (snowflake.Snowflake, error){
+		"SnowflakeFromString": snowflake.SnowflakeFromString,
+		"ParseBytes":          func(s string) (snowflake.Snowflake, error) { return snowflake.ParseBytes([]byte(s)) },
+		"ParseStrict":         snowflake.ParseStrict,
+		"ParseSortable":       snowflake.ParseSortable,
+		"ParseChecked":        snowflake.ParseChecked,
+		"ParseAny":            func(s string) (snowflake.Snowflake, error) { return snowflake.ParseAny(s) },
+		"ParseHex":            snowflake.ParseHex,
+		"ParseBase32":         snowflake.ParseBase32,
+		"ParseBase58":         snowflake.ParseBase58,
+		"ParseBase62":         snowflake.ParseBase62,
+		"ParseBase64":         snowflake.ParseBase64,
+		"FromULIDTimestamp":   snowflake.FromULIDTimestamp,
+		"ParseFormat":         func(s string) (snowflake.Snowflake, error) { return snowflake.ParseFormat("{dec}", s) },
+		"KSUIDBounds": func(s string) (snowflake.Snowflake, error) {
+			lo, _, err := snowflake.KSUIDBounds(s)
+			return lo, err
+		},
+	} {
+		got, err := parse(long)
+		if !errors.Is(err, snowflake.ErrTooLong) {
+			t.Errorf("%s(%d bytes) = %d, %v, want ErrTooLong", name, len(long), got, err)
+		}
+
+		if len(err.Error()) > 200 {
+			t.Errorf("%s(%d bytes) error is %d bytes long", name, len(long), len(err.Error()))
+		}
+	}
+
+	_, err := snowflake.SnowflakeFromString(long)
+	var perr *snowflake.ParseError
+	if !errors.As(err, &perr) || perr.Input != strings.Repeat("0", 20)+"..." {
+		t.Errorf("SnowflakeFromString(%d bytes) = %#v, want a ParseError with truncated input", len(long), err)
+	}
+}
+
+func TestParseLengthBoundaries(t *testing.T) {
+	max := snowflake.Snowflake(math.MaxUint64)
+
+	for _, tt := range []struct {
+		name    string
+		parse   func(string) (snowflake.Snowflake, error)
+		longest string // the longest input accepted
+		extra   string // makes longest one byte too long
+	}{
+		{"SnowflakeFromString", snowflake.SnowflakeFromString, "00000000000000000042", "0"},
+		{"ParseBytes", func(s string) (snowflake.Snowflake, error) { return snowflake.ParseBytes([]byte(s)) }, "00000000000000000042", "0"},
+		{"ParseStrict", snowflake.ParseStrict, max.String(), "0"},
+		{"ParseSortable", snowflake.ParseSortable, max.SortableString(), "0"},
+		{"ParseChecked", snowflake.ParseChecked, max.StringChecked(), "0"},
+		{"ParseHex", snowflake.ParseHex, "0x" + max.Hex(), "0"},
+		{"ParseBase32", snowflake.ParseBase32, "F-Z-Z-Z-Z-Z-Z-Z-Z-Z-Z-Z-Z", "-"},
+		{"ParseBase58", snowflake.ParseBase58, max.EncodeBase58(), "1"},
+		{"ParseBase62", snowflake.ParseBase62, max.EncodeBase62(), "0"},
+		{"ParseBase64", snowflake.ParseBase64, max.EncodeBase64(), "A"},
+		{"ParseFormat", func(s string) (snowflake.Snowflake, error) { return snowflake.ParseFormat("id {dec:3,}", s) }, "id 18,446,744,073,709,551,615", "0"},
+	} {
+		if _, err := tt.parse(tt.longest); err != nil {
+			t.Errorf("%s(%q) = %v, want nil", tt.name, tt.longest, err)
+		}
+
+		if got, err := tt.parse(tt.extra + tt.longest); !errors.Is(err, snowflake.ErrTooLong) {
+			t.Errorf("%s(%q) = %d, %v, want ErrTooLong", tt.name, tt.extra+tt.longest, got, err)
+		}
+	}
+}
+
+// FuzzParse checks that no string parser panics, and that every Snowflake
+// they accept formats back to something they parse to the same value.
+func FuzzParse(f *testing.F) {
+	for _, s := range []string{"0", "1069557246566533180", "18446744073709551615", "0x0ed7c1d6a8000000", "4W86BB0G4007", "jpXCZedGfVQ", "LygHa16AHYF", "AAAAAAAAAAA", "01ARZ3NDEKTSV4RRFFQ69G5FAV", "1759288472991170635", "",
+		// One byte past the decimal, hex and base32 caps.
+		"000000000000000000001", "0x00000000000000001", "F-Z-Z-Z-Z-Z-Z-Z-Z-Z-Z-Z-Z-"} {
+		f.Add(s)
+	}
+
+	parsers := []struct {
+		name   string
+		parse  func(string) (snowflake.Snowflake, error)
+		format func(snowflake.Snowflake) string
+	}{
+		{"SnowflakeFromString", snowflake.SnowflakeFromString, snowflake.Snowflake.String},
+		{"ParseStrict", snowflake.ParseStrict, snowflake.Snowflake.String},
+		{"ParseSortable", snowflake.ParseSortable, snowflake.Snowflake.SortableString},
+		{"ParseChecked", snowflake.ParseChecked, snowflake.Snowflake.StringChecked},
+		{"ParseHex", snowflake.ParseHex, snowflake.Snowflake.Hex},
+		{"ParseBase32", snowflake.ParseBase32, snowflake.Snowflake.EncodeBase32},
+		{"ParseBase58", snowflake.ParseBase58, snowflake.Snowflake.EncodeBase58},
+		{"ParseBase62", snowflake.ParseBase62, snowflake.Snowflake.EncodeBase62},
+		{"ParseBase64", snowflake.ParseBase64, snowflake.Snowflake.EncodeBase64},
+	}
+
+	f.Fuzz(func(t *testing.T, s string) {
+		for _, p := range parsers {
+			got, err := p.parse(s)
+			if err != nil {
+				continue
+			}
+
+			if again, err := p.parse(p.format(got)); err != nil || again != got {
+				t.Errorf("%s(%q) = %d, but it does not round trip: %d, %v", p.name, s, got, again, err)
+			}
+		}
+	})
+}
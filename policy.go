@@ -0,0 +1,144 @@
+// MIT License
+
+// Copyright (c) 2022 Project-Sparrow
+// Copyright (c) 2023 Kelwing <kelwing@kelnet.org>
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package snowflake
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// Policy decides what a Generator does when the clock reads earlier
+// than the last generated Snowflake.
+type Policy int
+
+const (
+	// PolicyWait waits for the clock to catch up if it is behind by at most
+	// the maximum set with WithMaxBackwardsWait, and returns ErrClockBackwards
+	// otherwise. TryGenerate never waits and always returns ErrClockBackwards.
+	PolicyWait Policy = iota
+
+	// PolicyError returns ErrClockBackwards.
+	PolicyError
+
+	// PolicyPanic panics.
+	PolicyPanic
+)
+
+// DefaultMaxBackwardsWait is how far behind the clock may be
+// for PolicyWait to wait for it, unless overridden with WithMaxBackwardsWait.
+const DefaultMaxBackwardsWait = 10 * time.Millisecond
+
+// String implements fmt.Stringer interface
+func (p Policy) String() string {
+	switch p {
+	case PolicyWait:
+		return "wait"
+	case PolicyError:
+		return "error"
+	case PolicyPanic:
+		return "panic"
+	default:
+		return fmt.Sprintf("Policy(%d)", int(p))
+	}
+}
+
+// MarshalText implements encoding.TextMarshaler interface
+func (p Policy) MarshalText() ([]byte, error) {
+	if p < PolicyWait || p > PolicyPanic {
+		return nil, fmt.Errorf("unknown clock backwards policy %d", int(p))
+	}
+
+	return []byte(p.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler interface
+func (p *Policy) UnmarshalText(text []byte) error {
+	for q := PolicyWait; q <= PolicyPanic; q++ {
+		if string(text) == q.String() {
+			*p = q
+			return nil
+		}
+	}
+
+	return fmt.Errorf("unknown clock backwards policy %q", text)
+}
+
+// WithClockBackwardsPolicy sets what the Generator does when the clock
+// moves backwards. The default is PolicyWait.
+func WithClockBackwardsPolicy(p Policy) Option {
+	return func(g *Generator) error {
+		if err := g.once("clock backwards policy"); err != nil {
+			return err
+		}
+
+		if p < PolicyWait || p > PolicyPanic {
+			return fmt.Errorf("unknown clock backwards policy %d", int(p))
+		}
+
+		g.policy = p
+
+		return nil
+	}
+}
+
+// WithMaxBackwardsWait sets how far behind the clock may be
+// for PolicyWait to wait for it to catch up.
+func WithMaxBackwardsWait(d time.Duration) Option {
+	return func(g *Generator) error {
+		if err := g.once("max backwards wait"); err != nil {
+			return err
+		}
+
+		if d < 0 {
+			return fmt.Errorf("max backwards wait %v is negative", d)
+		}
+
+		g.maxWait = d
+
+		return nil
+	}
+}
+
+// clockBackwards applies the Generator's Policy to a clock reading ts
+// earlier than the last generated timestamp, returning the timestamp
+// to continue with once caught up.
+// The event is recorded in ev. The caller must hold g.mtx.
+func (g *Generator) clockBackwards(ctx context.Context, ts int64, wait bool, ev *events) (int64, error) {
+	behind := time.Duration(g.lastTimestamp-ts) * g.unit
+	if g.onBackwards != nil {
+		ev.backwards = append(ev.backwards, behind)
+	}
+
+	switch g.policy {
+	case PolicyPanic:
+		panic(fmt.Sprintf("snowflake: %v by %v", ErrClockBackwards, behind))
+	case PolicyWait:
+		if wait && behind <= g.maxWait {
+			return g.waitFor(ctx, g.lastTimestamp)
+		}
+	}
+
+	return 0, fmt.Errorf("%w by %v", ErrClockBackwards, behind)
+}
@@ -0,0 +1,131 @@
+// MIT License
+
+// Copyright (c) 2022 Project-Sparrow
+// Copyright (c) 2023 Kelwing <kelwing@kelnet.org>
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package snowflake_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"wumpgo.dev/snowflake"
+)
+
+// backwardsGenerator returns a Generator that has generated a Snowflake
+// and a clock that has then moved backwards by d.
+func backwardsGenerator(t *testing.T, d time.Duration, opts ...snowflake.Option) (*snowflake.Generator, *snowflake.FakeClock) {
+	t.Helper()
+
+	epoch := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	clock := snowflake.NewFakeClock(epoch.Add(time.Hour))
+
+	opts = append(opts, snowflake.WithEpoch(epoch), snowflake.WithWorkerID(1), snowflake.WithClock(clock))
+	g, err := snowflake.New(opts...)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	g.Generate()
+	clock.Set(clock.Now().Add(-d))
+
+	return g, clock
+}
+
+func TestPolicyWait(t *testing.T) {
+	g, clock := backwardsGenerator(t, 5*time.Millisecond)
+
+	if _, err := g.GenerateContext(context.Background()); err != nil {
+		t.Errorf("GenerateContext() = %v, want nil", err)
+	}
+
+	if clock.Sleeps() == 0 {
+		t.Error("GenerateContext did not wait for the clock to catch up")
+	}
+
+	g, _ = backwardsGenerator(t, time.Second)
+	if _, err := g.GenerateContext(context.Background()); !errors.Is(err, snowflake.ErrClockBackwards) {
+		t.Errorf("GenerateContext() = %v, want ErrClockBackwards", err)
+	}
+
+	g, _ = backwardsGenerator(t, 5*time.Millisecond)
+	if _, err := g.TryGenerate(); !errors.Is(err, snowflake.ErrClockBackwards) {
+		t.Errorf("TryGenerate() = %v, want ErrClockBackwards", err)
+	}
+
+	g, _ = backwardsGenerator(t, time.Second, snowflake.WithMaxBackwardsWait(2*time.Second))
+	if _, err := g.GenerateContext(context.Background()); err != nil {
+		t.Errorf("GenerateContext() with larger max wait = %v, want nil", err)
+	}
+
+	g, _ = backwardsGenerator(t, time.Second)
+	defer func() {
+		if recover() == nil {
+			t.Error("Generate did not panic")
+		}
+	}()
+	g.Generate()
+}
+
+func TestPolicyError(t *testing.T) {
+	for _, d := range []time.Duration{time.Millisecond, time.Hour} {
+		g, clock := backwardsGenerator(t, d, snowflake.WithClockBackwardsPolicy(snowflake.PolicyError))
+
+		if _, err := g.GenerateContext(context.Background()); !errors.Is(err, snowflake.ErrClockBackwards) {
+			t.Errorf("GenerateContext() behind by %v = %v, want ErrClockBackwards", d, err)
+		}
+
+		if _, err := g.TryGenerate(); !errors.Is(err, snowflake.ErrClockBackwards) {
+			t.Errorf("TryGenerate() behind by %v = %v, want ErrClockBackwards", d, err)
+		}
+
+		if clock.Sleeps() != 0 {
+			t.Errorf("PolicyError waited for the clock")
+		}
+	}
+}
+
+func TestPolicyPanic(t *testing.T) {
+	for _, d := range []time.Duration{time.Millisecond, time.Hour} {
+		g, _ := backwardsGenerator(t, d, snowflake.WithClockBackwardsPolicy(snowflake.PolicyPanic))
+
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("TryGenerate() behind by %v did not panic", d)
+				}
+			}()
+			g.TryGenerate()
+		}()
+	}
+}
+
+func TestPolicyInvalid(t *testing.T) {
+	if _, err := snowflake.New(snowflake.WithEpoch(time.Now()), snowflake.WithClockBackwardsPolicy(snowflake.Policy(42))); err == nil {
+		t.Error("expected error for unknown policy")
+	}
+
+	if _, err := snowflake.New(snowflake.WithEpoch(time.Now()), snowflake.WithMaxBackwardsWait(-time.Second)); err == nil {
+		t.Error("expected error for negative max wait")
+	}
+}
@@ -0,0 +1,150 @@
+// MIT License
+
+// Copyright (c) 2022 Project-Sparrow
+// Copyright (c) 2023 Kelwing <kelwing@kelnet.org>
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package snowflake
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// Pool pre-generates Snowflakes in the background so bursts of demand
+// can be served from a buffer instead of waiting on the Generator.
+// Snowflakes handed out by a Pool are unique but not strictly increasing,
+// as buffered ones are older than ones generated directly.
+type Pool struct {
+	g      *Generator
+	ids    chan Snowflake
+	space  chan struct{}
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+	once   sync.Once
+
+	// unregister removes Close from the Generator's close functions.
+	unregister func()
+}
+
+// NewPool creates a Pool buffering up to size Snowflakes from g
+// and starts filling it. Closing g also closes the Pool.
+func NewPool(g *Generator, size int) (*Pool, error) {
+	if g == nil {
+		return nil, errors.New("generator is nil")
+	}
+
+	if size <= 0 {
+		return nil, fmt.Errorf("pool size %d is not positive", size)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	p := &Pool{
+		g:      g,
+		ids:    make(chan Snowflake, size),
+		space:  make(chan struct{}, 1),
+		ctx:    ctx,
+		cancel: cancel,
+	}
+
+	unregister, err := g.onClose(p.Close)
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	p.unregister = unregister
+
+	p.wg.Add(1)
+	go p.fill()
+
+	return p, nil
+}
+
+// fill keeps the buffer topped up until the Pool is closed.
+// It is the only sender on p.ids, so sends never block while there is room.
+func (p *Pool) fill() {
+	defer p.wg.Done()
+
+	for {
+		if len(p.ids) < cap(p.ids) {
+			s, err := p.g.GenerateContext(p.ctx)
+			if err == nil {
+				p.ids <- s
+				continue
+			}
+
+			if p.ctx.Err() != nil {
+				return
+			}
+
+			// Back off while the clock is misbehaving.
+			select {
+			case <-p.ctx.Done():
+				return
+			case <-time.After(time.Millisecond):
+			}
+			continue
+		}
+
+		select {
+		case <-p.ctx.Done():
+			return
+		case <-p.space:
+		}
+	}
+}
+
+// Next returns a pre-generated Snowflake, or generates one directly
+// if the buffer is empty. After Close, buffered Snowflakes are still
+// handed out before falling back to direct generation.
+func (p *Pool) Next() Snowflake {
+	select {
+	case s := <-p.ids:
+		select {
+		case p.space <- struct{}{}:
+		default:
+		}
+		return s
+	default:
+		return p.g.Generate()
+	}
+}
+
+// Len returns the number of buffered Snowflakes.
+func (p *Pool) Len() int {
+	return len(p.ids)
+}
+
+// Close stops filling the Pool and waits for the background goroutine to exit,
+// and stops the Generator from closing it again.
+// It is safe to call more than once.
+func (p *Pool) Close() error {
+	p.once.Do(func() {
+		p.cancel()
+		p.wg.Wait()
+		p.unregister()
+	})
+
+	return nil
+}
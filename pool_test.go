@@ -0,0 +1,164 @@
+// MIT License
+
+// Copyright (c) 2022 Project-Sparrow
+// Copyright (c) 2023 Kelwing <kelwing@kelnet.org>
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package snowflake_test
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"wumpgo.dev/snowflake"
+)
+
+// waitFull waits for p to buffer n Snowflakes.
+func waitFull(t *testing.T, p *snowflake.Pool, n int) {
+	t.Helper()
+
+	deadline := time.Now().Add(5 * time.Second)
+	for p.Len() < n {
+		if time.Now().After(deadline) {
+			t.Fatalf("pool buffered %d Snowflakes, want %d", p.Len(), n)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestPoolClose(t *testing.T) {
+	epoch := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	g, err := snowflake.New(snowflake.WithEpoch(epoch), snowflake.WithWorkerID(1), snowflake.WithProcessID(1), snowflake.WithClock(snowflake.NewManualClock(epoch.Add(time.Hour))))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	p, err := snowflake.NewPool(g, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	waitFull(t, p, 10)
+
+	if err := p.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := p.Close(); err != nil {
+		t.Fatalf("second Close() = %v", err)
+	}
+
+	if n := snowflake.CloseFuncs(g); n != 0 {
+		t.Errorf("Generator holds %d close functions after Pool.Close, want 0", n)
+	}
+
+	// Buffered Snowflakes are handed out first, then direct ones continue
+	// the same sequence, so none were lost or duplicated.
+	for i := 0; i < 15; i++ {
+		if s := p.Next(); s.Sequence() != uint16(i) {
+			t.Fatalf("Next() #%d sequence = %d, want %d", i, s.Sequence(), i)
+		}
+	}
+}
+
+func TestPoolConcurrent(t *testing.T) {
+	g, err := snowflake.New(snowflake.WithEpoch(time.Now().Add(-time.Hour)), snowflake.WithWorkerID(1), snowflake.WithProcessID(1))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	p, err := snowflake.NewPool(g, 256)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	const goroutines = 8
+	const n = 5000
+
+	results := make([][]snowflake.Snowflake, goroutines)
+	var wg sync.WaitGroup
+	for i := range results {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			ids := make([]snowflake.Snowflake, n)
+			for j := range ids {
+				if i == 0 && j == n/2 {
+					p.Close()
+				}
+				ids[j] = p.Next()
+			}
+			results[i] = ids
+		}(i)
+	}
+	wg.Wait()
+
+	seen := make(map[snowflake.Snowflake]bool, goroutines*n)
+	for _, ids := range results {
+		for _, s := range ids {
+			if seen[s] {
+				t.Fatalf("duplicate Snowflake %d", s)
+			}
+			seen[s] = true
+		}
+	}
+}
+
+func TestPoolNextDrained(t *testing.T) {
+	epoch := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	g, err := snowflake.New(snowflake.WithEpoch(epoch), snowflake.WithWorkerID(1), snowflake.WithProcessID(1), snowflake.WithClock(snowflake.NewManualClock(epoch.Add(time.Hour))))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	p, err := snowflake.NewPool(g, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer p.Close()
+
+	// Next never waits for the filler, once the buffer is drained
+	// it generates directly.
+	waitFull(t, p, 1)
+	start := time.Now()
+	for i := 0; i < 100; i++ {
+		p.Next()
+	}
+
+	if d := time.Since(start); d > time.Second {
+		t.Errorf("100 Next() calls took %v", d)
+	}
+}
+
+func TestNewPoolInvalid(t *testing.T) {
+	g, err := snowflake.New(snowflake.WithEpoch(time.Now()), snowflake.WithWorkerID(1), snowflake.WithProcessID(1))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := snowflake.NewPool(g, 0); err == nil {
+		t.Error("expected error for zero size")
+	}
+
+	if _, err := snowflake.NewPool(nil, 1); err == nil {
+		t.Error("expected error for nil generator")
+	}
+}
@@ -0,0 +1,90 @@
+// MIT License
+
+// Copyright (c) 2022 Project-Sparrow
+// Copyright (c) 2023 Kelwing <kelwing@kelnet.org>
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package snowflake
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// ErrNoFreeID is returned when every ID an allocator can hand out is in use.
+var ErrNoFreeID = errors.New("no free ID")
+
+// ProcessIDFromPID returns the low bits of the current process ID.
+// Processes whose IDs share those bits get the same process ID;
+// use ProcessIDUnique to rule that out on a host.
+// It panics if bits is not between 1 and 8.
+func ProcessIDFromPID(bits uint8) uint8 {
+	if err := checkIDBits("process", bits); err != nil {
+		panic("snowflake: " + err.Error())
+	}
+
+	return uint8(os.Getpid()) & (1<<bits - 1)
+}
+
+// ProcessIDUnique claims the lowest process ID of the given bit width not
+// claimed by another process sharing dir, through lock files named like
+// process-3.lock holding the claiming process's ID. The claim lasts until
+// release is called or the process exits, even if it crashes: the lock is
+// held by the operating system, so a lock file left behind by a dead process
+// is taken over. It returns an error wrapping ErrNoFreeID if every process
+// ID is claimed. release may be called more than once.
+func ProcessIDUnique(dir string, bits uint8) (id uint8, release func(), err error) {
+	if err := checkIDBits("process", bits); err != nil {
+		return 0, nil, err
+	}
+
+	n, f, err := claimLock(dir, "process", bits)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	return uint8(n), sync.OnceFunc(func() { unlockFile(f) }), nil
+}
+
+// claimLock locks the lowest free lock file named like kind-3.lock in dir,
+// for IDs of the given bit width.
+func claimLock(dir, kind string, bits uint8) (int, *os.File, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return 0, nil, err
+	}
+
+	for n := 0; n < 1<<bits; n++ {
+		f, err := lockFile(filepath.Join(dir, fmt.Sprintf("%s-%d.lock", kind, n)))
+		if errors.Is(err, errLocked) {
+			continue
+		}
+
+		if err != nil {
+			return 0, nil, fmt.Errorf("claiming %s ID %d: %w", kind, n, err)
+		}
+
+		return n, f, nil
+	}
+
+	return 0, nil, fmt.Errorf("%w: all %d %s IDs in %s are claimed", ErrNoFreeID, 1<<bits, kind, dir)
+}
@@ -0,0 +1,197 @@
+// MIT License
+
+// Copyright (c) 2022 Project-Sparrow
+// Copyright (c) 2023 Kelwing <kelwing@kelnet.org>
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package snowflake_test
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"wumpgo.dev/snowflake"
+)
+
+func TestProcessIDFromPID(t *testing.T) {
+	pid := os.Getpid()
+	for _, bits := range []uint8{1, 5, 8} {
+		if got, want := snowflake.ProcessIDFromPID(bits), uint8(pid&(1<<bits-1)); got != want {
+			t.Errorf("ProcessIDFromPID(%d) = %d, want %d", bits, got, want)
+		}
+	}
+
+	if _, _, err := snowflake.ProcessIDUnique(t.TempDir(), 9); err == nil || !strings.Contains(err.Error(), "process ID width 9") {
+		t.Errorf("ProcessIDUnique(9 bits) = %v, want error naming the process ID width", err)
+	}
+
+	defer func() {
+		if r := recover(); r == nil || !strings.Contains(fmt.Sprint(r), "process ID width 9") {
+			t.Errorf("ProcessIDFromPID(9) panicked with %v, want the process ID width", r)
+		}
+	}()
+	snowflake.ProcessIDFromPID(9)
+}
+
+func TestProcessIDUnique(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "locks")
+
+	const goroutines = 12
+
+	type claim struct {
+		id      uint8
+		release func()
+	}
+	claims := make(chan claim, goroutines)
+	errs := make(chan error, goroutines)
+
+	var wg sync.WaitGroup
+	for i := 0; i < goroutines; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			id, release, err := snowflake.ProcessIDUnique(dir, 2)
+			if err != nil {
+				errs <- err
+				return
+			}
+			claims <- claim{id, release}
+		}()
+	}
+	wg.Wait()
+	close(claims)
+	close(errs)
+
+	held := make(map[uint8]func())
+	for c := range claims {
+		if held[c.id] != nil {
+			t.Errorf("process ID %d claimed twice", c.id)
+		}
+		held[c.id] = c.release
+	}
+
+	if len(held) != 4 {
+		t.Fatalf("%d process IDs claimed, want 4", len(held))
+	}
+
+	for err := range errs {
+		if !errors.Is(err, snowflake.ErrNoFreeID) {
+			t.Errorf("ProcessIDUnique() = %v, want ErrNoFreeID", err)
+		}
+	}
+
+	lock := filepath.Join(dir, "process-2.lock")
+	if b, err := os.ReadFile(lock); err != nil || strings.TrimSpace(string(b)) != fmt.Sprint(os.Getpid()) {
+		t.Errorf("lock file holds %q, %v, want the process ID %d", b, err, os.Getpid())
+	}
+
+	held[2]()
+	held[2]()
+	if _, err := os.Stat(lock); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("lock file after release: %v, want it removed", err)
+	}
+
+	id, release, err := snowflake.ProcessIDUnique(dir, 2)
+	if err != nil || id != 2 {
+		t.Fatalf("ProcessIDUnique() after release = %d, %v, want 2", id, err)
+	}
+	release()
+
+	for _, release := range held {
+		release()
+	}
+}
+
+func TestProcessIDUniqueStale(t *testing.T) {
+	dir := t.TempDir()
+
+	// A process that died left its lock file behind.
+	if err := os.WriteFile(filepath.Join(dir, "process-0.lock"), []byte("99999\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	id, release, err := snowflake.ProcessIDUnique(dir, 5)
+	if err != nil || id != 0 {
+		t.Fatalf("ProcessIDUnique() with a stale lock = %d, %v, want 0", id, err)
+	}
+	release()
+}
+
+// TestProcessIDUniqueHelper claims a process ID when run as a subprocess
+// by TestProcessIDUniqueCrash, and holds it until killed.
+func TestProcessIDUniqueHelper(t *testing.T) {
+	dir := os.Getenv("SNOWFLAKE_LOCK_DIR")
+	if dir == "" {
+		t.Skip("only run as a subprocess")
+	}
+
+	id, _, err := snowflake.ProcessIDUnique(dir, 5)
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+
+	fmt.Println(id)
+	select {}
+}
+
+func TestProcessIDUniqueCrash(t *testing.T) {
+	dir := t.TempDir()
+
+	cmd := exec.Command(os.Args[0], "-test.run=^TestProcessIDUniqueHelper$")
+	cmd.Env = append(os.Environ(), "SNOWFLAKE_LOCK_DIR="+dir)
+	out, err := cmd.StdoutPipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := cmd.Start(); err != nil {
+		t.Fatal(err)
+	}
+	defer cmd.Process.Kill()
+
+	line, err := bufio.NewReader(out).ReadString('\n')
+	if err != nil || line != "0\n" {
+		t.Fatalf("subprocess claimed %q, %v, want 0", line, err)
+	}
+
+	id, release, err := snowflake.ProcessIDUnique(dir, 5)
+	if err != nil || id != 1 {
+		t.Fatalf("ProcessIDUnique() while the subprocess runs = %d, %v, want 1", id, err)
+	}
+	release()
+
+	// The subprocess crashes without releasing its claim.
+	cmd.Process.Kill()
+	cmd.Wait()
+
+	id, release, err = snowflake.ProcessIDUnique(dir, 5)
+	if err != nil || id != 0 {
+		t.Fatalf("ProcessIDUnique() after the subprocess died = %d, %v, want 0", id, err)
+	}
+	release()
+}
@@ -0,0 +1,95 @@
+// MIT License
+
+// Copyright (c) 2022 Project-Sparrow
+// Copyright (c) 2023 Kelwing <kelwing@kelnet.org>
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package snowflake
+
+import (
+	"fmt"
+	"math"
+)
+
+// radix writes Snowflakes as numbers in a base between 16 and 256
+// with the digits of its alphabet, most significant first.
+type radix struct {
+	name     string
+	alphabet string
+	digits   [256]byte // value of each character, or 0xFF if invalid
+	maxLen   int       // length of the largest Snowflake
+}
+
+func newRadix(name, alphabet string) *radix {
+	r := &radix{name: name, alphabet: alphabet}
+	for i := range r.digits {
+		r.digits[i] = 0xFF
+	}
+
+	for i, c := range []byte(alphabet) {
+		r.digits[c] = byte(i)
+	}
+	r.maxLen = len(r.encode(math.MaxUint64))
+
+	return r
+}
+
+func (r *radix) encode(v uint64) string {
+	var buf [16]byte
+
+	base := uint64(len(r.alphabet))
+	i := len(buf)
+	for ; ; v /= base {
+		i--
+		buf[i] = r.alphabet[v%base]
+		if v < base {
+			break
+		}
+	}
+
+	return string(buf[i:])
+}
+
+func (r *radix) parse(s string) (Snowflake, error) {
+	if s == "" {
+		return 0, fmt.Errorf("%w: %s Snowflake is empty", ErrSyntax, r.name)
+	}
+
+	if len(s) > r.maxLen {
+		return 0, fmt.Errorf("%w: %s Snowflake of %d bytes, want at most %d", ErrTooLong, r.name, len(s), r.maxLen)
+	}
+
+	base := uint64(len(r.alphabet))
+	var n uint64
+	for i := 0; i < len(s); i++ {
+		d := r.digits[s[i]]
+		if d == 0xFF {
+			return 0, fmt.Errorf("%w: %s Snowflake %q has invalid character %q", ErrSyntax, r.name, s, s[i])
+		}
+
+		if n > (math.MaxUint64-uint64(d))/base {
+			return 0, fmt.Errorf("%w: %s Snowflake %q", ErrOverflow, r.name, s)
+		}
+
+		n = n*base + uint64(d)
+	}
+
+	return Snowflake(n), nil
+}
@@ -0,0 +1,50 @@
+// MIT License
+
+// Copyright (c) 2022 Project-Sparrow
+// Copyright (c) 2023 Kelwing <kelwing@kelnet.org>
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package snowflake
+
+import "math/rand"
+
+// WithRandomSequenceStart makes the Generator start the sequence of each
+// time unit at a random value and wrap around within the sequence bits,
+// so the sequence no longer reveals how many Snowflakes were generated
+// in a time unit. Up to the same number of Snowflakes can be generated per
+// time unit, but they are only ordered between time units, not within one.
+// The start is not cryptographically random.
+func WithRandomSequenceStart() Option {
+	return func(g *Generator) error {
+		if err := g.once("random sequence start"); err != nil {
+			return err
+		}
+
+		g.randomStart = true
+
+		return nil
+	}
+}
+
+// randomSequence returns a random sequence between 0 and max,
+// which must be one less than a power of two.
+func randomSequence(max uint16) uint16 {
+	return uint16(rand.Uint32()) & max
+}
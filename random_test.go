@@ -0,0 +1,90 @@
+// MIT License
+
+// Copyright (c) 2022 Project-Sparrow
+// Copyright (c) 2023 Kelwing <kelwing@kelnet.org>
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package snowflake_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"wumpgo.dev/snowflake"
+)
+
+func TestWithRandomSequenceStart(t *testing.T) {
+	epoch := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	clock := snowflake.NewFakeClock(epoch.Add(time.Hour))
+
+	g, err := snowflake.New(snowflake.WithEpoch(epoch), snowflake.WithClock(clock), snowflake.WithRandomSequenceStart())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	const units = 8
+	ids := g.GenerateN(units * 4096)
+
+	starts := make(map[uint16]bool)
+	for u := 0; u < units; u++ {
+		unit := ids[u*4096 : (u+1)*4096]
+		want := g.Time(unit[0])
+		starts[g.Deconstruct(unit[0]).Sequence] = true
+
+		seen := make(map[uint16]bool, 4096)
+		for _, s := range unit {
+			p := g.Deconstruct(s)
+			if !p.Time.Equal(want) {
+				t.Fatalf("time unit %d has Snowflakes at %v and %v", u, want, p.Time)
+			}
+
+			if seen[p.Sequence] {
+				t.Fatalf("time unit %d repeats sequence %d", u, p.Sequence)
+			}
+			seen[p.Sequence] = true
+		}
+	}
+
+	// The chance of all 8 starts colliding into 2 values is negligible.
+	if len(starts) < 3 {
+		t.Errorf("time units started at %d distinct sequences, want more", len(starts))
+	}
+}
+
+func TestWithRandomSequenceStartExhausted(t *testing.T) {
+	epoch := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	clock := snowflake.NewManualClock(epoch.Add(time.Hour))
+
+	g, err := snowflake.New(snowflake.WithEpoch(epoch), snowflake.WithClock(clock), snowflake.WithRandomSequenceStart())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 0; i < 4096; i++ {
+		if _, err := g.TryGenerate(); err != nil {
+			t.Fatalf("TryGenerate() #%d = %v", i, err)
+		}
+	}
+
+	if _, err := g.TryGenerate(); !errors.Is(err, snowflake.ErrSequenceExhausted) {
+		t.Errorf("TryGenerate() #4096 = %v, want ErrSequenceExhausted", err)
+	}
+}
@@ -0,0 +1,96 @@
+// MIT License
+
+// Copyright (c) 2022 Project-Sparrow
+// Copyright (c) 2023 Kelwing <kelwing@kelnet.org>
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package snowflake
+
+import (
+	"fmt"
+	"time"
+)
+
+// FirstForTime returns the smallest Snowflake with the timestamp of t,
+// with the worker, process and sequence bits all zero.
+// It uses the epoch passed to Init and returns an error
+// if t is before the epoch or overflows the timestamp bits.
+func FirstForTime(t time.Time) (Snowflake, error) {
+	return Compose(t, 0, 0, 0)
+}
+
+// LastForTime returns the largest Snowflake with the timestamp of t,
+// with the worker, process and sequence bits all set.
+// It uses the epoch passed to Init and returns an error
+// if t is before the epoch or overflows the timestamp bits.
+func LastForTime(t time.Time) (Snowflake, error) {
+	return Compose(t, workerMask, processMask, sequenceMask)
+}
+
+// BoundsForRange returns the smallest and largest Snowflakes
+// created between from and to inclusive, suitable for queries like
+// WHERE id >= lo AND id <= hi.
+// A from before the epoch is clamped to the epoch,
+// but an error is returned if from is after to or to is before the epoch.
+func BoundsForRange(from, to time.Time) (lo, hi Snowflake, err error) {
+	if from.After(to) {
+		return 0, 0, fmt.Errorf("range start %v is after range end %v", from, to)
+	}
+
+	if e := currentEpoch(); from.Before(e) {
+		from = e
+	}
+
+	lo, err = FirstForTime(from)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	hi, err = LastForTime(to)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	return lo, hi, nil
+}
+
+// FirstForTime is like the package-level FirstForTime
+// but uses the epoch, Layout and time unit of g.
+func (g *Generator) FirstForTime(t time.Time) (Snowflake, error) {
+	ts, err := g.timestampAt(t)
+	if err != nil {
+		return 0, err
+	}
+
+	return g.layout.compose(uint64(ts), 0, 0, 0), nil
+}
+
+// LastForTime is like the package-level LastForTime
+// but uses the epoch, Layout and time unit of g.
+func (g *Generator) LastForTime(t time.Time) (Snowflake, error) {
+	ts, err := g.timestampAt(t)
+	if err != nil {
+		return 0, err
+	}
+
+	l := g.layout
+
+	return l.compose(uint64(ts), l.MaxWorkerID(), l.MaxProcessID(), l.MaxSequence()), nil
+}
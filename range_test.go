@@ -0,0 +1,140 @@
+// MIT License
+
+// Copyright (c) 2022 Project-Sparrow
+// Copyright (c) 2023 Kelwing <kelwing@kelnet.org>
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package snowflake_test
+
+import (
+	"testing"
+	"time"
+
+	"wumpgo.dev/snowflake"
+)
+
+func TestFirstForTime(t *testing.T) {
+	epoch := time.Now().Add(-time.Hour)
+	snowflake.Init(epoch, 31, 31)
+
+	first, err := snowflake.FirstForTime(time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if s := snowflake.Generate(); first > s {
+		t.Errorf("FirstForTime(now) = %d, want <= %d", first, s)
+	}
+
+	if first.WorkerID() != 0 || first.ProcessID() != 0 || first.Sequence() != 0 {
+		t.Errorf("FirstForTime() = %+v, want zero non-time bits", first.Deconstruct())
+	}
+
+	if _, err := snowflake.FirstForTime(epoch.Add(-time.Millisecond)); err == nil {
+		t.Error("expected error for time before epoch")
+	}
+
+	if _, err := snowflake.FirstForTime(epoch.Add(1 << 42 * time.Millisecond)); err == nil {
+		t.Error("expected error for time overflowing the timestamp bits")
+	}
+}
+
+func TestLastForTime(t *testing.T) {
+	epoch := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	snowflake.Init(epoch, 0, 0)
+
+	last, err := snowflake.LastForTime(epoch)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if last != 1<<22-1 {
+		t.Errorf("LastForTime(epoch) = %d, want %d", last, 1<<22-1)
+	}
+
+	next, _ := snowflake.FirstForTime(epoch.Add(time.Millisecond))
+	if last+1 != next {
+		t.Errorf("LastForTime(epoch)+1 = %d, want FirstForTime(epoch+1ms) = %d", last+1, next)
+	}
+
+	end := epoch.Add((1<<42 - 1) * time.Millisecond)
+	if last, err := snowflake.LastForTime(end); err != nil || last != 1<<64-1 {
+		t.Errorf("LastForTime(end) = %d, %v, want %d", last, err, uint64(1<<64-1))
+	}
+
+	if _, err := snowflake.LastForTime(end.Add(time.Millisecond)); err == nil {
+		t.Error("expected error for time overflowing the timestamp bits")
+	}
+}
+
+func TestBoundsForRange(t *testing.T) {
+	epoch := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	snowflake.Init(epoch, 5, 5)
+
+	from := epoch.Add(time.Hour)
+	to := from.Add(time.Minute)
+
+	lo, hi, err := snowflake.BoundsForRange(from, to)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name   string
+		t      time.Time
+		inside bool
+	}{
+		{"before", from.Add(-time.Millisecond), false},
+		{"start", from, true},
+		{"middle", from.Add(30 * time.Second), true},
+		{"end", to, true},
+		{"after", to.Add(time.Millisecond), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			restore := snowflake.FreezeClock(tt.t)
+			defer restore()
+
+			s := snowflake.Generate()
+			if inside := s >= lo && s <= hi; inside != tt.inside {
+				t.Errorf("%d in [%d, %d] = %v, want %v", s, lo, hi, inside, tt.inside)
+			}
+		})
+	}
+}
+
+func TestBoundsForRangeInvalid(t *testing.T) {
+	epoch := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	snowflake.Init(epoch, 0, 0)
+
+	if _, _, err := snowflake.BoundsForRange(epoch.Add(time.Hour), epoch); err == nil {
+		t.Error("expected error for from after to")
+	}
+
+	if _, _, err := snowflake.BoundsForRange(epoch.Add(-2*time.Hour), epoch.Add(-time.Hour)); err == nil {
+		t.Error("expected error for range entirely before epoch")
+	}
+
+	lo, _, err := snowflake.BoundsForRange(epoch.Add(-time.Hour), epoch.Add(time.Hour))
+	if err != nil || lo != 0 {
+		t.Errorf("BoundsForRange() lo = %d, %v, want clamped to 0", lo, err)
+	}
+}
@@ -0,0 +1,411 @@
+// MIT License
+
+// Copyright (c) 2022 Project-Sparrow
+// Copyright (c) 2023 Kelwing <kelwing@kelnet.org>
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+// Package redisalloc leases Snowflake worker IDs from Redis, so instances
+// of an autoscaled fleet each hold their own without static assignment.
+//
+// A worker ID is held by a key such as snowflake:worker:3 set with NX and
+// a TTL, and renewed in the background until the Allocator is closed.
+package redisalloc
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"wumpgo.dev/snowflake"
+)
+
+var (
+	// ErrNoFreeID is returned by Acquire when every worker ID is leased.
+	ErrNoFreeID = errors.New("no free worker ID")
+
+	// ErrLeaseLost reports that the lease on the worker ID expired or was
+	// taken over, so Snowflakes generated with it may no longer be unique.
+	ErrLeaseLost = errors.New("worker ID lease lost")
+
+	// ErrNotAcquired is returned by Renew when no worker ID is held.
+	ErrNotAcquired = errors.New("no worker ID acquired")
+)
+
+const (
+	// DefaultPrefix is the default prefix of the keys holding worker IDs.
+	DefaultPrefix = "snowflake:worker:"
+
+	// DefaultTTL is the default lifetime of a lease.
+	DefaultTTL = 30 * time.Second
+)
+
+// renewScript extends a lease if it is still held with the token.
+var renewScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("PEXPIRE", KEYS[1], ARGV[2])
+end
+return 0
+`)
+
+// releaseScript deletes a lease if it is still held with the token.
+var releaseScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("DEL", KEYS[1])
+end
+return 0
+`)
+
+// Option configures an Allocator.
+type Option func(*Allocator) error
+
+// WithPrefix sets the prefix of the keys holding worker IDs,
+// DefaultPrefix by default.
+func WithPrefix(prefix string) Option {
+	return func(a *Allocator) error {
+		a.prefix = prefix
+		return nil
+	}
+}
+
+// WithTTL sets the lifetime of a lease, DefaultTTL by default.
+// Leases are renewed every third of it, and given up as lost if they
+// could not be renewed for two thirds of it.
+func WithTTL(ttl time.Duration) Option {
+	return func(a *Allocator) error {
+		if ttl < 3*time.Millisecond {
+			return fmt.Errorf("lease TTL %v is shorter than 3ms", ttl)
+		}
+
+		a.ttl = ttl
+
+		return nil
+	}
+}
+
+// WithBits sets the width of the worker IDs handed out,
+// the 5 bits of snowflake.DefaultLayout by default. Acquire tries the keys
+// one SET NX at a time from the lowest ID up, so a wide, crowded range costs
+// as many round trips.
+func WithBits(bits uint8) Option {
+	return func(a *Allocator) error {
+		if bits == 0 || bits > 8 {
+			return fmt.Errorf("worker ID width %d is not between 1 and 8 bits", bits)
+		}
+
+		a.bits = bits
+
+		return nil
+	}
+}
+
+// WithOnLost makes the Allocator call f when a renewal finds the key expired
+// or holding another instance's token, or when renewals have failed for two
+// thirds of the TTL, such as while Redis is unreachable, leaving a third
+// to stop generating before another instance can take the worker ID over.
+// The Generator of NewGenerator is closed before f is called.
+// f is passed an error wrapping ErrLeaseLost and returns before the Lost
+// channel is closed. It runs on a goroutine of its own, so it may close
+// the Allocator.
+func WithOnLost(f func(error)) Option {
+	return func(a *Allocator) error {
+		a.onLost = f
+		return nil
+	}
+}
+
+var _ snowflake.WorkerIDAllocator = (*Allocator)(nil)
+
+// Allocator leases a worker ID from Redis.
+// It is safe for concurrent use.
+type Allocator struct {
+	client redis.Cmdable
+	prefix string
+	ttl    time.Duration
+	bits   uint8
+	onLost func(error)
+
+	mtx     sync.Mutex
+	token   string
+	id      int
+	renewed time.Time
+	gen     *snowflake.Generator
+	stop    chan struct{}
+	done    chan struct{}
+
+	lostOnce sync.Once
+	lost     chan struct{}
+	err      error // guarded by mtx
+}
+
+// New creates an Allocator leasing worker IDs through client.
+func New(client redis.Cmdable, opts ...Option) (*Allocator, error) {
+	a := &Allocator{
+		client: client,
+		prefix: DefaultPrefix,
+		ttl:    DefaultTTL,
+		bits:   snowflake.DefaultLayout.WorkerBits,
+		id:     -1,
+		lost:   make(chan struct{}),
+	}
+
+	for _, opt := range opts {
+		if err := opt(a); err != nil {
+			return nil, err
+		}
+	}
+
+	return a, nil
+}
+
+// Acquire leases the lowest free worker ID and starts renewing it in the
+// background. It returns ErrNoFreeID if every worker ID is leased.
+// An Allocator cannot acquire again once its lease was lost.
+func (a *Allocator) Acquire(ctx context.Context) (uint8, error) {
+	a.mtx.Lock()
+	defer a.mtx.Unlock()
+
+	if a.err != nil {
+		return 0, a.err
+	}
+
+	if a.id >= 0 {
+		return 0, fmt.Errorf("worker ID %d is already acquired", a.id)
+	}
+
+	token, err := newToken()
+	if err != nil {
+		return 0, err
+	}
+
+	for id := 0; id < 1<<a.bits; id++ {
+		// The TTL runs from some time after this on the server.
+		start := time.Now()
+		ok, err := a.client.SetNX(ctx, a.key(id), token, a.ttl).Result()
+		if err != nil {
+			return 0, fmt.Errorf("leasing worker ID %d: %w", id, err)
+		}
+
+		if ok {
+			a.token, a.id, a.renewed = token, id, start
+			a.stop, a.done = make(chan struct{}), make(chan struct{})
+			go a.renewLoop(a.stop, a.done)
+
+			return uint8(id), nil
+		}
+	}
+
+	return 0, fmt.Errorf("%w among %d worker IDs under %q", ErrNoFreeID, 1<<a.bits, a.prefix)
+}
+
+// NewGenerator acquires a worker ID and creates a Generator using it with
+// opts, which is closed whenever the lease is lost as described for
+// WithOnLost. This is the guard snowflake.WorkerIDAllocator asks for and
+// snowflake.WithAllocator does not provide.
+// Once the lease is lost, Generate panics like after Generator.Close, so an
+// outage of Redis longer than the TTL brings down callers using it; use
+// GenerateContext or TryGenerate, which return snowflake.ErrClosed instead,
+// where that matters.
+// Close the Allocator after the Generator to release the worker ID.
+func (a *Allocator) NewGenerator(ctx context.Context, opts ...snowflake.Option) (*snowflake.Generator, error) {
+	id, err := a.Acquire(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	g, err := snowflake.New(append(slices.Clip(opts), snowflake.WithWorkerID(uint16(id)))...)
+	if err != nil {
+		return nil, errors.Join(err, a.Release())
+	}
+
+	a.mtx.Lock()
+	a.gen = g
+	lost := a.err != nil
+	a.mtx.Unlock()
+
+	if lost {
+		g.Close()
+	}
+
+	return g, nil
+}
+
+// Renew extends the lease on the worker ID. It returns an error wrapping
+// ErrLeaseLost if the lease has expired or is held by someone else.
+// Leases are renewed in the background, so calling Renew is seldom needed.
+func (a *Allocator) Renew(ctx context.Context) error {
+	a.mtx.Lock()
+	id, token := a.id, a.token
+	a.mtx.Unlock()
+
+	if id < 0 {
+		return ErrNotAcquired
+	}
+
+	start := time.Now()
+	ok, err := renewScript.Run(ctx, a.client, []string{a.key(id)}, token, a.ttl.Milliseconds()).Bool()
+	if err != nil {
+		return fmt.Errorf("renewing worker ID %d: %w", id, err)
+	}
+
+	if !ok {
+		err := fmt.Errorf("%w: worker ID %d", ErrLeaseLost, id)
+		a.lose(err)
+		return err
+	}
+
+	a.mtx.Lock()
+	if a.id == id && start.After(a.renewed) {
+		a.renewed = start
+	}
+	a.mtx.Unlock()
+
+	return nil
+}
+
+// Release stops renewing the lease and deletes it, freeing the worker ID
+// for other instances. It does nothing if no worker ID is held.
+func (a *Allocator) Release() error {
+	a.mtx.Lock()
+	id, token, stop, done := a.id, a.token, a.stop, a.done
+	a.id, a.token, a.gen = -1, "", nil
+	a.mtx.Unlock()
+
+	if id < 0 {
+		return nil
+	}
+
+	close(stop)
+	<-done
+
+	ctx, cancel := context.WithTimeout(context.Background(), a.ttl)
+	defer cancel()
+
+	if err := releaseScript.Run(ctx, a.client, []string{a.key(id)}, token).Err(); err != nil {
+		return fmt.Errorf("releasing worker ID %d: %w", id, err)
+	}
+
+	return nil
+}
+
+// Close releases the worker ID. It implements io.Closer.
+func (a *Allocator) Close() error {
+	return a.Release()
+}
+
+// Lost returns a channel that is closed when the lease is lost.
+func (a *Allocator) Lost() <-chan struct{} {
+	return a.lost
+}
+
+// Err returns the error wrapping ErrLeaseLost that closed the Lost channel,
+// or nil if the lease has not been lost.
+func (a *Allocator) Err() error {
+	a.mtx.Lock()
+	defer a.mtx.Unlock()
+	return a.err
+}
+
+// renewLoop renews the lease every third of the TTL until stop is closed
+// or the lease is lost. Failed renewals are retried until two thirds of
+// the TTL have passed since the last successful one was sent, when the
+// lease is given up while it is still certain to be held.
+func (a *Allocator) renewLoop(stop <-chan struct{}, done chan<- struct{}) {
+	defer close(done)
+
+	interval := a.ttl / 3
+	t := time.NewTicker(interval)
+	defer t.Stop()
+
+	var err error
+	for {
+		a.mtx.Lock()
+		deadline := a.renewed.Add(a.ttl - interval)
+		a.mtx.Unlock()
+
+		expiry := time.NewTimer(time.Until(deadline))
+
+		select {
+		case <-stop:
+			expiry.Stop()
+			return
+		case <-a.lost:
+			expiry.Stop()
+			return
+		case <-expiry.C:
+			a.lose(fmt.Errorf("%w: not renewed for %v: %v", ErrLeaseLost, a.ttl-interval, err))
+			return
+		case <-t.C:
+			expiry.Stop()
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), min(interval, time.Until(deadline)))
+		err = a.Renew(ctx)
+		cancel()
+
+		if errors.Is(err, ErrLeaseLost) || errors.Is(err, ErrNotAcquired) {
+			return
+		}
+	}
+}
+
+// lose records err, from Renew or renewLoop, and closes the Generator of
+// NewGenerator before calling onLost and closing lost. Only the first call
+// has any effect. The Generator is closed before lose returns, so it stops
+// before the lease can expire, but onLost is called on another goroutine,
+// as it may release the Allocator, which waits for renewLoop to return.
+func (a *Allocator) lose(err error) {
+	a.lostOnce.Do(func() {
+		a.mtx.Lock()
+		a.err = err
+		g := a.gen
+		a.mtx.Unlock()
+
+		if g != nil {
+			g.Close()
+		}
+
+		go func() {
+			if a.onLost != nil {
+				a.onLost(err)
+			}
+
+			close(a.lost)
+		}()
+	})
+}
+
+func (a *Allocator) key(id int) string {
+	return a.prefix + strconv.Itoa(id)
+}
+
+func newToken() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("generating lease token: %w", err)
+	}
+	return hex.EncodeToString(b), nil
+}
@@ -0,0 +1,356 @@
+// MIT License
+
+// Copyright (c) 2022 Project-Sparrow
+// Copyright (c) 2023 Kelwing <kelwing@kelnet.org>
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package redisalloc_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"wumpgo.dev/snowflake"
+	"wumpgo.dev/snowflake/redisalloc"
+)
+
+func newRedis(t *testing.T) (*miniredis.Miniredis, *redis.Client) {
+	t.Helper()
+
+	mr := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr(), MaxRetries: -1})
+	t.Cleanup(func() { client.Close() })
+
+	return mr, client
+}
+
+func newAllocator(t *testing.T, client redis.Cmdable, opts ...redisalloc.Option) *redisalloc.Allocator {
+	t.Helper()
+
+	a, err := redisalloc.New(client, opts...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { a.Close() })
+
+	return a
+}
+
+func waitLost(t *testing.T, a *redisalloc.Allocator) {
+	t.Helper()
+
+	select {
+	case <-a.Lost():
+	case <-time.After(5 * time.Second):
+		t.Fatal("lease loss was not detected")
+	}
+}
+
+func TestAcquireLowestFree(t *testing.T) {
+	mr, client := newRedis(t)
+	ctx := context.Background()
+
+	allocs := make([]*redisalloc.Allocator, 4)
+	for i := range allocs {
+		allocs[i] = newAllocator(t, client, redisalloc.WithBits(2))
+
+		id, err := allocs[i].Acquire(ctx)
+		if err != nil || int(id) != i {
+			t.Fatalf("Acquire() #%d = %d, %v, want %d", i, id, err, i)
+		}
+	}
+
+	if ttl := mr.TTL("snowflake:worker:0"); ttl != redisalloc.DefaultTTL {
+		t.Errorf("lease TTL = %v, want %v", ttl, redisalloc.DefaultTTL)
+	}
+
+	last := newAllocator(t, client, redisalloc.WithBits(2))
+	if _, err := last.Acquire(ctx); !errors.Is(err, redisalloc.ErrNoFreeID) {
+		t.Fatalf("Acquire() with no free ID = %v, want ErrNoFreeID", err)
+	}
+
+	if err := allocs[1].Release(); err != nil {
+		t.Fatal(err)
+	}
+
+	if mr.Exists("snowflake:worker:1") {
+		t.Error("Release did not delete the lease")
+	}
+
+	if id, err := last.Acquire(ctx); err != nil || id != 1 {
+		t.Errorf("Acquire() after release = %d, %v, want 1", id, err)
+	}
+
+	if _, err := last.Acquire(ctx); err == nil {
+		t.Error("second Acquire succeeded, want error")
+	}
+}
+
+func TestAcquireContention(t *testing.T) {
+	_, client := newRedis(t)
+
+	const goroutines = 16
+
+	ids := make(chan uint8, goroutines)
+	var wg sync.WaitGroup
+	var mtx sync.Mutex
+	var errs []error
+	for i := 0; i < goroutines; i++ {
+		a := newAllocator(t, client, redisalloc.WithBits(3), redisalloc.WithPrefix("contended:"))
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			id, err := a.Acquire(context.Background())
+			if err != nil {
+				mtx.Lock()
+				errs = append(errs, err)
+				mtx.Unlock()
+				return
+			}
+			ids <- id
+		}()
+	}
+	wg.Wait()
+	close(ids)
+
+	seen := make(map[uint8]bool)
+	for id := range ids {
+		if seen[id] {
+			t.Errorf("worker ID %d acquired twice", id)
+		}
+		seen[id] = true
+	}
+
+	if len(seen) != 8 || len(errs) != goroutines-8 {
+		t.Errorf("%d IDs acquired and %d failures, want 8 and %d", len(seen), len(errs), goroutines-8)
+	}
+
+	for _, err := range errs {
+		if !errors.Is(err, redisalloc.ErrNoFreeID) {
+			t.Errorf("Acquire() = %v, want ErrNoFreeID", err)
+		}
+	}
+}
+
+func TestRenew(t *testing.T) {
+	mr, client := newRedis(t)
+	ctx := context.Background()
+
+	a := newAllocator(t, client, redisalloc.WithTTL(time.Minute))
+	if err := a.Renew(ctx); !errors.Is(err, redisalloc.ErrNotAcquired) {
+		t.Errorf("Renew() before Acquire = %v, want ErrNotAcquired", err)
+	}
+
+	if _, err := a.Acquire(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	mr.FastForward(50 * time.Second)
+	if err := a.Renew(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	if ttl := mr.TTL("snowflake:worker:0"); ttl != time.Minute {
+		t.Errorf("lease TTL after Renew = %v, want %v", ttl, time.Minute)
+	}
+}
+
+func TestLeaseExpiry(t *testing.T) {
+	mr, client := newRedis(t)
+	ctx := context.Background()
+
+	var (
+		a          *redisalloc.Allocator
+		lostErr    error
+		lostClosed bool
+	)
+	a = newAllocator(t, client, redisalloc.WithTTL(time.Minute), redisalloc.WithOnLost(func(err error) {
+		lostErr = err
+		select {
+		case <-a.Lost():
+			lostClosed = true
+		default:
+		}
+	}))
+	if _, err := a.Acquire(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	// The lease expires and another instance takes the worker ID over.
+	mr.FastForward(time.Minute)
+	b := newAllocator(t, client)
+	if id, err := b.Acquire(ctx); err != nil || id != 0 {
+		t.Fatalf("Acquire() after expiry = %d, %v, want 0", id, err)
+	}
+
+	if err := a.Renew(ctx); !errors.Is(err, redisalloc.ErrLeaseLost) {
+		t.Fatalf("Renew() after expiry = %v, want ErrLeaseLost", err)
+	}
+
+	waitLost(t, a)
+	if !errors.Is(a.Err(), redisalloc.ErrLeaseLost) || !errors.Is(lostErr, redisalloc.ErrLeaseLost) {
+		t.Errorf("Err() = %v and OnLost got %v, want ErrLeaseLost", a.Err(), lostErr)
+	}
+
+	if lostClosed {
+		t.Error("Lost was closed before OnLost was called")
+	}
+
+	// Releasing must not delete the lease now held by b.
+	if err := a.Release(); err != nil {
+		t.Fatal(err)
+	}
+
+	if !mr.Exists("snowflake:worker:0") {
+		t.Error("Release after loss deleted another instance's lease")
+	}
+
+	if _, err := a.Acquire(ctx); !errors.Is(err, redisalloc.ErrLeaseLost) {
+		t.Errorf("Acquire() after loss = %v, want ErrLeaseLost", err)
+	}
+}
+
+func TestBackgroundRenewal(t *testing.T) {
+	mr, client := newRedis(t)
+
+	a := newAllocator(t, client, redisalloc.WithTTL(60*time.Millisecond))
+	g, err := a.NewGenerator(context.Background(), snowflake.WithEpoch(snowflake.EpochDiscord))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got := g.Config().WorkerID; got != 0 {
+		t.Errorf("Generator worker ID = %d, want 0", got)
+	}
+
+	// Renewals keep pushing the expiry back.
+	for i := 0; i < 3; i++ {
+		time.Sleep(30 * time.Millisecond)
+		mr.FastForward(30 * time.Millisecond)
+		if !mr.Exists("snowflake:worker:0") {
+			t.Fatal("lease expired despite background renewal")
+		}
+	}
+
+	// Losing the lease stops the Generator.
+	mr.Del("snowflake:worker:0")
+	waitLost(t, a)
+
+	if _, err := g.TryGenerate(); !errors.Is(err, snowflake.ErrClosed) {
+		t.Errorf("TryGenerate() after loss = %v, want ErrClosed", err)
+	}
+}
+
+func TestCloseOnLost(t *testing.T) {
+	mr, client := newRedis(t)
+
+	closed := make(chan error, 1)
+	var a *redisalloc.Allocator
+	a = newAllocator(t, client, redisalloc.WithTTL(60*time.Millisecond), redisalloc.WithOnLost(func(error) {
+		closed <- a.Close()
+	}))
+	g, err := a.NewGenerator(context.Background(), snowflake.WithEpoch(snowflake.EpochDiscord))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	mr.FastForward(time.Minute)
+
+	select {
+	case err := <-closed:
+		if err != nil {
+			t.Errorf("Close() from OnLost = %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Close() from OnLost did not return")
+	}
+
+	waitLost(t, a)
+
+	if _, err := g.TryGenerate(); !errors.Is(err, snowflake.ErrClosed) {
+		t.Errorf("TryGenerate() after loss = %v, want ErrClosed", err)
+	}
+}
+
+func TestClosedBeforeExpiry(t *testing.T) {
+	mr, client := newRedis(t)
+
+	const ttl = 300 * time.Millisecond
+
+	a := newAllocator(t, client, redisalloc.WithTTL(ttl))
+	start := time.Now()
+	g, err := a.NewGenerator(context.Background(), snowflake.WithEpoch(snowflake.EpochDiscord))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Renewals fail but the key lives on, as while Redis is partitioned.
+	mr.SetError("LOADING")
+	for {
+		if _, err := g.TryGenerate(); errors.Is(err, snowflake.ErrClosed) {
+			break
+		}
+		if time.Since(start) > 5*time.Second {
+			t.Fatal("Generator still open after lease renewals failed")
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	// The key was set after start, so it expires no earlier than ttl after it.
+	elapsed := time.Since(start)
+	mr.SetError("")
+	mr.FastForward(elapsed)
+	if !mr.Exists("snowflake:worker:0") {
+		t.Errorf("Generator closed %v after acquiring, after the %v lease expired", elapsed, ttl)
+	}
+}
+
+func TestUnreachable(t *testing.T) {
+	mr, client := newRedis(t)
+
+	a := newAllocator(t, client, redisalloc.WithTTL(60*time.Millisecond))
+	if _, err := a.Acquire(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	mr.Close()
+	waitLost(t, a)
+
+	if !errors.Is(a.Err(), redisalloc.ErrLeaseLost) {
+		t.Errorf("Err() = %v, want ErrLeaseLost", a.Err())
+	}
+}
+
+func TestNewInvalid(t *testing.T) {
+	_, client := newRedis(t)
+
+	for _, opt := range []redisalloc.Option{redisalloc.WithBits(0), redisalloc.WithBits(9), redisalloc.WithTTL(time.Millisecond)} {
+		if _, err := redisalloc.New(client, opt); err == nil {
+			t.Error("New succeeded, want error")
+		}
+	}
+}
@@ -0,0 +1,87 @@
+// MIT License
+
+// Copyright (c) 2022 Project-Sparrow
+// Copyright (c) 2023 Kelwing <kelwing@kelnet.org>
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package snowflake
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// ErrNotRegistered is returned by GenerateFor for names
+// no Generator was registered under.
+var ErrNotRegistered = errors.New("no generator registered")
+
+// registry holds the Generators registered by name.
+var registry = struct {
+	mtx        sync.RWMutex
+	generators map[string]*Generator
+}{generators: make(map[string]*Generator)}
+
+// Register makes g available under name, for applications generating
+// Snowflakes for several kinds of entities from separate Generators.
+// An error is returned if name is empty, g is nil or name is already taken.
+func Register(name string, g *Generator) error {
+	if name == "" {
+		return errors.New("generator name is empty")
+	}
+
+	if g == nil {
+		return fmt.Errorf("generator %q is nil", name)
+	}
+
+	registry.mtx.Lock()
+	defer registry.mtx.Unlock()
+
+	if _, ok := registry.generators[name]; ok {
+		return fmt.Errorf("generator %q already registered", name)
+	}
+
+	registry.generators[name] = g
+
+	return nil
+}
+
+// Get returns the Generator registered under name.
+func Get(name string) (*Generator, bool) {
+	registry.mtx.RLock()
+	defer registry.mtx.RUnlock()
+
+	g, ok := registry.generators[name]
+
+	return g, ok
+}
+
+// GenerateFor generates a Snowflake with the Generator registered under name,
+// as Generator.GenerateContext does without a deadline.
+// An error wrapping ErrNotRegistered is returned if there is none.
+func GenerateFor(name string) (Snowflake, error) {
+	g, ok := Get(name)
+	if !ok {
+		return 0, fmt.Errorf("%w under %q", ErrNotRegistered, name)
+	}
+
+	return g.GenerateContext(context.Background())
+}
@@ -0,0 +1,117 @@
+// MIT License
+
+// Copyright (c) 2022 Project-Sparrow
+// Copyright (c) 2023 Kelwing <kelwing@kelnet.org>
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package snowflake_test
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"wumpgo.dev/snowflake"
+)
+
+func TestRegistry(t *testing.T) {
+	defer snowflake.ResetRegistry()
+
+	users, err := snowflake.New(snowflake.WithEpoch(time.Now().Add(-time.Hour)), snowflake.WithWorkerID(1))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := snowflake.Register("test users", users); err != nil {
+		t.Fatalf("Register() = %v", err)
+	}
+
+	if err := snowflake.Register("test users", users); err == nil {
+		t.Error("Register() of a duplicate name succeeded")
+	}
+
+	if err := snowflake.Register("", users); err == nil {
+		t.Error("Register() of an empty name succeeded")
+	}
+
+	if err := snowflake.Register("test nil", nil); err == nil {
+		t.Error("Register() of a nil Generator succeeded")
+	}
+
+	if g, ok := snowflake.Get("test users"); !ok || g != users {
+		t.Errorf("Get() = %p, %v, want %p", g, ok, users)
+	}
+
+	s, err := snowflake.GenerateFor("test users")
+	if err != nil {
+		t.Fatalf("GenerateFor() = %v", err)
+	}
+
+	if users.Deconstruct(s).WorkerID != 1 {
+		t.Errorf("GenerateFor() worker ID = %d, want 1", users.Deconstruct(s).WorkerID)
+	}
+
+	if _, err := snowflake.GenerateFor("test missing"); !errors.Is(err, snowflake.ErrNotRegistered) {
+		t.Errorf("GenerateFor() of an unknown name = %v, want ErrNotRegistered", err)
+	}
+}
+
+func TestRegistryConcurrent(t *testing.T) {
+	defer snowflake.ResetRegistry()
+
+	g, err := snowflake.New(snowflake.WithEpoch(time.Now().Add(-time.Hour)))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	const names = 100
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < names; i++ {
+			if err := snowflake.Register(fmt.Sprintf("test concurrent %d", i), g); err != nil {
+				t.Error(err)
+			}
+		}
+	}()
+
+	for r := 0; r < 4; r++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < names; i++ {
+				if got, ok := snowflake.Get(fmt.Sprintf("test concurrent %d", i)); ok && got != g {
+					t.Errorf("Get() = %p, want %p", got, g)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	for i := 0; i < names; i++ {
+		if _, ok := snowflake.Get(fmt.Sprintf("test concurrent %d", i)); !ok {
+			t.Fatalf("name %d not registered", i)
+		}
+	}
+}
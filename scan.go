@@ -0,0 +1,119 @@
+// MIT License
+
+// Copyright (c) 2022 Project-Sparrow
+// Copyright (c) 2023 Kelwing <kelwing@kelnet.org>
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package snowflake
+
+import (
+	"fmt"
+	"io"
+	"unicode"
+)
+
+// ScanArg returns a fmt.Scanner that stores into s. Snowflake cannot
+// implement fmt.Scanner itself because its Scan method implements
+// sql.Scanner, so pass ScanArg(&id) to fmt.Sscanf and friends instead:
+//
+//	var id snowflake.Snowflake
+//	fmt.Sscanf("id=123 rest", "id=%v rest", snowflake.ScanArg(&id))
+//
+// It accepts the %v, %d and %s verbs and the same input as
+// SnowflakeFromString. With %v and %d it reads a run of ASCII letters,
+// digits and signs, and with %s everything up to the next space.
+func ScanArg(s *Snowflake) fmt.Scanner {
+	return scanArg{s}
+}
+
+// NullScanArg is like ScanArg for a NullSnowflake,
+// which is made invalid by the input "null".
+func NullScanArg(s *NullSnowflake) fmt.Scanner {
+	return nullScanArg{s}
+}
+
+type scanArg struct{ s *Snowflake }
+
+func (a scanArg) Scan(state fmt.ScanState, verb rune) error {
+	tok, err := scanToken(state, verb)
+	if err != nil {
+		return err
+	}
+
+	v, err := parseDecimal("Scan", tok)
+	if err != nil {
+		return err
+	}
+
+	*a.s = v
+	return nil
+}
+
+type nullScanArg struct{ s *NullSnowflake }
+
+func (a nullScanArg) Scan(state fmt.ScanState, verb rune) error {
+	tok, err := scanToken(state, verb)
+	if err != nil {
+		return err
+	}
+
+	if string(tok) == "null" {
+		a.s.Snowflake, a.s.Valid = Snowflake(0), false
+		return nil
+	}
+
+	v, err := parseDecimal("Scan", tok)
+	if err != nil {
+		return err
+	}
+
+	a.s.Snowflake, a.s.Valid = v, true
+	return nil
+}
+
+// scanToken reads the token for verb, after skipping leading spaces.
+func scanToken(state fmt.ScanState, verb rune) ([]byte, error) {
+	var f func(rune) bool
+	switch verb {
+	case 'v', 'd':
+		f = isNumberRune
+	case 's':
+		f = func(r rune) bool { return !unicode.IsSpace(r) }
+	default:
+		return nil, fmt.Errorf("bad verb '%%%c' for Snowflake", verb)
+	}
+
+	tok, err := state.Token(true, f)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(tok) == 0 {
+		return nil, io.ErrUnexpectedEOF
+	}
+
+	return tok, nil
+}
+
+// isNumberRune reports whether r can be part of a number token. Letters are
+// included so that input such as "12ab" is an error rather than 12.
+func isNumberRune(r rune) bool {
+	return r == '+' || r == '-' || r < unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r))
+}
@@ -0,0 +1,128 @@
+// MIT License
+
+// Copyright (c) 2022 Project-Sparrow
+// Copyright (c) 2023 Kelwing <kelwing@kelnet.org>
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package snowflake_test
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+
+	"wumpgo.dev/snowflake"
+)
+
+func TestScanArg(t *testing.T) {
+	for _, tt := range []struct {
+		format, in string
+		want       snowflake.Snowflake
+	}{
+		{"id=%v rest", "id=175928847299117063 rest", 175928847299117063},
+		{"id=%d rest", "id=175928847299117063 rest", 175928847299117063},
+		{"id=%s rest", "id=175928847299117063 rest", 175928847299117063},
+		{"%v", "  42", 42},
+		{"%d", "0", 0},
+		{"%s", "18446744073709551615", 1<<64 - 1},
+		{"%d,%%", "42,%", 42},
+		{"(%v)", "(42)", 42},
+	} {
+		var got snowflake.Snowflake
+		if n, err := fmt.Sscanf(tt.in, tt.format, snowflake.ScanArg(&got)); n != 1 || err != nil || got != tt.want {
+			t.Errorf("Sscanf(%q, %q) = %d, %v, scanned %d, want %d", tt.in, tt.format, n, err, got, tt.want)
+		}
+	}
+
+	var a, b snowflake.Snowflake
+	if n, err := fmt.Sscan("1 2", snowflake.ScanArg(&a), snowflake.ScanArg(&b)); n != 2 || err != nil || a != 1 || b != 2 {
+		t.Errorf("Sscan(\"1 2\") = %d, %v, scanned %d and %d, want 1 and 2", n, err, a, b)
+	}
+
+	if n, err := fmt.Fscanln(strings.NewReader("3\n"), snowflake.ScanArg(&a)); n != 1 || err != nil || a != 3 {
+		t.Errorf("Fscanln(\"3\\n\") = %d, %v, scanned %d, want 3", n, err, a)
+	}
+}
+
+func TestScanArgInvalid(t *testing.T) {
+	for _, tt := range []struct {
+		format, in string
+		err        error
+	}{
+		{"%v", "abc", snowflake.ErrSyntax},
+		{"%d", "12ab", snowflake.ErrSyntax},
+		{"%s", "12,", snowflake.ErrSyntax},
+		{"%d", "+1", snowflake.ErrSyntax},
+		{"%d", "-1", snowflake.ErrNegative},
+		{"%v", "18446744073709551616", snowflake.ErrOverflow},
+		{"%v", "", io.ErrUnexpectedEOF},
+		{"%v", "   ", io.ErrUnexpectedEOF},
+	} {
+		got := snowflake.Snowflake(7)
+		if _, err := fmt.Sscanf(tt.in, tt.format, snowflake.ScanArg(&got)); !errors.Is(err, tt.err) {
+			t.Errorf("Sscanf(%q, %q) = %v, want %v", tt.in, tt.format, err, tt.err)
+		}
+
+		if got != 7 {
+			t.Errorf("failed Sscanf(%q, %q) changed the Snowflake to %d", tt.in, tt.format, got)
+		}
+	}
+
+	var got snowflake.Snowflake
+	if _, err := fmt.Sscanf("42", "%x", snowflake.ScanArg(&got)); err == nil || !strings.Contains(err.Error(), "bad verb") {
+		t.Errorf("Sscanf with %%x = %v, want a bad verb error", err)
+	}
+}
+
+func TestNullScanArg(t *testing.T) {
+	for _, tt := range []struct {
+		format, in string
+		want       snowflake.NullSnowflake
+	}{
+		{"parent=%v", "parent=42", snowflake.NewNullSnowflake(42, true)},
+		{"parent=%d", "parent=0", snowflake.NewNullSnowflake(0, true)},
+		{"parent=%s", "parent=42", snowflake.NewNullSnowflake(42, true)},
+		{"parent=%v", "parent=null", snowflake.NullSnowflake{}},
+		{"parent=%d", "parent=null", snowflake.NullSnowflake{}},
+		{"parent=%s", "parent=null", snowflake.NullSnowflake{}},
+	} {
+		got := snowflake.NewNullSnowflake(7, true)
+		if tt.want.Valid {
+			got = snowflake.NullSnowflake{}
+		}
+
+		if n, err := fmt.Sscanf(tt.in, tt.format, snowflake.NullScanArg(&got)); n != 1 || err != nil || got != tt.want {
+			t.Errorf("Sscanf(%q, %q) = %d, %v, scanned %+v, want %+v", tt.in, tt.format, n, err, got, tt.want)
+		}
+	}
+
+	for _, in := range []string{"NULL", "nil", "nullx", "-1", ""} {
+		got := snowflake.NewNullSnowflake(7, true)
+		if _, err := fmt.Sscanf(in, "%v", snowflake.NullScanArg(&got)); err == nil {
+			t.Errorf("Sscanf(%q) scanned %+v, want error", in, got)
+		}
+
+		if want := snowflake.NewNullSnowflake(7, true); got != want {
+			t.Errorf("failed Sscanf(%q) changed the NullSnowflake to %+v", in, got)
+		}
+	}
+}
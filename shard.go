@@ -0,0 +1,119 @@
+// MIT License
+
+// Copyright (c) 2022 Project-Sparrow
+// Copyright (c) 2023 Kelwing <kelwing@kelnet.org>
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package snowflake
+
+import (
+	"fmt"
+	"math/bits"
+)
+
+// WithShards splits the sequence of the Generator between n shards, each with
+// its own lock, so goroutines generating concurrently rarely contend.
+// n must be a power of two smaller than the number of sequence values;
+// the top log2(n) bits of the sequence identify the shard, so Snowflakes stay
+// unique but each shard runs out of sequence numbers n times sooner.
+// Snowflakes from different shards are not ordered within a time unit,
+// and GenerateN and AppendN draw each batch from a single shard.
+// GenerateAt is not sharded.
+func WithShards(n int) Option {
+	return func(g *Generator) error {
+		if err := g.once("shards"); err != nil {
+			return err
+		}
+
+		if n < 1 || n&(n-1) != 0 {
+			return fmt.Errorf("shard count %d is not a power of two", n)
+		}
+
+		g.shardCount = n
+
+		return nil
+	}
+}
+
+// shard creates the shards requested with WithShards
+// once the rest of g is configured.
+func (g *Generator) shard() error {
+	if g.shardCount <= 1 {
+		return nil
+	}
+
+	shardBits := uint8(bits.TrailingZeros(uint(g.shardCount)))
+	if shardBits >= g.layout.SequenceBits {
+		return fmt.Errorf("shard count %d leaves no sequence bits of %d", g.shardCount, g.layout.SequenceBits)
+	}
+
+	localBits := g.layout.SequenceBits - shardBits
+
+	g.shards = make([]*Generator, g.shardCount)
+	for i := range g.shards {
+		g.shards[i] = &Generator{
+			epoch:         g.epoch,
+			layout:        g.layout,
+			unit:          g.unit,
+			signed:        g.signed,
+			workerID:      g.workerID,
+			processID:     g.processID,
+			clock:         g.clock,
+			policy:        g.policy,
+			maxWait:       g.maxWait,
+			anchorElapsed: g.anchorElapsed,
+			anchorMono:    g.anchorMono,
+			lastTimestamp: -1,
+			seqBase:       uint16(i) << localBits,
+			seqMax:        1<<localBits - 1,
+			randomStart:   g.randomStart,
+			maxBorrow:     g.maxBorrow,
+			coarse:        g.coarse,
+			borrowTop:     -1,
+			reservedTop:   -1,
+			onBackwards:   g.onBackwards,
+			onExhausted:   g.onExhausted,
+		}
+	}
+
+	return nil
+}
+
+// lock locks and returns the Generator to generate with: g itself,
+// or with WithShards the first free shard starting from a rotating index.
+func (g *Generator) lock() *Generator {
+	if g.shards == nil {
+		g.mtx.Lock()
+		return g
+	}
+
+	n := uint32(len(g.shards))
+	start := g.nextShard.Add(1)
+	for i := uint32(0); i < n; i++ {
+		if sh := g.shards[(start+i)%n]; sh.mtx.TryLock() {
+			return sh
+		}
+	}
+
+	sh := g.shards[start%n]
+	sh.mtx.Lock()
+
+	return sh
+}
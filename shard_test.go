@@ -0,0 +1,146 @@
+// MIT License
+
+// Copyright (c) 2022 Project-Sparrow
+// Copyright (c) 2023 Kelwing <kelwing@kelnet.org>
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package snowflake_test
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"wumpgo.dev/snowflake"
+)
+
+func TestWithShardsInvalid(t *testing.T) {
+	for _, n := range []int{0, -1, 3, 4096} {
+		if _, err := snowflake.New(snowflake.WithShards(n)); err == nil {
+			t.Errorf("New(WithShards(%d)) succeeded, want error", n)
+		}
+	}
+
+	if _, err := snowflake.New(snowflake.WithShards(1)); err != nil {
+		t.Errorf("New(WithShards(1)) = %v", err)
+	}
+}
+
+func TestWithShardsUnique(t *testing.T) {
+	epoch := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	clock := snowflake.NewFakeClock(epoch.Add(time.Hour))
+
+	g, err := snowflake.New(snowflake.WithEpoch(epoch), snowflake.WithShards(8), snowflake.WithClock(clock))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	const goroutines = 32
+	const n = 5000
+
+	results := make([][]snowflake.Snowflake, goroutines)
+	var wg sync.WaitGroup
+	for i := range results {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			ids := make([]snowflake.Snowflake, 0, n)
+			for len(ids) < n {
+				if i%2 == 0 {
+					ids = append(ids, g.Generate())
+				} else {
+					ids = g.AppendN(ids, 10)
+				}
+			}
+			results[i] = ids
+		}(i)
+	}
+	wg.Wait()
+
+	seen := make(map[snowflake.Snowflake]bool, goroutines*n)
+	for _, ids := range results {
+		for _, s := range ids {
+			if seen[s] {
+				t.Fatalf("duplicate Snowflake %d", s)
+			}
+			seen[s] = true
+		}
+	}
+
+	if st := g.Stats(); st.Generated != goroutines*n {
+		t.Errorf("Stats().Generated = %d, want %d", st.Generated, goroutines*n)
+	}
+}
+
+func TestWithShardsSequence(t *testing.T) {
+	epoch := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	clock := snowflake.NewFakeClock(epoch.Add(time.Hour))
+
+	g, err := snowflake.New(snowflake.WithEpoch(epoch), snowflake.WithShards(4), snowflake.WithClock(clock))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Each shard holds 1024 sequence numbers, so a batch from one shard
+	// moves on to the next millisecond after 1024 Snowflakes.
+	ids := g.GenerateN(1025)
+	first, last := g.Deconstruct(ids[0]), g.Deconstruct(ids[1024])
+
+	if first.Sequence&1023 != 0 || last.Sequence != first.Sequence {
+		t.Errorf("sequences = %d, %d, want the same shard base", first.Sequence, last.Sequence)
+	}
+
+	if !last.Time.Equal(first.Time.Add(time.Millisecond)) {
+		t.Errorf("1025th Snowflake time = %v, want %v", last.Time, first.Time.Add(time.Millisecond))
+	}
+}
+
+// tickingClock advances by a microsecond on every reading without a lock,
+// so benchmarks measure the Generator rather than the clock.
+type tickingClock struct {
+	start time.Time
+	ticks atomic.Int64
+}
+
+func (c *tickingClock) Now() time.Time {
+	return c.start.Add(time.Duration(c.ticks.Add(1)) * time.Microsecond)
+}
+
+func benchShards(b *testing.B, shards int) {
+	epoch := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	clock := &tickingClock{start: epoch.Add(time.Hour)}
+
+	g, err := snowflake.New(snowflake.WithEpoch(epoch), snowflake.WithShards(shards), snowflake.WithClock(clock))
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			g.Generate()
+		}
+	})
+}
+
+// Run with -cpu 32 to compare under GOMAXPROCS=32.
+func BenchmarkGenerateUnsharded(b *testing.B) { benchShards(b, 1) }
+func BenchmarkGenerateShards8(b *testing.B)   { benchShards(b, 8) }
+func BenchmarkGenerateShards32(b *testing.B)  { benchShards(b, 32) }
@@ -0,0 +1,78 @@
+// MIT License
+
+// Copyright (c) 2022 Project-Sparrow
+// Copyright (c) 2023 Kelwing <kelwing@kelnet.org>
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package snowflake
+
+import (
+	"errors"
+	"fmt"
+	"math"
+)
+
+// ErrSignBit is returned by SnowflakeFromStringSigned and Validate for
+// Snowflakes that do not fit in a signed 64-bit integer.
+var ErrSignBit = errors.New("snowflake has the sign bit set")
+
+// WithSigned63Bit makes the Generator leave the most significant bit of every
+// Snowflake clear, so Snowflakes stay positive and ordered when stored as
+// signed 64-bit integers, such as a Postgres BIGINT or a Java long.
+// Generation fails with ErrTimestampOverflow once the timestamp would reach
+// the sign bit, which for DefaultLayout in milliseconds is about 69 years
+// after the epoch instead of 139.
+// It has no effect on layouts of 63 bits, which never set the sign bit.
+func WithSigned63Bit() Option {
+	return func(g *Generator) error {
+		if err := g.once("signed 63-bit mode"); err != nil {
+			return err
+		}
+
+		g.signed = true
+
+		return nil
+	}
+}
+
+// maxTimestamp returns the largest timestamp g may generate.
+func (g *Generator) maxTimestamp() uint64 {
+	if g.signed {
+		return min(g.layout.MaxTimestamp(), math.MaxInt64>>g.layout.timestampShift())
+	}
+
+	return g.layout.MaxTimestamp()
+}
+
+// SnowflakeFromStringSigned is like SnowflakeFromString but returns an error
+// wrapping ErrSignBit if the Snowflake exceeds math.MaxInt64,
+// for systems that expect Snowflakes from a Generator using WithSigned63Bit.
+func SnowflakeFromStringSigned(s string) (Snowflake, error) {
+	i, err := SnowflakeFromString(s)
+	if err != nil {
+		return 0, err
+	}
+
+	if i > math.MaxInt64 {
+		return 0, fmt.Errorf("%w: %d exceeds %d", ErrSignBit, i, int64(math.MaxInt64))
+	}
+
+	return i, nil
+}
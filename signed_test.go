@@ -0,0 +1,96 @@
+// MIT License
+
+// Copyright (c) 2022 Project-Sparrow
+// Copyright (c) 2023 Kelwing <kelwing@kelnet.org>
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package snowflake_test
+
+import (
+	"errors"
+	"math"
+	"strconv"
+	"testing"
+	"time"
+
+	"wumpgo.dev/snowflake"
+)
+
+func TestWithSigned63Bit(t *testing.T) {
+	epoch := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	lastUnit := epoch.Add((1<<41 - 1) * time.Millisecond)
+	clock := snowflake.NewFakeClock(lastUnit)
+
+	g, err := snowflake.New(snowflake.WithEpoch(epoch), snowflake.WithWorkerID(31), snowflake.WithProcessID(31), snowflake.WithSigned63Bit(), snowflake.WithClock(clock))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var last snowflake.Snowflake
+	for i := 0; i < 4096; i++ {
+		s, err := g.TryGenerate()
+		if err != nil {
+			t.Fatalf("TryGenerate() #%d = %v", i, err)
+		}
+		last = s
+	}
+
+	if last != math.MaxInt64 {
+		t.Errorf("largest Snowflake = %d, want %d", last, int64(math.MaxInt64))
+	}
+
+	clock.Advance(time.Millisecond)
+
+	if _, err := g.TryGenerate(); !errors.Is(err, snowflake.ErrTimestampOverflow) {
+		t.Errorf("TryGenerate() past the sign bit = %v, want ErrTimestampOverflow", err)
+	}
+
+	if _, err := g.GenerateAt(lastUnit.Add(time.Millisecond)); !errors.Is(err, snowflake.ErrTimestampOverflow) {
+		t.Errorf("GenerateAt() past the sign bit = %v, want ErrTimestampOverflow", err)
+	}
+}
+
+func TestWithoutSigned63Bit(t *testing.T) {
+	epoch := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	clock := snowflake.NewFakeClock(epoch.Add(1 << 41 * time.Millisecond))
+
+	g, err := snowflake.New(snowflake.WithEpoch(epoch), snowflake.WithClock(clock))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if s := g.Generate(); int64(s) >= 0 {
+		t.Errorf("Generate() = %d, want the sign bit set", s)
+	}
+}
+
+func TestSnowflakeFromStringSigned(t *testing.T) {
+	if s, err := snowflake.SnowflakeFromStringSigned(strconv.FormatInt(math.MaxInt64, 10)); err != nil || s != math.MaxInt64 {
+		t.Errorf("SnowflakeFromStringSigned(MaxInt64) = %d, %v", s, err)
+	}
+
+	if _, err := snowflake.SnowflakeFromStringSigned("9223372036854775808"); !errors.Is(err, snowflake.ErrSignBit) {
+		t.Errorf("SnowflakeFromStringSigned(MaxInt64+1) = %v, want ErrSignBit", err)
+	}
+
+	if _, err := snowflake.SnowflakeFromStringSigned("-1"); err == nil {
+		t.Error("SnowflakeFromStringSigned(-1) succeeded, want error")
+	}
+}
@@ -24,32 +24,129 @@
 // Package snowflake provides a simple snowflake ID generator
 // along with interface implementations to make it easy to use
 // with database/sql and encoding/json.
+//
+// The parsing functions and methods are safe to use on untrusted input:
+// they never panic on arbitrary strings or []byte, run in time linear in
+// the input, and reject input longer than their format needs for any
+// uint64, such as 20 decimal or 18 hex bytes with "0x", with an error
+// wrapping ErrTooLong before looking at its contents.
 package snowflake
 
 import (
+	"bytes"
+	"context"
 	"database/sql/driver"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"strconv"
-	"sync"
+	"strings"
+	"sync/atomic"
 	"time"
 )
 
-var (
-	workerID  int
-	processID int
-	epoch     time.Time
-	increment int
-	mtx       sync.Mutex
+// Bit layout of a Snowflake, from most to least significant:
+// 42 bits of milliseconds since the epoch, 5 bits of worker ID,
+// 5 bits of process ID and 12 bits of sequence.
+const (
+	timestampBits = 42
+	workerBits    = 5
+	processBits   = 5
+	sequenceBits  = 12
+
+	processShift   = sequenceBits
+	workerShift    = processShift + processBits
+	timestampShift = workerShift + workerBits
+
+	timestampMask = 1<<timestampBits - 1
+	workerMask    = 1<<workerBits - 1
+	processMask   = 1<<processBits - 1
+	sequenceMask  = 1<<sequenceBits - 1
 )
 
+// maxDecimalLen is the length of the largest Snowflake in decimal.
+const maxDecimalLen = 20
+
+// ErrNoDefault is returned by the package-level generation functions
+// when neither Init nor SetDefault has been called.
+var ErrNoDefault = errors.New("no default generator, call Init or SetDefault first")
+
+// ErrNonIntegerJSON is returned by UnmarshalJSON for a JSON number with a
+// fraction or exponent, such as 1.069557246566533e18, which has usually
+// lost precision by passing through a float64.
+var ErrNonIntegerJSON = errors.New("snowflake JSON number is not an integer")
+
+// defaultGenerator backs the package-level Init and Generate functions.
+// It is nil until Init or SetDefault is called.
+var defaultGenerator atomic.Pointer[Generator]
+
+// Default returns the Generator used by the package-level functions,
+// or nil if neither Init nor SetDefault has been called.
+func Default() *Generator {
+	return defaultGenerator.Load()
+}
+
+// SetDefault makes g the Generator used by the package-level functions,
+// so library code calling Generate picks up its options.
+// It is safe to call concurrently with Generate.
+// Snowflake methods such as Time decode using the epoch of g but always
+// assume DefaultLayout in milliseconds; use g.Deconstruct for other layouts.
+// SetDefault panics if g is nil.
+func SetDefault(g *Generator) {
+	if g == nil {
+		panic("snowflake: SetDefault called with a nil Generator")
+	}
+
+	defaultGenerator.Store(g)
+}
+
+// mustDefault returns the default Generator, panicking if there is none.
+func mustDefault() *Generator {
+	g := defaultGenerator.Load()
+	if g == nil {
+		panic("snowflake: " + ErrNoDefault.Error())
+	}
+
+	return g
+}
+
 // Init initializes the Snowflake generator.
-// This MUST be called before any calls to Generate.
+// This MUST be called, or SetDefault used, before any calls to Generate.
+// Init panics if the arguments are invalid, see InitChecked.
 func Init(e time.Time, w, p int) {
-	epoch = e
-	workerID = w
-	processID = p
-	increment = 0
+	if err := InitChecked(e, w, p); err != nil {
+		panic("snowflake: " + err.Error())
+	}
+}
+
+// InitChecked is like Init but returns an error instead of panicking
+// when the worker or process ID do not fit in their 5 bits,
+// or the epoch is the zero time or in the future.
+func InitChecked(e time.Time, w, p int) error {
+	if e.IsZero() {
+		return errors.New("epoch is the zero time")
+	}
+
+	if e.After(time.Now()) {
+		return fmt.Errorf("epoch %v is in the future", e)
+	}
+
+	if w < 0 || w > workerMask {
+		return fmt.Errorf("worker ID %d out of range [0, %d]", w, workerMask)
+	}
+
+	if p < 0 || p > processMask {
+		return fmt.Errorf("process ID %d out of range [0, %d]", p, processMask)
+	}
+
+	g, err := New(WithEpoch(e), WithWorkerID(uint16(w)), WithProcessID(uint16(p)))
+	if err != nil {
+		return err
+	}
+
+	SetDefault(g)
+
+	return nil
 }
 
 // Snowflake represents a single Snowflake ID.
@@ -57,64 +154,183 @@ type Snowflake uint64
 
 // Generate generates a new Snowflake.
 // This function is thread-safe.
+// It panics if neither Init nor SetDefault has been called.
 func Generate() Snowflake {
-	mtx.Lock()
-	defer mtx.Unlock()
-	s := Snowflake(0)
+	return mustDefault().Generate()
+}
+
+// GenerateN generates n Snowflakes at once, see Generator.GenerateN.
+func GenerateN(n int) []Snowflake {
+	return mustDefault().GenerateN(n)
+}
 
-	timeComp := time.Since(epoch).Milliseconds()
-	s |= Snowflake(timeComp << 22)
-	s |= Snowflake(workerID << 17)
-	s |= Snowflake(processID << 12)
-	s |= Snowflake(increment)
+// AppendN appends n Snowflakes to dst, see Generator.AppendN.
+func AppendN(dst []Snowflake, n int) []Snowflake {
+	return mustDefault().AppendN(dst, n)
+}
 
-	increment++
+// GenerateContext is like Generate but respects ctx while waiting,
+// see Generator.GenerateContext.
+// It returns ErrNoDefault if neither Init nor SetDefault has been called.
+func GenerateContext(ctx context.Context) (Snowflake, error) {
+	g := defaultGenerator.Load()
+	if g == nil {
+		return 0, ErrNoDefault
+	}
 
-	return s
+	return g.GenerateContext(ctx)
 }
 
-// SnowflakeFromString attempts to parse a Snowflake from a string.
-func SnowflakeFromString(s string) (Snowflake, error) {
-	i, err := strconv.ParseUint(s, 10, 64)
-	if err != nil {
-		return 0, err
+// TryGenerate is like Generate but returns an error instead of waiting,
+// see Generator.TryGenerate.
+// It returns ErrNoDefault if neither Init nor SetDefault has been called.
+func TryGenerate() (Snowflake, error) {
+	g := defaultGenerator.Load()
+	if g == nil {
+		return 0, ErrNoDefault
 	}
 
-	return Snowflake(i), nil
+	return g.TryGenerate()
+}
+
+// SnowflakeFromString attempts to parse a Snowflake from a string.
+// It accepts decimal digits with leading zeros, such as "0000123", up to
+// 20 bytes in all; use ParseStrict to accept only the form returned by String.
+// Errors are a *ParseError wrapping ErrSyntax, ErrNegative, ErrOverflow
+// or ErrTooLong.
+func SnowflakeFromString(s string) (Snowflake, error) {
+	return parseDecimal("SnowflakeFromString", s)
 }
 
 // MarshalJSON implements json.Marshaler interface
 func (s Snowflake) MarshalJSON() ([]byte, error) {
 	// Needs to be a string or later snowflakes will be truncated
-	return json.Marshal(s.String())
+	b := make([]byte, 0, maxDecimalLen+2)
+	b = append(b, '"')
+	b = s.AppendString(b)
+	return append(b, '"'), nil
 }
 
-// MarshalJSON implements json.Unmarshaler interface
-func (s *Snowflake) UnmarshalJSON(bytes []byte) error {
-	var snowflake string
-	err := json.Unmarshal(bytes, &snowflake)
-	if err != nil {
-		return err
+// UnmarshalJSON implements json.Unmarshaler interface.
+// It accepts a string of decimal digits, as written by MarshalJSON, or a
+// bare JSON number, whose digits are parsed without going through a float64.
+// A JSON null leaves the Snowflake zero; use NullSnowflake to tell it apart.
+//
+// A number with a fraction or exponent is an error wrapping
+// ErrNonIntegerJSON. Other errors, including for an empty string,
+// are a *ParseError wrapping ErrSyntax, ErrNegative or ErrOverflow.
+func (s *Snowflake) UnmarshalJSON(data []byte) error {
+	if len(data) > maxDecimalLen+2 {
+		return fmt.Errorf("%w: JSON snowflake of %d bytes", ErrTooLong, len(data))
 	}
 
-	if snowflake == "" || snowflake == "null" {
+	if bytes.Equal(data, nullBytes) {
 		*s = 0
 		return nil
 	}
 
-	snowInt, err := strconv.ParseInt(snowflake, 10, 64)
+	if isNonIntegerJSON(data) {
+		return fmt.Errorf("%w: %s", ErrNonIntegerJSON, data)
+	}
+
+	digits := data
+	switch {
+	case isJSONString(data):
+		digits = data[1 : len(data)-1]
+		if bytes.IndexByte(digits, '\\') >= 0 {
+			var str string
+			if err := json.Unmarshal(data, &str); err != nil {
+				return err
+			}
+			digits = []byte(str)
+		}
+	case !isJSONNumber(data):
+		return &ParseError{Func: "UnmarshalJSON", Input: string(data), Err: ErrSyntax}
+	}
+
+	snowflake, err := parseDecimal("UnmarshalJSON", digits)
 	if err != nil {
 		return err
 	}
 
-	*s = Snowflake(snowInt)
+	*s = snowflake
 
 	return nil
 }
 
+// isJSONString reports whether data is quoted like a JSON string.
+func isJSONString(data []byte) bool {
+	return len(data) >= 2 && data[0] == '"' && data[len(data)-1] == '"'
+}
+
+// isJSONNumber reports whether data starts like a JSON number.
+func isJSONNumber(data []byte) bool {
+	return len(data) > 0 && (data[0] == '-' || '0' <= data[0] && data[0] <= '9')
+}
+
+// isNonIntegerJSON reports whether data is a JSON number with a fraction
+// or exponent.
+func isNonIntegerJSON(data []byte) bool {
+	return isJSONNumber(data) && strings.ContainsAny(string(data), ".eE")
+}
+
 // String implements fmt.Stringer interface
 func (s Snowflake) String() string {
-	return strconv.FormatUint(uint64(s), 10)
+	var buf [maxDecimalLen]byte
+	return string(s.AppendString(buf[:0]))
+}
+
+// SortableString returns the decimal form of the Snowflake left-padded with
+// zeros to 20 digits, so that SortableStrings sort in the same order as the
+// Snowflakes they encode. ParseSortable parses it.
+func (s Snowflake) SortableString() string {
+	var buf [maxDecimalLen]byte
+	n := len(s.AppendString(buf[:0]))
+	copy(buf[maxDecimalLen-n:], buf[:n])
+	for i := 0; i < maxDecimalLen-n; i++ {
+		buf[i] = '0'
+	}
+
+	return string(buf[:])
+}
+
+// AppendString appends the decimal form of the Snowflake, as returned by
+// String, to dst and returns the extended buffer.
+func (s Snowflake) AppendString(dst []byte) []byte {
+	return strconv.AppendUint(dst, uint64(s), 10)
+}
+
+// AppendText implements encoding.TextAppender interface
+// by appending the decimal form of the Snowflake. It never fails.
+func (s Snowflake) AppendText(dst []byte) ([]byte, error) {
+	return s.AppendString(dst), nil
+}
+
+// MarshalText implements encoding.TextMarshaler interface
+// using the decimal form returned by String.
+func (s Snowflake) MarshalText() ([]byte, error) {
+	return s.AppendString(make([]byte, 0, maxDecimalLen)), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler interface.
+// It accepts the same input as SnowflakeFromString.
+func (s *Snowflake) UnmarshalText(text []byte) error {
+	v, err := parseDecimal("UnmarshalText", text)
+	if err != nil {
+		return err
+	}
+
+	*s = v
+	return nil
+}
+
+// DebugString returns a single line breakdown of the Snowflake's components
+// as key=value pairs, with the time in UTC relative to the epoch passed to Init,
+// e.g. "id=175928847299117063 time=2016-04-30T11:18:25.796Z worker=1 process=0 seq=7".
+func (s Snowflake) DebugString() string {
+	p := s.Deconstruct()
+	return fmt.Sprintf("id=%d time=%s worker=%d process=%d seq=%d",
+		uint64(s), p.Time.UTC().Format(time.RFC3339Nano), p.WorkerID, p.ProcessID, p.Sequence)
 }
 
 // Value implements driver.Valuer interface
@@ -122,32 +338,252 @@ func (s Snowflake) Value() (driver.Value, error) {
 	return int64(s), nil
 }
 
-// Scan implements sql.Scanner interface
+// Scan implements sql.Scanner interface.
+// It accepts an int64 and the values accepted by ParseAny.
 func (s *Snowflake) Scan(value interface{}) error {
 	if value == nil {
 		*s = Snowflake(0)
 		return nil
 	}
 
-	switch v := value.(type) {
-	case int64:
+	// Value stores Snowflakes above math.MaxInt64 as negative int64s.
+	if v, ok := value.(int64); ok {
 		*s = Snowflake(v)
-	case string:
-		iv, err := SnowflakeFromString(v)
-		if err != nil {
-			return err
-		}
+		return nil
+	}
 
-		*s = iv
-	default:
-		return errors.New("not a valid snowflake type")
+	v, err := ParseAny(value)
+	if err != nil {
+		return err
 	}
+
+	*s = v
 	return nil
 }
 
+// currentEpoch returns the epoch passed to Init,
+// or the Unix epoch if Init has not been called.
+func currentEpoch() time.Time {
+	if g := defaultGenerator.Load(); g != nil {
+		return g.epoch
+	}
+
+	return EpochUnix
+}
+
+// currentTime returns the time according to the clock
+// of the package-level generator, or the system clock if there is none.
+func currentTime() time.Time {
+	if g := defaultGenerator.Load(); g != nil {
+		return g.clock.Now()
+	}
+
+	return time.Now()
+}
+
+// Compose builds a Snowflake from explicit components,
+// using the epoch passed to Init for the time component.
+// An error is returned if any component does not fit in its bits
+// rather than silently truncating it.
+func Compose(t time.Time, worker, process uint8, seq uint16) (Snowflake, error) {
+	e := currentEpoch()
+	if t.Before(e) {
+		return 0, fmt.Errorf("time %v is before the epoch %v", t, e)
+	}
+
+	ms := t.Sub(e).Milliseconds()
+	if ms > timestampMask {
+		return 0, fmt.Errorf("%w: time %v", ErrTimestampOverflow, t)
+	}
+
+	if worker > workerMask {
+		return 0, fmt.Errorf("worker ID %d exceeds maximum %d", worker, workerMask)
+	}
+
+	if process > processMask {
+		return 0, fmt.Errorf("process ID %d exceeds maximum %d", process, processMask)
+	}
+
+	if seq > sequenceMask {
+		return 0, fmt.Errorf("sequence %d exceeds maximum %d", seq, sequenceMask)
+	}
+
+	s := Snowflake(ms) << timestampShift
+	s |= Snowflake(worker) << workerShift
+	s |= Snowflake(process) << processShift
+	s |= Snowflake(seq)
+
+	return s, nil
+}
+
+// Time returns the time component of the Snowflake as a time.Time,
+// relative to the epoch passed to Init.
+// If Init has not been called, the Unix epoch is assumed.
+func (s Snowflake) Time() time.Time {
+	return s.TimeWithEpoch(currentEpoch())
+}
+
+// TimeWithEpoch returns the time component of the Snowflake relative to e,
+// for decoding Snowflakes minted with an epoch other than the one passed to Init.
+func (s Snowflake) TimeWithEpoch(e time.Time) time.Time {
+	return e.Add(time.Duration(s>>timestampShift) * time.Millisecond)
+}
+
+// Age returns how long ago the Snowflake was created.
+// Snowflakes with a timestamp in the future, e.g. due to clock skew,
+// return a negative duration.
+func (s Snowflake) Age() time.Duration {
+	return currentTime().Sub(s.Time())
+}
+
+// Before reports whether the Snowflake was created before t.
+func (s Snowflake) Before(t time.Time) bool {
+	return s.Time().Before(t)
+}
+
+// After reports whether the Snowflake was created after t.
+func (s Snowflake) After(t time.Time) bool {
+	return s.Time().After(t)
+}
+
+// WorkerID returns the 5 worker ID bits of the Snowflake,
+// as set by the worker ID passed to Init.
+func (s Snowflake) WorkerID() uint8 {
+	return uint8((s >> workerShift) & workerMask)
+}
+
+// ProcessID returns the 5 process ID bits of the Snowflake,
+// as set by the process ID passed to Init.
+func (s Snowflake) ProcessID() uint8 {
+	return uint8((s >> processShift) & processMask)
+}
+
+// Sequence returns the 12 sequence bits of the Snowflake.
+func (s Snowflake) Sequence() uint16 {
+	return uint16(s & sequenceMask)
+}
+
+// Parts holds the decoded components of a Snowflake.
+type Parts struct {
+	Time      time.Time
+	WorkerID  uint16
+	ProcessID uint16
+	Sequence  uint16
+}
+
+// Deconstruct decodes every component of the Snowflake at once,
+// using the epoch passed to Init for the time component.
+func (s Snowflake) Deconstruct() Parts {
+	return Parts{
+		Time:      s.Time(),
+		WorkerID:  uint16(s.WorkerID()),
+		ProcessID: uint16(s.ProcessID()),
+		Sequence:  s.Sequence(),
+	}
+}
+
+// IsZero reports whether the Snowflake is zero, which is used to mean unset.
+// An invalid NullSnowflake yields a zero Snowflake from ValueOrZero.
+func (s Snowflake) IsZero() bool {
+	return s == 0
+}
+
+// Compare returns -1 if s is less than other, 0 if they are equal
+// and +1 if s is greater than other. For Snowflakes sharing a layout
+// and epoch this is also chronological order.
+func (s Snowflake) Compare(other Snowflake) int {
+	return Compare(s, other)
+}
+
+// Compare returns -1 if a is less than b, 0 if they are equal
+// and +1 if a is greater than b.
+// It is suitable for use with slices.SortFunc and slices.BinarySearchFunc.
+func Compare(a, b Snowflake) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	default:
+		return 0
+	}
+}
+
+type offsetOptions struct {
+	zeroLowBits bool
+}
+
+// OffsetOption configures the Snowflake returned by Offset.
+type OffsetOption func(*offsetOptions)
+
+// WithZeroedLowBits makes Offset clear the worker, process and sequence bits
+// instead of preserving them.
+func WithZeroedLowBits() OffsetOption {
+	return func(o *offsetOptions) {
+		o.zeroLowBits = true
+	}
+}
+
+// Offset returns a Snowflake whose timestamp is shifted by d,
+// which is truncated to whole milliseconds and may be negative.
+// The worker, process and sequence bits are preserved unless WithZeroedLowBits is passed.
+// An error is returned if the result would be before the epoch or overflow the timestamp bits.
+func (s Snowflake) Offset(d time.Duration, opts ...OffsetOption) (Snowflake, error) {
+	var o offsetOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	ms := int64(s>>timestampShift) + d.Milliseconds()
+	if ms < 0 {
+		return 0, fmt.Errorf("offset %v moves %d before the epoch", d, s)
+	}
+
+	if ms > timestampMask {
+		return 0, fmt.Errorf("offset %v overflows the %d timestamp bits of %d", d, timestampBits, s)
+	}
+
+	low := s & (1<<timestampShift - 1)
+	if o.zeroLowBits {
+		low = 0
+	}
+
+	return Snowflake(ms)<<timestampShift | low, nil
+}
+
+// Truncate returns the smallest Snowflake whose timestamp is the Snowflake's time
+// rounded down to a multiple of d, following time.Time.Truncate,
+// with the worker, process and sequence bits cleared.
+// A boundary before the epoch is clamped to the epoch.
+// If d is less than a millisecond, including non-positive, s is returned unchanged.
+func (s Snowflake) Truncate(d time.Duration) Snowflake {
+	if d < time.Millisecond {
+		return s
+	}
+
+	e := currentEpoch()
+	t := s.Time().Truncate(d)
+	if t.Before(e) {
+		t = e
+	}
+
+	return Snowflake(t.Sub(e).Milliseconds()) << timestampShift
+}
+
+// Bucket returns the Snowflake modulo n, for sharding work across n buckets.
+// Like integer division, it panics if n is zero.
+func (s Snowflake) Bucket(n uint64) uint64 {
+	return uint64(s) % n
+}
+
+// TimeBucket is like Bucket but only considers the timestamp bits,
+// so that all Snowflakes from the same millisecond land in the same bucket.
+// It panics if n is zero.
+func (s Snowflake) TimeBucket(n uint64) uint64 {
+	return uint64(s>>timestampShift) % n
+}
+
 // CreatedAt returns the time component of the Snowflake as a time.Time
 func (s Snowflake) CreatedAt() time.Time {
-	unixMilis := (s >> 22) + Snowflake(epoch.UnixMilli())
-
-	return time.UnixMilli(int64(unixMilis))
+	return s.Time()
 }
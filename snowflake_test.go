@@ -24,6 +24,16 @@
 package snowflake_test
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"math"
+	"math/rand"
+	"slices"
+	"strings"
+	"sync"
 	"testing"
 	"time"
 
@@ -44,3 +54,917 @@ func TestGenerate(t *testing.T) {
 		t.Fail()
 	}
 }
+
+func TestInitChecked(t *testing.T) {
+	epoch := time.Now().Add(-time.Hour)
+
+	tests := []struct {
+		name  string
+		epoch time.Time
+		w, p  int
+	}{
+		{"zero epoch", time.Time{}, 0, 0},
+		{"future epoch", time.Now().Add(time.Hour), 0, 0},
+		{"worker too large", epoch, 32, 0},
+		{"worker negative", epoch, -1, 0},
+		{"process too large", epoch, 0, 32},
+		{"process negative", epoch, 0, -1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := snowflake.InitChecked(tt.epoch, tt.w, tt.p); err == nil {
+				t.Error("expected error")
+			}
+
+			defer func() {
+				if recover() == nil {
+					t.Error("Init did not panic")
+				}
+			}()
+			snowflake.Init(tt.epoch, tt.w, tt.p)
+		})
+	}
+
+	if err := snowflake.InitChecked(epoch, 31, 31); err != nil {
+		t.Errorf("InitChecked() = %v, want nil", err)
+	}
+}
+
+func TestTime(t *testing.T) {
+	snowflake.Init(time.Now().Add(-time.Hour), 1, 1)
+
+	before := time.Now()
+	s := snowflake.Generate()
+	after := time.Now()
+
+	ts := s.Time()
+	if ts.Before(before.Add(-time.Millisecond)) || ts.After(after) {
+		t.Errorf("Time() = %v, want between %v and %v", ts, before, after)
+	}
+}
+
+func TestTimeWithoutInit(t *testing.T) {
+	snowflake.ResetDefault()
+
+	s := snowflake.Snowflake(1000 << 22)
+	if !s.Time().Equal(time.UnixMilli(1000)) {
+		t.Errorf("Time() = %v, want %v", s.Time(), time.UnixMilli(1000))
+	}
+}
+
+func TestWorkerID(t *testing.T) {
+	epoch := time.Now()
+
+	for _, w := range []int{0, 1, 17, 31} {
+		snowflake.Init(epoch, w, 0)
+
+		if s := snowflake.Generate(); s.WorkerID() != uint8(w) {
+			t.Errorf("WorkerID() = %d, want %d", s.WorkerID(), w)
+		}
+	}
+}
+
+func TestProcessID(t *testing.T) {
+	epoch := time.Now()
+
+	tests := []struct {
+		name    string
+		worker  int
+		process int
+	}{
+		{"zero", 0, 0},
+		{"one", 0, 1},
+		{"middle", 31, 16},
+		{"max", 0, 31},
+		{"max with worker", 31, 31},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			snowflake.Init(epoch, tt.worker, tt.process)
+
+			s := snowflake.Generate()
+			if s.ProcessID() != uint8(tt.process) {
+				t.Errorf("ProcessID() = %d, want %d", s.ProcessID(), tt.process)
+			}
+
+			if s.WorkerID() != uint8(tt.worker) {
+				t.Errorf("WorkerID() = %d, want %d", s.WorkerID(), tt.worker)
+			}
+		})
+	}
+}
+
+func TestSequence(t *testing.T) {
+	epoch := time.Now().Add(-time.Hour)
+	snowflake.Init(epoch, 31, 31)
+
+	ts := time.Now()
+	restore := snowflake.FreezeClock(ts)
+	defer restore()
+
+	for i := 0; i < 4096; i++ {
+		s := snowflake.Generate()
+		if s.Sequence() != uint16(i) {
+			t.Fatalf("Sequence() = %d, want %d", s.Sequence(), i)
+		}
+
+		if s.ProcessID() != 31 || s.WorkerID() != 31 {
+			t.Fatalf("sequence corrupted worker/process: %d/%d", s.WorkerID(), s.ProcessID())
+		}
+	}
+
+	// The sequence starts over in the next millisecond.
+	snowflake.FreezeClock(ts.Add(time.Millisecond))
+	if s := snowflake.Generate(); s.Sequence() != 0 {
+		t.Errorf("Sequence() in next millisecond = %d, want 0", s.Sequence())
+	}
+}
+
+func TestDeconstruct(t *testing.T) {
+	snowflake.Init(time.Now().Add(-time.Hour), 3, 7)
+
+	snowflake.Generate()
+	s := snowflake.Generate()
+
+	p := s.Deconstruct()
+	want := snowflake.Parts{
+		Time:      s.Time(),
+		WorkerID:  3,
+		ProcessID: 7,
+		Sequence:  1,
+	}
+
+	if p != want {
+		t.Errorf("Deconstruct() = %+v, want %+v", p, want)
+	}
+}
+
+func TestCompose(t *testing.T) {
+	epoch := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	snowflake.Init(epoch, 0, 0)
+
+	ts := epoch.Add(1234567 * time.Millisecond)
+
+	s, err := snowflake.Compose(ts, 31, 17, 4095)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := snowflake.Parts{Time: ts, WorkerID: 31, ProcessID: 17, Sequence: 4095}
+	if p := s.Deconstruct(); !p.Time.Equal(want.Time) || p.WorkerID != want.WorkerID ||
+		p.ProcessID != want.ProcessID || p.Sequence != want.Sequence {
+		t.Errorf("Deconstruct() = %+v, want %+v", p, want)
+	}
+}
+
+func TestComposeInvalid(t *testing.T) {
+	epoch := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	snowflake.Init(epoch, 0, 0)
+
+	tests := []struct {
+		name    string
+		t       time.Time
+		worker  uint8
+		process uint8
+		seq     uint16
+	}{
+		{"before epoch", epoch.Add(-time.Microsecond), 0, 0, 0},
+		{"timestamp overflow", epoch.Add(1 << 42 * time.Millisecond), 0, 0, 0},
+		{"worker", epoch, 32, 0, 0},
+		{"process", epoch, 0, 32, 0},
+		{"sequence", epoch, 0, 0, 4096},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := snowflake.Compose(tt.t, tt.worker, tt.process, tt.seq); err == nil {
+				t.Error("expected error")
+			}
+		})
+	}
+}
+
+func TestAge(t *testing.T) {
+	epoch := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	snowflake.Init(epoch, 0, 0)
+
+	s, err := snowflake.Compose(epoch.Add(time.Hour), 0, 0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	restore := snowflake.FreezeClock(epoch.Add(3 * time.Hour))
+	if age := s.Age(); age != 2*time.Hour {
+		t.Errorf("Age() = %v, want %v", age, 2*time.Hour)
+	}
+	restore()
+
+	restore = snowflake.FreezeClock(epoch)
+	defer restore()
+	if age := s.Age(); age != -time.Hour {
+		t.Errorf("Age() = %v, want %v", age, -time.Hour)
+	}
+}
+
+func TestBeforeAfter(t *testing.T) {
+	epoch := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	snowflake.Init(epoch, 0, 0)
+
+	ts := epoch.Add(time.Hour)
+	s, err := snowflake.Compose(ts, 1, 1, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name   string
+		t      time.Time
+		before bool
+		after  bool
+	}{
+		{"equal", ts, false, false},
+		{"earlier", ts.Add(-time.Millisecond), false, true},
+		{"later", ts.Add(time.Millisecond), true, false},
+		{"sub-millisecond earlier", ts.Add(-time.Microsecond), false, true},
+		{"sub-millisecond later", ts.Add(time.Microsecond), true, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := s.Before(tt.t); got != tt.before {
+				t.Errorf("Before() = %v, want %v", got, tt.before)
+			}
+
+			if got := s.After(tt.t); got != tt.after {
+				t.Errorf("After() = %v, want %v", got, tt.after)
+			}
+		})
+	}
+}
+
+func TestCompare(t *testing.T) {
+	if c := snowflake.Compare(1, 2); c != -1 {
+		t.Errorf("Compare(1, 2) = %d, want -1", c)
+	}
+
+	if c := snowflake.Compare(2, 1); c != 1 {
+		t.Errorf("Compare(2, 1) = %d, want 1", c)
+	}
+
+	if c := snowflake.Snowflake(2).Compare(2); c != 0 {
+		t.Errorf("Compare(2, 2) = %d, want 0", c)
+	}
+}
+
+func TestCompareSortFunc(t *testing.T) {
+	snowflake.Init(time.Now().Add(-time.Hour), 0, 0)
+
+	ids := make([]snowflake.Snowflake, 100)
+	for i := range ids {
+		ids[i] = snowflake.Generate()
+	}
+
+	shuffled := slices.Clone(ids)
+	rand.Shuffle(len(shuffled), func(i, j int) {
+		shuffled[i], shuffled[j] = shuffled[j], shuffled[i]
+	})
+
+	slices.SortFunc(shuffled, snowflake.Compare)
+	if !slices.Equal(shuffled, ids) {
+		t.Error("SortFunc(Compare) did not restore generation order")
+	}
+
+	i, found := slices.BinarySearchFunc(shuffled, ids[42], snowflake.Compare)
+	if !found || i != 42 {
+		t.Errorf("BinarySearchFunc() = %d, %v, want 42, true", i, found)
+	}
+}
+
+func TestIsZero(t *testing.T) {
+	if !snowflake.Snowflake(0).IsZero() {
+		t.Error("Snowflake(0).IsZero() = false, want true")
+	}
+
+	if snowflake.Snowflake(1).IsZero() {
+		t.Error("Snowflake(1).IsZero() = true, want false")
+	}
+
+	if !snowflake.NullSnowflakeFromPtr(nil).ValueOrZero().IsZero() {
+		t.Error("invalid NullSnowflake ValueOrZero().IsZero() = false, want true")
+	}
+}
+
+func TestBucket(t *testing.T) {
+	epoch := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	snowflake.Init(epoch, 0, 0)
+
+	a, _ := snowflake.Compose(epoch.Add(7*time.Millisecond), 1, 2, 3)
+	b, _ := snowflake.Compose(epoch.Add(7*time.Millisecond), 4, 5, 6)
+
+	if got := a.Bucket(10); got != uint64(a)%10 {
+		t.Errorf("Bucket(10) = %d, want %d", got, uint64(a)%10)
+	}
+
+	if a.TimeBucket(5) != 2 || b.TimeBucket(5) != 2 {
+		t.Errorf("TimeBucket(5) = %d, %d, want 2, 2", a.TimeBucket(5), b.TimeBucket(5))
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("Bucket(0) did not panic")
+		}
+	}()
+	a.Bucket(0)
+}
+
+func TestTimeWithEpoch(t *testing.T) {
+	discord := time.Date(2015, 1, 1, 0, 0, 0, 0, time.UTC)
+	custom := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	s := snowflake.Snowflake(175928847299117063)
+
+	a := s.TimeWithEpoch(discord)
+	b := s.TimeWithEpoch(custom)
+
+	if d := b.Sub(a); d != custom.Sub(discord) {
+		t.Errorf("epoch delta = %v, want %v", d, custom.Sub(discord))
+	}
+
+	want := time.Date(2016, 4, 30, 11, 18, 25, 796000000, time.UTC)
+	if !a.Equal(want) {
+		t.Errorf("TimeWithEpoch(discord) = %v, want %v", a, want)
+	}
+}
+
+func TestOffset(t *testing.T) {
+	epoch := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	snowflake.Init(epoch, 0, 0)
+
+	s, _ := snowflake.Compose(epoch.Add(time.Hour), 3, 4, 5)
+
+	for _, d := range []time.Duration{0, 5 * time.Minute, -5 * time.Minute, -time.Hour} {
+		o, err := s.Offset(d)
+		if err != nil {
+			t.Fatalf("Offset(%v): %v", d, err)
+		}
+
+		if got := o.Time().Sub(s.Time()); got != d {
+			t.Errorf("Offset(%v) moved time by %v", d, got)
+		}
+
+		if o.WorkerID() != 3 || o.ProcessID() != 4 || o.Sequence() != 5 {
+			t.Errorf("Offset(%v) = %+v, want low bits preserved", d, o.Deconstruct())
+		}
+	}
+
+	o, err := s.Offset(time.Minute, snowflake.WithZeroedLowBits())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if o.WorkerID() != 0 || o.ProcessID() != 0 || o.Sequence() != 0 {
+		t.Errorf("Offset(WithZeroedLowBits) = %+v, want low bits zeroed", o.Deconstruct())
+	}
+
+	if _, err := s.Offset(-time.Hour - time.Millisecond); err == nil {
+		t.Error("expected error for underflow past the epoch")
+	}
+
+	if _, err := s.Offset(1 << 42 * time.Millisecond); err == nil {
+		t.Error("expected error for timestamp overflow")
+	}
+}
+
+func TestTruncate(t *testing.T) {
+	epoch := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	snowflake.Init(epoch, 0, 0)
+
+	ts := epoch.Add(26*time.Hour + 17*time.Minute + 42*time.Second + 123*time.Millisecond)
+	s, _ := snowflake.Compose(ts, 1, 2, 3)
+
+	for _, d := range []time.Duration{time.Millisecond, time.Second, time.Minute, time.Hour, 24 * time.Hour} {
+		tr := s.Truncate(d)
+		if !tr.Time().Equal(ts.Truncate(d)) {
+			t.Errorf("Truncate(%v).Time() = %v, want %v", d, tr.Time(), ts.Truncate(d))
+		}
+
+		if tr.WorkerID() != 0 || tr.ProcessID() != 0 || tr.Sequence() != 0 {
+			t.Errorf("Truncate(%v) = %+v, want low bits cleared", d, tr.Deconstruct())
+		}
+	}
+
+	for _, d := range []time.Duration{0, -time.Second, time.Microsecond} {
+		if tr := s.Truncate(d); tr != s {
+			t.Errorf("Truncate(%v) = %d, want %d unchanged", d, tr, s)
+		}
+	}
+}
+
+func TestDebugString(t *testing.T) {
+	snowflake.Init(time.Date(2015, 1, 1, 0, 0, 0, 0, time.UTC), 0, 0)
+
+	tests := []struct {
+		s    snowflake.Snowflake
+		want string
+	}{
+		{175928847299117063, "id=175928847299117063 time=2016-04-30T11:18:25.796Z worker=1 process=0 seq=7"},
+		{0, "id=0 time=2015-01-01T00:00:00Z worker=0 process=0 seq=0"},
+	}
+
+	for _, tt := range tests {
+		if got := tt.s.DebugString(); got != tt.want {
+			t.Errorf("DebugString() = %q, want %q", got, tt.want)
+		}
+	}
+}
+
+func TestSetDefault(t *testing.T) {
+	defer snowflake.ResetDefault()
+
+	epoch := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	clock := snowflake.NewFakeClock(epoch.Add(time.Hour))
+
+	g, err := snowflake.New(snowflake.WithEpoch(epoch), snowflake.WithWorkerID(7), snowflake.WithClock(clock))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	snowflake.SetDefault(g)
+
+	if snowflake.Default() != g {
+		t.Error("Default() did not return the Generator passed to SetDefault")
+	}
+
+	s := snowflake.Generate()
+	if s.WorkerID() != 7 || !s.Time().Equal(epoch.Add(time.Hour)) {
+		t.Errorf("Generate() = %v, want worker 7 at %v", s.DebugString(), epoch.Add(time.Hour))
+	}
+
+	if g.Stats().Generated != 1 {
+		t.Errorf("Stats().Generated = %d, want 1", g.Stats().Generated)
+	}
+}
+
+func TestSetDefaultConcurrent(t *testing.T) {
+	defer snowflake.ResetDefault()
+
+	snowflake.Init(time.Now().Add(-time.Hour), 0, 0)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 1000; j++ {
+				snowflake.Generate()
+			}
+		}()
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				g, err := snowflake.New(snowflake.WithEpoch(time.Now().Add(-time.Hour)), snowflake.WithWorkerID(uint16(i)))
+				if err != nil {
+					t.Error(err)
+					return
+				}
+				snowflake.SetDefault(g)
+			}
+		}(i)
+	}
+	wg.Wait()
+}
+
+func TestGenerateWithoutDefault(t *testing.T) {
+	snowflake.ResetDefault()
+
+	if snowflake.Default() != nil {
+		t.Error("Default() before Init is not nil")
+	}
+
+	if _, err := snowflake.TryGenerate(); !errors.Is(err, snowflake.ErrNoDefault) {
+		t.Errorf("TryGenerate() = %v, want ErrNoDefault", err)
+	}
+
+	if _, err := snowflake.GenerateContext(context.Background()); !errors.Is(err, snowflake.ErrNoDefault) {
+		t.Errorf("GenerateContext() = %v, want ErrNoDefault", err)
+	}
+
+	defer func() {
+		if r := recover(); r == nil || !strings.Contains(fmt.Sprint(r), "Init or SetDefault") {
+			t.Errorf("Generate() panicked with %v, want a message about Init or SetDefault", r)
+		}
+	}()
+
+	snowflake.Generate()
+}
+
+func TestAppendString(t *testing.T) {
+	for _, s := range []snowflake.Snowflake{0, 1, 175928847299117063, math.MaxUint64} {
+		if got := string(s.AppendString([]byte("id="))); got != "id="+s.String() {
+			t.Errorf("AppendString() = %q, want %q", got, "id="+s.String())
+		}
+
+		got, err := s.AppendText(nil)
+		if err != nil || string(got) != s.String() {
+			t.Errorf("AppendText() = %q, %v, want %q", got, err, s.String())
+		}
+	}
+
+	s := snowflake.Snowflake(175928847299117063)
+	buf := make([]byte, 0, 64)
+	if n := testing.AllocsPerRun(100, func() { s.AppendString(buf) }); n != 0 {
+		t.Errorf("AppendString allocates %v times, want 0", n)
+	}
+}
+
+func BenchmarkAppendString(b *testing.B) {
+	s := snowflake.Snowflake(175928847299117063)
+	buf := make([]byte, 0, 64)
+	b.ReportAllocs()
+
+	for i := 0; i < b.N; i++ {
+		buf = s.AppendString(buf[:0])
+	}
+}
+
+func BenchmarkAppendStringConversion(b *testing.B) {
+	s := snowflake.Snowflake(175928847299117063)
+	buf := make([]byte, 0, 64)
+	b.ReportAllocs()
+
+	for i := 0; i < b.N; i++ {
+		buf = append(buf[:0], s.String()...)
+	}
+}
+
+func TestStringBoundaries(t *testing.T) {
+	for _, tt := range []struct {
+		s    snowflake.Snowflake
+		want string
+	}{
+		{0, "0"},
+		{1, "1"},
+		{175928847299117063, "175928847299117063"},
+		{math.MaxUint64, "18446744073709551615"},
+	} {
+		if got := tt.s.String(); got != tt.want {
+			t.Errorf("String() = %q, want %q", got, tt.want)
+		}
+
+		// MarshalJSON must match encoding a string.
+		want, _ := json.Marshal(tt.want)
+		if got, err := tt.s.MarshalJSON(); err != nil || string(got) != string(want) {
+			t.Errorf("MarshalJSON() = %s, %v, want %s", got, err, want)
+		}
+	}
+}
+
+func BenchmarkString(b *testing.B) {
+	s := snowflake.Snowflake(175928847299117063)
+	b.ReportAllocs()
+
+	for i := 0; i < b.N; i++ {
+		_ = s.String()
+	}
+}
+
+func BenchmarkMarshalJSON(b *testing.B) {
+	s := snowflake.Snowflake(175928847299117063)
+	b.ReportAllocs()
+
+	for i := 0; i < b.N; i++ {
+		s.MarshalJSON()
+	}
+}
+
+func TestMarshalText(t *testing.T) {
+	for _, s := range []snowflake.Snowflake{0, 1, 175928847299117063, math.MaxUint64} {
+		text, err := s.MarshalText()
+		if err != nil || string(text) != s.String() {
+			t.Errorf("MarshalText() = %q, %v, want %q", text, err, s.String())
+		}
+
+		var got snowflake.Snowflake
+		if err := got.UnmarshalText(text); err != nil || got != s {
+			t.Errorf("UnmarshalText(%q) = %d, %v, want %d", text, got, err, s)
+		}
+	}
+
+	// Leading zeros are accepted, as by SnowflakeFromString.
+	var got snowflake.Snowflake
+	if err := got.UnmarshalText([]byte("0042")); err != nil || got != 42 {
+		t.Errorf(`UnmarshalText("0042") = %d, %v, want 42`, got, err)
+	}
+
+	for _, tt := range []struct {
+		in  string
+		err error
+	}{
+		{"", snowflake.ErrSyntax},
+		{"abc", snowflake.ErrSyntax},
+		{"1.5", snowflake.ErrSyntax},
+		{"+1", snowflake.ErrSyntax},
+		{"-1", snowflake.ErrNegative},
+		{"18446744073709551616", snowflake.ErrOverflow},
+	} {
+		got := snowflake.Snowflake(7)
+		err := got.UnmarshalText([]byte(tt.in))
+
+		var perr *snowflake.ParseError
+		if !errors.Is(err, tt.err) || !errors.As(err, &perr) || perr.Func != "UnmarshalText" {
+			t.Errorf("UnmarshalText(%q) = %v, want a ParseError wrapping %v", tt.in, err, tt.err)
+		}
+
+		if got != 7 {
+			t.Errorf("failed UnmarshalText(%q) changed the Snowflake to %d", tt.in, got)
+		}
+	}
+}
+
+func TestNullSnowflakeMarshalText(t *testing.T) {
+	for _, tt := range []struct {
+		in   snowflake.NullSnowflake
+		want string
+	}{
+		{snowflake.NewNullSnowflake(0, false), "null"},
+		{snowflake.NewNullSnowflake(0, true), "0"},
+		{snowflake.NewNullSnowflake(175928847299117063, true), "175928847299117063"},
+	} {
+		text, err := tt.in.MarshalText()
+		if err != nil || string(text) != tt.want {
+			t.Errorf("%+v.MarshalText() = %q, %v, want %q", tt.in, text, err, tt.want)
+		}
+
+		got := snowflake.NewNullSnowflake(7, !tt.in.Valid)
+		if err := got.UnmarshalText(text); err != nil || got != tt.in {
+			t.Errorf("UnmarshalText(%q) = %+v, %v, want %+v", text, got, err, tt.in)
+		}
+	}
+
+	got := snowflake.NewNullSnowflake(7, true)
+	if err := got.UnmarshalText([]byte("abc")); !errors.Is(err, snowflake.ErrSyntax) || got != snowflake.NewNullSnowflake(7, true) {
+		t.Errorf(`UnmarshalText("abc") = %+v, %v, want ErrSyntax and no change`, got, err)
+	}
+
+	// MarshalJSON still takes precedence over MarshalText.
+	b, err := json.Marshal([]snowflake.NullSnowflake{{}, snowflake.NewNullSnowflake(3, true)})
+	if want := `[null,"3"]`; err != nil || string(b) != want {
+		t.Errorf("json.Marshal() = %s, %v, want %s", b, err, want)
+	}
+}
+
+// TestJSONUnchangedByText locks down the JSON encoding, which must keep going
+// through MarshalJSON and UnmarshalJSON now that Snowflake is also a
+// TextMarshaler and TextUnmarshaler.
+func TestJSONUnchangedByText(t *testing.T) {
+	type record struct {
+		ID      snowflake.Snowflake            `json:"id"`
+		Ptr     *snowflake.Snowflake           `json:"ptr"`
+		Null    snowflake.NullSnowflake        `json:"null"`
+		Valid   snowflake.NullSnowflake        `json:"valid"`
+		List    []snowflake.Snowflake          `json:"list"`
+		ByID    map[snowflake.Snowflake]string `json:"by_id"`
+		Omitted snowflake.Snowflake            `json:"omitted,omitempty"`
+	}
+
+	ptr := snowflake.Snowflake(2)
+	in := record{
+		ID:    175928847299117063,
+		Ptr:   &ptr,
+		Valid: snowflake.NewNullSnowflake(3, true),
+		List:  []snowflake.Snowflake{0, math.MaxUint64},
+		ByID:  map[snowflake.Snowflake]string{10: "a", 9: "b"},
+	}
+
+	const want = `{"id":"175928847299117063","ptr":"2","null":null,"valid":"3",` +
+		`"list":["0","18446744073709551615"],"by_id":{"10":"a","9":"b"}}`
+
+	got, err := json.Marshal(in)
+	if err != nil || string(got) != want {
+		t.Fatalf("json.Marshal() = %s, %v, want %s", got, err, want)
+	}
+
+	var out record
+	if err := json.Unmarshal(got, &out); err != nil {
+		t.Fatal(err)
+	}
+
+	if out.ID != in.ID || *out.Ptr != ptr || out.Null.Valid || out.Valid != in.Valid ||
+		!slices.Equal(out.List, in.List) || !maps.Equal(out.ByID, in.ByID) {
+		t.Errorf("json.Unmarshal(%s) = %+v, want %+v", got, out, in)
+	}
+
+	// Like UnmarshalText, UnmarshalJSON rejects an empty string.
+	var s snowflake.Snowflake = 7
+	if err := json.Unmarshal([]byte(`""`), &s); !errors.Is(err, snowflake.ErrSyntax) || s != 7 {
+		t.Errorf(`json.Unmarshal("") = %d, %v, want ErrSyntax`, s, err)
+	}
+}
+
+func TestScan(t *testing.T) {
+	for _, tt := range []struct {
+		in   any
+		want snowflake.Snowflake
+	}{
+		{nil, 0},
+		{int64(175928847299117063), 175928847299117063},
+		{int64(-1), math.MaxUint64}, // as stored by Value
+		{"175928847299117063", 175928847299117063},
+		{[]byte("175928847299117063"), 175928847299117063},
+		{float64(42), 42},
+		{uint64(math.MaxUint64), math.MaxUint64},
+	} {
+		got := snowflake.Snowflake(7)
+		if err := got.Scan(tt.in); err != nil || got != tt.want {
+			t.Errorf("Scan(%T(%v)) = %d, %v, want %d", tt.in, tt.in, got, err, tt.want)
+		}
+	}
+
+	// Scan reverses Value, including above math.MaxInt64.
+	for _, s := range []snowflake.Snowflake{0, 175928847299117063, math.MaxUint64} {
+		v, _ := s.Value()
+
+		var got snowflake.Snowflake
+		if err := got.Scan(v); err != nil || got != s {
+			t.Errorf("Scan(Value(%d)) = %d, %v", s, got, err)
+		}
+	}
+
+	for _, in := range []any{"abc", []byte("-1"), float64(1.5), true, time.Now()} {
+		got := snowflake.Snowflake(7)
+		if err := got.Scan(in); err == nil {
+			t.Errorf("Scan(%T(%v)) = %d, want error", in, in, got)
+		}
+
+		if got != 7 {
+			t.Errorf("failed Scan(%T) changed the Snowflake to %d", in, got)
+		}
+	}
+}
+
+func TestUnmarshalJSON(t *testing.T) {
+	for _, tt := range []struct {
+		in   string
+		want snowflake.Snowflake
+	}{
+		{`"175928847299117063"`, 175928847299117063},
+		{`175928847299117063`, 175928847299117063},
+		{`"0"`, 0},
+		{`0`, 0},
+		{`null`, 0},
+		{`"007"`, 7},
+		{`"\u0031\u0032"`, 12},
+		// Precision boundary: 2^53+1 is the first integer a float64 cannot hold.
+		{`9007199254740991`, 1<<53 - 1},
+		{`9007199254740992`, 1 << 53},
+		{`9007199254740993`, 1<<53 + 1},
+		{`"9007199254740993"`, 1<<53 + 1},
+		{`9223372036854775808`, 1 << 63},
+		{`18446744073709551615`, math.MaxUint64},
+		{`"18446744073709551615"`, math.MaxUint64},
+		{`"00000000000000000042"`, 42}, // 22 bytes quoted, the most allowed
+	} {
+		var s snowflake.Snowflake = 42
+		if err := s.UnmarshalJSON([]byte(tt.in)); err != nil || s != tt.want {
+			t.Errorf("UnmarshalJSON(%s) = %d, %v, want %d", tt.in, s, err, tt.want)
+		}
+
+		var doc struct {
+			ID snowflake.Snowflake `json:"id"`
+		}
+		if err := json.Unmarshal([]byte(`{"id": `+tt.in+`}`), &doc); err != nil || doc.ID != tt.want {
+			t.Errorf("json.Unmarshal(id: %s) = %d, %v, want %d", tt.in, doc.ID, err, tt.want)
+		}
+	}
+
+	for _, tt := range []struct {
+		in   string
+		want error
+	}{
+		{`""`, snowflake.ErrSyntax},
+		{`"null"`, snowflake.ErrSyntax},
+		{`"abc"`, snowflake.ErrSyntax},
+		{`"12abc"`, snowflake.ErrSyntax},
+		{`" 12"`, snowflake.ErrSyntax},
+		{`"+12"`, snowflake.ErrSyntax},
+		{`"0x12"`, snowflake.ErrSyntax},
+		{`true`, snowflake.ErrSyntax},
+		{`{}`, snowflake.ErrSyntax},
+		{`[]`, snowflake.ErrSyntax},
+		{`"`, snowflake.ErrSyntax},
+		{`-5`, snowflake.ErrNegative},
+		{`"-5"`, snowflake.ErrNegative},
+		{`-0`, snowflake.ErrNegative},
+		{`18446744073709551616`, snowflake.ErrOverflow},
+		{`"18446744073709551616"`, snowflake.ErrOverflow},
+		{`9007199254740993.0`, snowflake.ErrNonIntegerJSON},
+		{`1e3`, snowflake.ErrNonIntegerJSON},
+		{`"000000000000000000042"`, snowflake.ErrTooLong},
+		{`184467440737095516150`, snowflake.ErrTooLong},
+	} {
+		var s snowflake.Snowflake = 42
+		if err := s.UnmarshalJSON([]byte(tt.in)); !errors.Is(err, tt.want) || s != 42 {
+			t.Errorf("UnmarshalJSON(%s) = %d, %v, want %v", tt.in, s, err, tt.want)
+		}
+
+		var n snowflake.NullSnowflake
+		if err := n.UnmarshalJSON([]byte(tt.in)); !errors.Is(err, tt.want) || n.Valid {
+			t.Errorf("NullSnowflake.UnmarshalJSON(%s) = %+v, %v, want %v", tt.in, n, err, tt.want)
+		}
+	}
+
+	for _, tt := range []struct {
+		in   string
+		want snowflake.NullSnowflake
+	}{
+		{`null`, snowflake.NewNullSnowflake(0, false)},
+		{`"0"`, snowflake.NewNullSnowflake(0, true)},
+		{`12`, snowflake.NewNullSnowflake(12, true)},
+		{`"9007199254740993"`, snowflake.NewNullSnowflake(1<<53+1, true)},
+	} {
+		var n snowflake.NullSnowflake
+		if err := n.UnmarshalJSON([]byte(tt.in)); err != nil || n != tt.want {
+			t.Errorf("NullSnowflake.UnmarshalJSON(%s) = %+v, %v, want %+v", tt.in, n, err, tt.want)
+		}
+	}
+}
+
+func BenchmarkUnmarshalJSON(b *testing.B) {
+	in := []byte(`"1069557246566533180"`)
+	var s snowflake.Snowflake
+	for i := 0; i < b.N; i++ {
+		s.UnmarshalJSON(in)
+	}
+}
+
+func TestUnmarshalJSONNonInteger(t *testing.T) {
+	for _, in := range []string{
+		`1.069557246566533e18`, // exponent notation
+		`1.5`,                  // a fraction
+		`9007199254740993.0`,   // a whole number, but above 2^53
+		`175928847299117063.0`,
+		`1e3`,
+		`1E3`,
+		`-1.5`,
+		`0.0`,
+	} {
+		var s snowflake.Snowflake
+		if err := s.UnmarshalJSON([]byte(in)); !errors.Is(err, snowflake.ErrNonIntegerJSON) {
+			t.Errorf("Snowflake.UnmarshalJSON(%s) = %v, want ErrNonIntegerJSON", in, err)
+		}
+
+		var n snowflake.NullSnowflake
+		if err := n.UnmarshalJSON([]byte(in)); !errors.Is(err, snowflake.ErrNonIntegerJSON) || n.Valid {
+			t.Errorf("NullSnowflake.UnmarshalJSON(%s) = %v, valid %v, want ErrNonIntegerJSON", in, err, n.Valid)
+		}
+
+		// The error survives decoding a whole document.
+		var doc struct {
+			ID     snowflake.Snowflake     `json:"id"`
+			Parent snowflake.NullSnowflake `json:"parent"`
+		}
+		if err := json.Unmarshal([]byte(`{"id": `+in+`}`), &doc); !errors.Is(err, snowflake.ErrNonIntegerJSON) {
+			t.Errorf("json.Unmarshal(id: %s) = %v, want ErrNonIntegerJSON", in, err)
+		}
+
+		if err := json.Unmarshal([]byte(`{"parent": `+in+`}`), &doc); !errors.Is(err, snowflake.ErrNonIntegerJSON) {
+			t.Errorf("json.Unmarshal(parent: %s) = %v, want ErrNonIntegerJSON", in, err)
+		}
+	}
+
+	// Strings holding floats are a syntax error, not a lossy conversion.
+	var s snowflake.Snowflake
+	if err := s.UnmarshalJSON([]byte(`"1.5e3"`)); err == nil || errors.Is(err, snowflake.ErrNonIntegerJSON) {
+		t.Errorf(`UnmarshalJSON("1.5e3") = %v, want a syntax error`, err)
+	}
+}
+
+func FuzzUnmarshalJSON(f *testing.F) {
+	for _, s := range []string{`"1069557246566533180"`, `"0"`, `""`, `null`, `"null"`, `1.5`, `1e3`, `"-1"`, `"18446744073709551616"`, `{}`, `"`, `"000000000000000000042"`, `"\u0031"`} {
+		f.Add([]byte(s))
+	}
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		var s snowflake.Snowflake
+		if err := s.UnmarshalJSON(data); err != nil {
+			return
+		}
+
+		b, err := s.MarshalJSON()
+		if err != nil {
+			t.Fatalf("MarshalJSON(%d) = %v", s, err)
+		}
+
+		var again snowflake.Snowflake
+		if err := again.UnmarshalJSON(b); err != nil || again != s {
+			t.Errorf("UnmarshalJSON(%s) = %d, but %s unmarshals to %d, %v", data, s, b, again, err)
+		}
+
+		var n snowflake.NullSnowflake
+		if err := n.UnmarshalJSON(data); err != nil || n.Snowflake != s {
+			t.Errorf("NullSnowflake.UnmarshalJSON(%s) = %+v, %v, want %d", data, n, err, s)
+		}
+	})
+}
@@ -0,0 +1,51 @@
+// MIT License
+
+// Copyright (c) 2022 Project-Sparrow
+// Copyright (c) 2023 Kelwing <kelwing@kelnet.org>
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package snowflake
+
+import "time"
+
+// EpochSonyflake is the default start time of Sonyflake, 2014-09-01 UTC.
+var EpochSonyflake = time.Date(2014, time.September, 1, 0, 0, 0, 0, time.UTC)
+
+// LayoutSonyflake is the bit layout of Sonyflake: a 39-bit timestamp in units
+// of 10ms, an 8-bit sequence and a 16-bit machine ID held in the worker field.
+var LayoutSonyflake = Layout{TimestampBits: 39, WorkerBits: 16, ProcessBits: 0, SequenceBits: 8, SequenceHigh: true}
+
+// sonyflakeUnit is the length of a Sonyflake time unit.
+const sonyflakeUnit = 10 * time.Millisecond
+
+// NewSonyflake creates a Generator compatible with Sonyflake, using
+// LayoutSonyflake, EpochSonyflake and 10ms time units.
+// The Snowflakes it generates can be read by Sonyflake and vice versa,
+// as long as they are decoded with the Generator's Time and Deconstruct.
+// opts are applied afterwards, so WithEpoch may be used to pick another start time.
+func NewSonyflake(machineID uint16, opts ...Option) (*Generator, error) {
+	g := newGenerator()
+	g.epoch = EpochSonyflake
+	g.layout = LayoutSonyflake
+	g.unit = sonyflakeUnit
+	g.workerID = machineID
+
+	return g.configure(opts)
+}
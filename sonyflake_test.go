@@ -0,0 +1,106 @@
+// MIT License
+
+// Copyright (c) 2022 Project-Sparrow
+// Copyright (c) 2023 Kelwing <kelwing@kelnet.org>
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package snowflake_test
+
+import (
+	"testing"
+	"time"
+
+	"wumpgo.dev/snowflake"
+)
+
+// sonyflakeVectors were generated by github.com/sony/sonyflake v1.2.0
+// with its default start time and machine ID 0x1234.
+var sonyflakeVectors = []struct {
+	id       snowflake.Snowflake
+	sequence uint16
+}{
+	{641679846128226868, 0},
+	{641679846128292404, 1},
+	{641679846128357940, 2},
+}
+
+// sonyflakeElapsed is the elapsed time of the vectors in 10ms units.
+const sonyflakeElapsed = 38247099288
+
+func TestSonyflakeDecode(t *testing.T) {
+	g, err := snowflake.NewSonyflake(0x1234)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := snowflake.EpochSonyflake.Add(sonyflakeElapsed * 10 * time.Millisecond)
+
+	for _, v := range sonyflakeVectors {
+		p := g.Deconstruct(v.id)
+		if !p.Time.Equal(want) || p.WorkerID != 0x1234 || p.ProcessID != 0 || p.Sequence != v.sequence {
+			t.Errorf("Deconstruct(%d) = %+v, want time %v, machine ID 0x1234, sequence %d", v.id, p, want, v.sequence)
+		}
+	}
+}
+
+func TestSonyflakeGenerate(t *testing.T) {
+	clock := snowflake.NewFakeClock(snowflake.EpochSonyflake.Add(sonyflakeElapsed*10*time.Millisecond + 3*time.Millisecond))
+
+	g, err := snowflake.NewSonyflake(0x1234, snowflake.WithClock(clock))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, v := range sonyflakeVectors {
+		if s := g.Generate(); s != v.id {
+			t.Errorf("Generate() = %d, want %d", s, v.id)
+		}
+	}
+}
+
+func TestSonyflakeSequenceExhausted(t *testing.T) {
+	start := snowflake.EpochSonyflake.Add(time.Hour)
+	clock := snowflake.NewFakeClock(start)
+
+	g, err := snowflake.NewSonyflake(1, snowflake.WithClock(clock))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ids := g.GenerateN(257)
+
+	if got := g.Time(ids[255]); !got.Equal(start) {
+		t.Errorf("Time(ids[255]) = %v, want %v", got, start)
+	}
+
+	if got, want := g.Time(ids[256]), start.Add(10*time.Millisecond); !got.Equal(want) {
+		t.Errorf("Time(ids[256]) = %v, want %v", got, want)
+	}
+}
+
+func TestSonyflakeMachineIDRange(t *testing.T) {
+	if _, err := snowflake.NewSonyflake(0xFFFF); err != nil {
+		t.Errorf("NewSonyflake(0xFFFF) = %v", err)
+	}
+
+	if _, err := snowflake.NewSonyflake(1, snowflake.WithWorkerID(2)); err != nil {
+		t.Errorf("NewSonyflake with WithWorkerID = %v", err)
+	}
+}
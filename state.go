@@ -0,0 +1,177 @@
+// MIT License
+
+// Copyright (c) 2022 Project-Sparrow
+// Copyright (c) 2023 Kelwing <kelwing@kelnet.org>
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package snowflake
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrInvalidState is returned by RestoreState for state that is corrupt
+// or was saved by a Generator with a different configuration.
+var ErrInvalidState = errors.New("invalid generator state")
+
+const (
+	stateVersion = 1
+	stateSize    = 42
+)
+
+// State returns the last timestamp and sequence issued by the Generator,
+// along with its configuration, so it can be checkpointed and passed to
+// RestoreState after a restart. Timestamps used by GenerateAt are not included.
+func (g *Generator) State() ([]byte, error) {
+	last, seq := g.watermark()
+
+	b := make([]byte, 0, stateSize)
+	b = append(b, 'S', 'F', stateVersion)
+	b = binary.BigEndian.AppendUint64(b, uint64(g.epoch.Unix()))
+	b = binary.BigEndian.AppendUint32(b, uint32(g.epoch.Nanosecond()))
+	b = binary.BigEndian.AppendUint64(b, uint64(g.unit))
+	b = append(b, g.layout.TimestampBits, g.layout.WorkerBits, g.layout.ProcessBits, g.layout.SequenceBits)
+	if g.layout.SequenceHigh {
+		b = append(b, 1)
+	} else {
+		b = append(b, 0)
+	}
+	b = binary.BigEndian.AppendUint16(b, g.workerID)
+	b = binary.BigEndian.AppendUint16(b, g.processID)
+	b = binary.BigEndian.AppendUint64(b, uint64(last))
+	b = binary.BigEndian.AppendUint16(b, seq)
+
+	return b, nil
+}
+
+// RestoreState makes the Generator treat the timestamp saved in state
+// by State as fully used, so it never issues a Snowflake at or before it.
+// If the clock is behind the restored timestamp, generation follows the
+// Generator's Policy as if the clock had moved backwards.
+// State older than what the Generator has already issued is ignored.
+// An error wrapping ErrInvalidState is returned if state is corrupt or
+// was saved by a Generator with a different epoch, time unit, Layout,
+// worker ID or process ID.
+func (g *Generator) RestoreState(state []byte) error {
+	if len(state) != stateSize {
+		return fmt.Errorf("%w: %d bytes, want %d", ErrInvalidState, len(state), stateSize)
+	}
+
+	if state[0] != 'S' || state[1] != 'F' {
+		return fmt.Errorf("%w: bad header %q", ErrInvalidState, state[:2])
+	}
+
+	if state[2] != stateVersion {
+		return fmt.Errorf("%w: unsupported version %d", ErrInvalidState, state[2])
+	}
+
+	b := state[3:]
+	epoch := time.Unix(int64(binary.BigEndian.Uint64(b)), int64(binary.BigEndian.Uint32(b[8:])))
+	unit := time.Duration(binary.BigEndian.Uint64(b[12:]))
+	layout := Layout{TimestampBits: b[20], WorkerBits: b[21], ProcessBits: b[22], SequenceBits: b[23], SequenceHigh: b[24] == 1}
+	worker := binary.BigEndian.Uint16(b[25:])
+	process := binary.BigEndian.Uint16(b[27:])
+	last := int64(binary.BigEndian.Uint64(b[29:]))
+	seq := binary.BigEndian.Uint16(b[37:])
+
+	switch {
+	case !epoch.Equal(g.epoch):
+		return fmt.Errorf("%w: saved with epoch %v, generator uses %v", ErrInvalidState, epoch, g.epoch)
+	case unit != g.unit:
+		return fmt.Errorf("%w: saved with time unit %v, generator uses %v", ErrInvalidState, unit, g.unit)
+	case layout != g.layout:
+		return fmt.Errorf("%w: saved with layout %v, generator uses %v", ErrInvalidState, layout, g.layout)
+	case worker != g.workerID || process != g.processID:
+		return fmt.Errorf("%w: saved by worker %d process %d, generator is worker %d process %d", ErrInvalidState, worker, process, g.workerID, g.processID)
+	case last < -1 || (last >= 0 && uint64(last) > g.layout.MaxTimestamp()):
+		return fmt.Errorf("%w: timestamp %d out of range", ErrInvalidState, last)
+	case seq > g.layout.MaxSequence():
+		return fmt.Errorf("%w: sequence %d out of range", ErrInvalidState, seq)
+	}
+
+	g.restore(last)
+
+	return nil
+}
+
+// watermark returns the last timestamp issued by g and the highest
+// sequence issued with it, or -1 if g has not generated anything.
+func (g *Generator) watermark() (int64, uint16) {
+	if g.lockFree {
+		state := g.packed.Load()
+		return int64(state>>16) - 1, uint16(state)
+	}
+
+	if g.shards != nil {
+		last, seq := int64(-1), uint16(0)
+		for _, sh := range g.shards {
+			l, s := sh.watermark()
+			if l > last || (l == last && s > seq) {
+				last, seq = l, s
+			}
+		}
+
+		return last, seq
+	}
+
+	g.mtx.Lock()
+	defer g.mtx.Unlock()
+
+	if g.lastTimestamp < 0 {
+		return -1, 0
+	}
+
+	return g.lastTimestamp, g.seqBase | g.sequence
+}
+
+// restore marks every sequence of timestamp last as used,
+// unless g has already issued a later timestamp.
+func (g *Generator) restore(last int64) {
+	if last < 0 {
+		return
+	}
+
+	if g.lockFree {
+		for {
+			state := g.packed.Load()
+			if int64(state>>16)-1 >= last {
+				return
+			}
+
+			if g.packed.CompareAndSwap(state, uint64(last+1)<<16|uint64(g.seqMax)) {
+				return
+			}
+		}
+	}
+
+	for _, sh := range g.shards {
+		sh.restore(last)
+	}
+
+	g.mtx.Lock()
+	defer g.mtx.Unlock()
+
+	if last > g.lastTimestamp {
+		g.lastTimestamp, g.sequence = last, g.seqMax
+	}
+}
@@ -0,0 +1,185 @@
+// MIT License
+
+// Copyright (c) 2022 Project-Sparrow
+// Copyright (c) 2023 Kelwing <kelwing@kelnet.org>
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package snowflake_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"wumpgo.dev/snowflake"
+)
+
+func TestRestoreStateRestart(t *testing.T) {
+	epoch := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	modes := []struct {
+		name string
+		opts []snowflake.Option
+	}{
+		{"default", nil},
+		{"shards", []snowflake.Option{snowflake.WithShards(4)}},
+		{"lock-free", []snowflake.Option{snowflake.WithLockFree()}},
+	}
+
+	for _, m := range modes {
+		t.Run(m.name, func(t *testing.T) {
+			clock := snowflake.NewFakeClock(epoch.Add(time.Hour))
+			opts := append([]snowflake.Option{snowflake.WithEpoch(epoch), snowflake.WithWorkerID(3), snowflake.WithClock(clock)}, m.opts...)
+
+			before, err := snowflake.New(opts...)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			seen := make(map[snowflake.Snowflake]bool)
+			var highest snowflake.Snowflake
+			for _, s := range before.GenerateN(100) {
+				seen[s] = true
+				highest = max(highest, s)
+			}
+
+			state, err := before.State()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			// Restart within the same millisecond.
+			after, err := snowflake.New(opts...)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if err := after.RestoreState(state); err != nil {
+				t.Fatalf("RestoreState() = %v", err)
+			}
+
+			for _, s := range after.GenerateN(100) {
+				if seen[s] {
+					t.Fatalf("duplicate Snowflake %d after restart", s)
+				}
+
+				if s <= highest {
+					t.Fatalf("Snowflake %d after restart is not after %d", s, highest)
+				}
+			}
+		})
+	}
+}
+
+func TestRestoreStateClockBehind(t *testing.T) {
+	epoch := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	clock := snowflake.NewFakeClock(epoch.Add(time.Hour))
+	opts := []snowflake.Option{snowflake.WithEpoch(epoch), snowflake.WithClock(clock), snowflake.WithClockBackwardsPolicy(snowflake.PolicyError)}
+
+	before, err := snowflake.New(opts...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	before.Generate()
+
+	state, err := before.State()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	clock.Set(clock.Now().Add(-time.Second))
+
+	after, err := snowflake.New(opts...)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := after.RestoreState(state); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := after.GenerateContext(context.Background()); !errors.Is(err, snowflake.ErrClockBackwards) {
+		t.Errorf("GenerateContext() = %v, want ErrClockBackwards", err)
+	}
+}
+
+func TestRestoreStateInvalid(t *testing.T) {
+	epoch := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	clock := snowflake.NewFakeClock(epoch.Add(time.Hour))
+
+	g, err := snowflake.New(snowflake.WithEpoch(epoch), snowflake.WithWorkerID(1), snowflake.WithClock(clock))
+	if err != nil {
+		t.Fatal(err)
+	}
+	g.Generate()
+
+	state, err := g.State()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	corrupt := func(i int, b byte) []byte {
+		c := append([]byte(nil), state...)
+		c[i] = b
+		return c
+	}
+
+	others := []struct {
+		name string
+		opts []snowflake.Option
+	}{
+		{"epoch", []snowflake.Option{snowflake.WithEpoch(epoch.Add(time.Second)), snowflake.WithWorkerID(1)}},
+		{"worker", []snowflake.Option{snowflake.WithEpoch(epoch), snowflake.WithWorkerID(2)}},
+		{"unit", []snowflake.Option{snowflake.WithEpoch(epoch), snowflake.WithWorkerID(1), snowflake.WithTimeUnit(10 * time.Millisecond)}},
+	}
+
+	tests := []struct {
+		name  string
+		state []byte
+	}{
+		{"empty", nil},
+		{"truncated", state[:len(state)-1]},
+		{"bad header", corrupt(0, 'X')},
+		{"bad version", corrupt(2, 9)},
+		{"bad sequence", corrupt(len(state)-2, 0xFF)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := g.RestoreState(tt.state); !errors.Is(err, snowflake.ErrInvalidState) {
+				t.Errorf("RestoreState() = %v, want ErrInvalidState", err)
+			}
+		})
+	}
+
+	for _, o := range others {
+		t.Run("other "+o.name, func(t *testing.T) {
+			other, err := snowflake.New(append(o.opts, snowflake.WithClock(clock))...)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if err := other.RestoreState(state); !errors.Is(err, snowflake.ErrInvalidState) {
+				t.Errorf("RestoreState() = %v, want ErrInvalidState", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,94 @@
+// MIT License
+
+// Copyright (c) 2022 Project-Sparrow
+// Copyright (c) 2023 Kelwing <kelwing@kelnet.org>
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package snowflake
+
+import "sync/atomic"
+
+// Stats holds counters describing the work done by a Generator.
+type Stats struct {
+	// Generated is the number of Snowflakes generated.
+	Generated uint64
+	// Exhausted is the number of time units whose sequence reached its maximum.
+	Exhausted uint64
+	// Waits is the number of times generation waited for the clock to catch up,
+	// after the sequence was exhausted or the clock moved backwards.
+	Waits uint64
+	// MaxSequence is the highest count of Snowflakes generated in one time
+	// unit so far, less one, whatever sequence numbers they were given;
+	// with WithShards, the highest of any one shard. It reaches the
+	// Layout's MaxSequence when a time unit is exhausted.
+	MaxSequence uint16
+}
+
+// generatorStats holds the counters behind Stats.
+// They are atomic so Stats does not contend for the Generator's lock.
+type generatorStats struct {
+	generated   atomic.Uint64
+	exhausted   atomic.Uint64
+	waits       atomic.Uint64
+	maxSequence atomic.Uint32
+}
+
+// record counts a Snowflake generated as the n-th of its time unit,
+// counting from zero, which exhausted the sequence if last is true.
+func (st *generatorStats) record(n uint16, last bool) {
+	st.generated.Add(1)
+
+	if last {
+		st.exhausted.Add(1)
+	}
+
+	for {
+		prev := st.maxSequence.Load()
+		if uint32(n) <= prev || st.maxSequence.CompareAndSwap(prev, uint32(n)) {
+			return
+		}
+	}
+}
+
+// Stats returns a snapshot of the Generator's counters.
+// It may be called concurrently with generation.
+// With WithShards, the counters of every shard are added up.
+func (g *Generator) Stats() Stats {
+	st := g.stats.snapshot()
+	for _, sh := range g.shards {
+		s := sh.stats.snapshot()
+		st.Generated += s.Generated
+		st.Exhausted += s.Exhausted
+		st.Waits += s.Waits
+		st.MaxSequence = max(st.MaxSequence, s.MaxSequence)
+	}
+
+	return st
+}
+
+// snapshot loads the counters.
+func (st *generatorStats) snapshot() Stats {
+	return Stats{
+		Generated:   st.generated.Load(),
+		Exhausted:   st.exhausted.Load(),
+		Waits:       st.waits.Load(),
+		MaxSequence: uint16(st.maxSequence.Load()),
+	}
+}
@@ -0,0 +1,126 @@
+// MIT License
+
+// Copyright (c) 2022 Project-Sparrow
+// Copyright (c) 2023 Kelwing <kelwing@kelnet.org>
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package snowflake_test
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"wumpgo.dev/snowflake"
+)
+
+func TestStats(t *testing.T) {
+	epoch := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	clock := snowflake.NewFakeClock(epoch.Add(time.Hour))
+
+	g, err := snowflake.New(snowflake.WithEpoch(epoch), snowflake.WithClock(clock))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got := g.Stats(); got != (snowflake.Stats{}) {
+		t.Errorf("Stats() before generating = %+v, want zero", got)
+	}
+
+	var wg sync.WaitGroup
+	done := make(chan struct{})
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for {
+			select {
+			case <-done:
+				return
+			default:
+				g.Stats()
+			}
+		}
+	}()
+
+	g.GenerateN(3*4096 + 1)
+	close(done)
+	wg.Wait()
+
+	want := snowflake.Stats{Generated: 3*4096 + 1, Exhausted: 3, Waits: 3, MaxSequence: 4095}
+	if got := g.Stats(); got != want {
+		t.Errorf("Stats() = %+v, want %+v", got, want)
+	}
+}
+
+func TestStatsMaxSequence(t *testing.T) {
+	epoch := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	clock := snowflake.NewManualClock(epoch.Add(time.Hour))
+
+	g, err := snowflake.New(snowflake.WithEpoch(epoch), snowflake.WithClock(clock))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	g.GenerateN(10)
+	clock.Advance(time.Millisecond)
+	g.GenerateN(3)
+
+	if got := g.Stats(); got.MaxSequence != 9 || got.Exhausted != 0 || got.Waits != 0 {
+		t.Errorf("Stats() = %+v, want MaxSequence 9 and no exhaustion or waits", got)
+	}
+}
+
+func TestStatsMaxSequenceShards(t *testing.T) {
+	epoch := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	clock := snowflake.NewManualClock(epoch.Add(time.Hour))
+
+	g, err := snowflake.New(snowflake.WithEpoch(epoch), snowflake.WithClock(clock), snowflake.WithShards(4))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Whichever shard serves them, the sequence numbers carry its base.
+	for i := 0; i < 4; i++ {
+		g.Generate()
+	}
+
+	if got := g.Stats(); got.MaxSequence > 3 {
+		t.Errorf("Stats() = %+v, want MaxSequence at most 3", got)
+	}
+}
+
+func TestStatsMaxSequenceRandomStart(t *testing.T) {
+	epoch := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	clock := snowflake.NewManualClock(epoch.Add(time.Hour))
+
+	g, err := snowflake.New(snowflake.WithEpoch(epoch), snowflake.WithClock(clock), snowflake.WithRandomSequenceStart())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 0; i < 20; i++ {
+		g.GenerateN(10)
+		clock.Advance(time.Millisecond)
+	}
+
+	if got := g.Stats(); got.MaxSequence != 9 {
+		t.Errorf("Stats() = %+v, want MaxSequence 9", got)
+	}
+}
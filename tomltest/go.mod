@@ -0,0 +1,11 @@
+module wumpgo.dev/snowflake/tomltest
+
+go 1.21
+
+replace wumpgo.dev/snowflake => ../
+
+require (
+	github.com/BurntSushi/toml v1.6.0
+	github.com/pelletier/go-toml/v2 v2.2.2
+	wumpgo.dev/snowflake v0.0.0
+)
@@ -0,0 +1,159 @@
+// MIT License
+
+// Copyright (c) 2022 Project-Sparrow
+// Copyright (c) 2023 Kelwing <kelwing@kelnet.org>
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package tomltest_test
+
+import (
+	"bytes"
+	"maps"
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/BurntSushi/toml"
+	gotoml "github.com/pelletier/go-toml/v2"
+	"wumpgo.dev/snowflake"
+)
+
+type config struct {
+	ID     snowflake.Snowflake     `toml:"id"`
+	Parent snowflake.NullSnowflake `toml:"parent"`
+}
+
+// decoders decode a TOML document with each library.
+var decoders = map[string]func(doc string, v any) error{
+	"BurntSushi": func(doc string, v any) error {
+		_, err := toml.Decode(doc, v)
+		return err
+	},
+	"pelletier": func(doc string, v any) error {
+		return gotoml.Unmarshal([]byte(doc), v)
+	},
+}
+
+// encoders encode a value as TOML with each library.
+var encoders = map[string]func(v any) (string, error){
+	"BurntSushi": func(v any) (string, error) {
+		var buf bytes.Buffer
+		err := toml.NewEncoder(&buf).Encode(v)
+		return buf.String(), err
+	},
+	"pelletier": func(v any) (string, error) {
+		b, err := gotoml.Marshal(v)
+		return string(b), err
+	},
+}
+
+func TestDecode(t *testing.T) {
+	for _, tt := range []struct {
+		doc  string
+		want config
+	}{
+		// String form, as written by the encoders.
+		{`id = "175928847299117063"` + "\n" + `parent = "41771983423143937"`,
+			config{175928847299117063, snowflake.NewNullSnowflake(41771983423143937, true)}},
+		{`id = "18446744073709551615"`, config{ID: math.MaxUint64}},
+		// Integer form, which TOML limits to math.MaxInt64.
+		{"id = 175928847299117063\nparent = 41771983423143937",
+			config{175928847299117063, snowflake.NewNullSnowflake(41771983423143937, true)}},
+		{"id = 9223372036854775807", config{ID: math.MaxInt64}},
+		{"parent = 0", config{Parent: snowflake.NewNullSnowflake(0, true)}},
+		// TOML has no null, so an absent key leaves a NullSnowflake invalid,
+		// as does the "null" string written for one.
+		{"id = 1", config{ID: 1}},
+		{`id = 1` + "\n" + `parent = "null"`, config{ID: 1}},
+	} {
+		for name, decode := range decoders {
+			var got config
+			if err := decode(tt.doc, &got); err != nil || got != tt.want {
+				t.Errorf("%s: decoding %q = %+v, %v, want %+v", name, tt.doc, got, err, tt.want)
+			}
+		}
+	}
+}
+
+func TestDecodeInvalid(t *testing.T) {
+	for _, tt := range []struct {
+		doc  string
+		want error // nil for any error
+	}{
+		{`id = "abc"`, snowflake.ErrSyntax},
+		{`id = ""`, snowflake.ErrSyntax},
+		{`id = "-5"`, snowflake.ErrNegative},
+		{`id = -5`, nil}, // pelletier decodes integers itself, not with UnmarshalText
+		{`id = "18446744073709551616"`, snowflake.ErrOverflow},
+		{`parent = "abc"`, snowflake.ErrSyntax},
+		{`id = 1.5`, snowflake.ErrSyntax},
+	} {
+		for name, decode := range decoders {
+			var got config
+			// Neither library wraps the error, so look for its message.
+			if err := decode(tt.doc, &got); err == nil || tt.want != nil && !strings.Contains(err.Error(), tt.want.Error()) {
+				t.Errorf("%s: decoding %q = %+v, %v, want %v", name, tt.doc, got, err, tt.want)
+			}
+		}
+	}
+}
+
+func TestEncode(t *testing.T) {
+	for _, tt := range []struct {
+		in   config
+		want map[string]any
+	}{
+		{config{175928847299117063, snowflake.NewNullSnowflake(41771983423143937, true)},
+			map[string]any{"id": "175928847299117063", "parent": "41771983423143937"}},
+		{config{ID: math.MaxUint64},
+			map[string]any{"id": "18446744073709551615", "parent": "null"}},
+	} {
+		for name, encode := range encoders {
+			doc, err := encode(tt.in)
+			if err != nil {
+				t.Errorf("%s: encoding %+v = %v", name, tt.in, err)
+				continue
+			}
+
+			// Both are written as TOML strings, whatever the quoting style.
+			var got map[string]any
+			if _, err := toml.Decode(doc, &got); err != nil || !maps.Equal(got, tt.want) {
+				t.Errorf("%s: encoding %+v = %q, which holds %v, want %v", name, tt.in, doc, got, tt.want)
+			}
+
+			for name, decode := range decoders {
+				var out config
+				if err := decode(doc, &out); err != nil || out != tt.in {
+					t.Errorf("%s: decoding %q = %+v, %v, want %+v", name, doc, out, err, tt.in)
+				}
+			}
+		}
+	}
+
+	// An invalid NullSnowflake with the zero Snowflake can be left out.
+	type optional struct {
+		Parent snowflake.NullSnowflake `toml:"parent,omitempty"`
+	}
+
+	doc, err := encoders["BurntSushi"](optional{})
+	if err != nil || doc != "" {
+		t.Errorf("BurntSushi: encoding an omitempty null = %q, %v, want nothing", doc, err)
+	}
+}
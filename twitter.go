@@ -0,0 +1,49 @@
+// MIT License
+
+// Copyright (c) 2022 Project-Sparrow
+// Copyright (c) 2023 Kelwing <kelwing@kelnet.org>
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package snowflake
+
+// LayoutTwitter is the bit layout of Twitter's original Snowflake scheme:
+// a 41-bit timestamp, a 5-bit datacenter ID, a 5-bit worker ID and a 12-bit sequence.
+// The datacenter ID occupies the worker field and the worker ID the process field.
+var LayoutTwitter = Layout{TimestampBits: 41, WorkerBits: 5, ProcessBits: 5, SequenceBits: 12}
+
+// NewTwitter creates a Generator compatible with Twitter's original scheme,
+// using LayoutTwitter and EpochTwitter.
+// opts are applied afterwards, so WithEpoch may be used to pick another epoch.
+func NewTwitter(datacenterID, workerID uint8, opts ...Option) (*Generator, error) {
+	g := newGenerator()
+	g.epoch = EpochTwitter
+	g.layout = LayoutTwitter
+	g.workerID = uint16(datacenterID)
+	g.processID = uint16(workerID)
+
+	return g.configure(opts)
+}
+
+// DatacenterID decodes the datacenter ID of a Snowflake generated by a Generator
+// from NewTwitter. It is the field reported as WorkerID by Deconstruct,
+// while Twitter's worker ID is reported as ProcessID.
+func (g *Generator) DatacenterID(s Snowflake) uint8 {
+	return uint8(g.layout.WorkerID(s))
+}
@@ -0,0 +1,86 @@
+// MIT License
+
+// Copyright (c) 2022 Project-Sparrow
+// Copyright (c) 2023 Kelwing <kelwing@kelnet.org>
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package snowflake_test
+
+import (
+	"testing"
+	"time"
+
+	"wumpgo.dev/snowflake"
+)
+
+func TestTwitterDecode(t *testing.T) {
+	g, err := snowflake.NewTwitter(0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name       string
+		id         snowflake.Snowflake
+		created    time.Time
+		datacenter uint8
+		worker     uint16
+	}{
+		// @BarackObama, "Four more years."
+		{"four more years", 266031293945503744, time.Date(2012, time.November, 7, 4, 16, 0, 0, time.UTC), 1, 6},
+		// @TheEllenShow, the Oscars selfie.
+		{"oscars selfie", 440322224407314432, time.Date(2014, time.March, 3, 3, 6, 0, 0, time.UTC), 1, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Tweets show their creation time to the minute.
+			if got := g.Time(tt.id).Truncate(time.Minute); !got.Equal(tt.created) {
+				t.Errorf("Time() = %v, want %v", got, tt.created)
+			}
+
+			if got := g.DatacenterID(tt.id); got != tt.datacenter {
+				t.Errorf("DatacenterID() = %d, want %d", got, tt.datacenter)
+			}
+
+			if got := g.Deconstruct(tt.id).ProcessID; got != tt.worker {
+				t.Errorf("Deconstruct().ProcessID = %d, want %d", got, tt.worker)
+			}
+		})
+	}
+}
+
+func TestTwitterGenerate(t *testing.T) {
+	now := time.Date(2012, time.November, 7, 4, 16, 17, 756e6, time.UTC)
+	clock := snowflake.NewFakeClock(now)
+
+	g, err := snowflake.NewTwitter(1, 6, snowflake.WithClock(clock))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if s := g.Generate(); s != 266031293945503744 {
+		t.Errorf("Generate() = %d, want 266031293945503744", s)
+	}
+
+	if _, err := snowflake.NewTwitter(32, 0); err == nil {
+		t.Error("NewTwitter(32, 0) succeeded, want error")
+	}
+}
@@ -0,0 +1,111 @@
+// MIT License
+
+// Copyright (c) 2022 Project-Sparrow
+// Copyright (c) 2023 Kelwing <kelwing@kelnet.org>
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package snowflake
+
+import (
+	"fmt"
+	"time"
+)
+
+const (
+	// ulidLen is the length of a ULID in Crockford's base32.
+	ulidLen = 26
+
+	// ulidTimeLen is the number of characters holding the ULID's timestamp.
+	ulidTimeLen = 10
+
+	// maxULIDTime is the largest millisecond timestamp a ULID can hold.
+	maxULIDTime = 1<<48 - 1
+)
+
+// ToULID returns a ULID with the timestamp of the Snowflake, relative to the
+// epoch passed to Init, and the whole Snowflake in the low 64 bits of its
+// 80 bits of entropy, with the high 16 bits zero. The last 16 characters
+// are therefore "000" followed by the base32 form of the Snowflake
+// zero-padded to 13 characters, and ULIDs made from Snowflakes created in
+// the same millisecond sort in the same order as the Snowflakes.
+//
+// It returns an error if the timestamp is before the Unix epoch or
+// beyond the 48 bits of a ULID timestamp.
+func (s Snowflake) ToULID() (string, error) {
+	ms := s.Time().UnixMilli()
+	if ms < 0 || ms > maxULIDTime {
+		return "", fmt.Errorf("%w: time of %d is outside the ULID range", ErrTimestampOverflow, s)
+	}
+
+	// The 128 bits of the ULID, as hi and lo.
+	hi, lo := uint64(ms)<<16, uint64(s)
+
+	var buf [ulidLen]byte
+	for i := ulidLen - 1; i >= 0; i-- {
+		buf[i] = crockfordAlphabet[lo&31]
+		lo = lo>>5 | hi<<59
+		hi >>= 5
+	}
+
+	return string(buf[:]), nil
+}
+
+// TimeFromULID returns the timestamp of a ULID, in UTC.
+// The ULID may be in either case, and errors wrap ErrSyntax or ErrOverflow.
+func TimeFromULID(s string) (time.Time, error) {
+	if len(s) > ulidLen {
+		return time.Time{}, fmt.Errorf("%w: ULID of %d characters, want %d", ErrTooLong, len(s), ulidLen)
+	}
+
+	if len(s) < ulidLen {
+		return time.Time{}, fmt.Errorf("%w: ULID %q has %d characters, want %d", ErrSyntax, s, len(s), ulidLen)
+	}
+
+	var ms int64
+	for i := 0; i < len(s); i++ {
+		d := crockfordDigits[s[i]]
+		if d == 0xFF {
+			return time.Time{}, fmt.Errorf("%w: ULID %q has invalid character %q", ErrSyntax, s, s[i])
+		}
+
+		if i < ulidTimeLen {
+			ms = ms<<5 | int64(d)
+		}
+	}
+
+	// The first character holds only the top 3 bits of its 5.
+	if ms > maxULIDTime {
+		return time.Time{}, fmt.Errorf("%w: ULID %q", ErrOverflow, s)
+	}
+
+	return time.UnixMilli(ms).UTC(), nil
+}
+
+// FromULIDTimestamp returns the smallest Snowflake with the timestamp of
+// the ULID s, as FirstForTime does, for querying Snowflakes created at or
+// after the ULID.
+func FromULIDTimestamp(s string) (Snowflake, error) {
+	t, err := TimeFromULID(s)
+	if err != nil {
+		return 0, err
+	}
+
+	return FirstForTime(t)
+}
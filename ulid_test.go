@@ -0,0 +1,160 @@
+// MIT License
+
+// Copyright (c) 2022 Project-Sparrow
+// Copyright (c) 2023 Kelwing <kelwing@kelnet.org>
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package snowflake_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"wumpgo.dev/snowflake"
+)
+
+func TestToULID(t *testing.T) {
+	defer snowflake.ResetDefault()
+	snowflake.InitDiscord(0, 0)
+
+	s := snowflake.Snowflake(175928847299117063)
+	got, err := s.ToULID()
+	if want := "01AHKE86R400004W86BB0G4007"; err != nil || got != want {
+		t.Fatalf("ToULID() = %q, %v, want %q", got, err, want)
+	}
+
+	if want := "000" + strings.Repeat("0", 13-len(s.EncodeBase32())) + s.EncodeBase32(); got[10:] != want {
+		t.Errorf("ToULID() entropy = %q, want %q", got[10:], want)
+	}
+
+	ts, err := snowflake.TimeFromULID(got)
+	if err != nil || !ts.Equal(s.Time()) {
+		t.Errorf("TimeFromULID(%q) = %v, %v, want %v", got, ts, err, s.Time())
+	}
+
+	// ULIDs of Snowflakes from the same millisecond sort like the Snowflakes.
+	a, _ := (s + 1).ToULID()
+	b, _ := (s + 1<<12).ToULID()
+	if !(got < a && a < b) {
+		t.Errorf("ULIDs %q, %q and %q are out of order", got, a, b)
+	}
+}
+
+func TestToULIDRange(t *testing.T) {
+	defer snowflake.ResetDefault()
+
+	// The Unix epoch itself, the default before Init.
+	if got, err := snowflake.Snowflake(0).ToULID(); err != nil || got != "00000000000000000000000000" {
+		t.Errorf("ToULID(0) = %q, %v", got, err)
+	}
+
+	snowflake.Init(time.Date(1969, 1, 1, 0, 0, 0, 0, time.UTC), 0, 0)
+	if _, err := snowflake.Snowflake(0).ToULID(); !errors.Is(err, snowflake.ErrTimestampOverflow) {
+		t.Errorf("ToULID() before the Unix epoch = %v, want ErrTimestampOverflow", err)
+	}
+}
+
+func TestTimeFromULID(t *testing.T) {
+	for _, tt := range []struct {
+		in   string
+		want int64
+	}{
+		{"01ARZ3NDEKTSV4RRFFQ69G5FAV", 1469922850259}, // from the ULID spec
+		{"01arz3ndektsv4rrffq69g5fav", 1469922850259},
+		{"00000000000000000000000000", 0},
+		{"7ZZZZZZZZZZZZZZZZZZZZZZZZZ", 1<<48 - 1},
+		{"0000000001ZZZZZZZZZZZZZZZZ", 1},
+	} {
+		got, err := snowflake.TimeFromULID(tt.in)
+		if err != nil || got.UnixMilli() != tt.want || got.Location() != time.UTC {
+			t.Errorf("TimeFromULID(%q) = %v, %v, want %d ms in UTC", tt.in, got, err, tt.want)
+		}
+	}
+
+	for _, tt := range []struct {
+		in  string
+		err error
+	}{
+		{"", snowflake.ErrSyntax},
+		{"01ARZ3NDEKTSV4RRFFQ69G5FA", snowflake.ErrSyntax},
+		{"01ARZ3NDEKTSV4RRFFQ69G5FAVV", snowflake.ErrTooLong},
+		{"01ARZ3NDEKTSV4RRFFQ69G5FAU", snowflake.ErrSyntax},
+		{"01ARZ3NDEK-SV4RRFFQ69G5FAV", snowflake.ErrSyntax},
+		{"8ZZZZZZZZZZZZZZZZZZZZZZZZZ", snowflake.ErrOverflow},
+	} {
+		if got, err := snowflake.TimeFromULID(tt.in); !errors.Is(err, tt.err) {
+			t.Errorf("TimeFromULID(%q) = %v, %v, want %v", tt.in, got, err, tt.err)
+		}
+	}
+}
+
+func TestFromULIDTimestamp(t *testing.T) {
+	defer snowflake.ResetDefault()
+	snowflake.InitDiscord(0, 0)
+
+	got, err := snowflake.FromULIDTimestamp("01ARZ3NDEKTSV4RRFFQ69G5FAV")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if want := time.UnixMilli(1469922850259); !got.Time().Equal(want) || got.WorkerID() != 0 || got.ProcessID() != 0 || got.Sequence() != 0 {
+		t.Errorf("FromULIDTimestamp() = %v, want the first Snowflake at %v", got.DebugString(), want)
+	}
+
+	// Before the Discord epoch.
+	if _, err := snowflake.FromULIDTimestamp("00000000000000000000000000"); err == nil {
+		t.Error("FromULIDTimestamp() before the epoch succeeded")
+	}
+
+	if _, err := snowflake.FromULIDTimestamp("short"); !errors.Is(err, snowflake.ErrSyntax) {
+		t.Errorf("FromULIDTimestamp(%q) = %v, want ErrSyntax", "short", err)
+	}
+}
+
+func TestULIDTimestampRoundTrip(t *testing.T) {
+	defer snowflake.ResetDefault()
+	snowflake.InitDiscord(0, 0)
+
+	start := time.Date(2015, 2, 1, 0, 0, 0, 0, time.UTC)
+	for i := int64(0); i < 1000; i++ {
+		ts := start.Add(time.Duration(i*i*i*4001) * time.Millisecond)
+		s, err := snowflake.Compose(ts, uint8(i%32), uint8(i/32%32), uint16(i))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		u, err := s.ToULID()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		got, err := snowflake.TimeFromULID(u)
+		if err != nil || !got.Equal(ts) {
+			t.Fatalf("TimeFromULID(ToULID(%d)) = %v, %v, want %v", s, got, err, ts)
+		}
+
+		first, err := snowflake.FromULIDTimestamp(u)
+		if err != nil || first > s || !first.Time().Equal(ts) {
+			t.Fatalf("FromULIDTimestamp(ToULID(%d)) = %d, %v", s, first, err)
+		}
+	}
+}
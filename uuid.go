@@ -0,0 +1,73 @@
+// MIT License
+
+// Copyright (c) 2022 Project-Sparrow
+// Copyright (c) 2023 Kelwing <kelwing@kelnet.org>
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package snowflake
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+)
+
+// ErrNotSnowflakeUUID is returned by FromUUID for UUIDs not made by ToUUID.
+var ErrNotSnowflakeUUID = errors.New("UUID does not hold a snowflake")
+
+// ToUUID returns a version 8 UUID, as defined by RFC 9562, that holds the
+// Snowflake's 64 bits so that FromUUID can recover it. In big-endian order:
+//
+//	bytes 0-5    bits 63-16 of the Snowflake
+//	byte 6       version 8 in the high nibble, bits 15-12 in the low nibble
+//	byte 7       bits 11-4
+//	byte 8       variant 0b10 in the top two bits, then bits 3-0, then 0b00
+//	bytes 9-15   zero
+//
+// The UUIDs sort in the same order as the Snowflakes, byte by byte or as
+// strings. The result converts directly to types such as google/uuid's UUID:
+//
+//	u := uuid.UUID(s.ToUUID())
+//	s, err := snowflake.FromUUID(u)
+func (s Snowflake) ToUUID() [16]byte {
+	var u [16]byte
+	binary.BigEndian.PutUint64(u[:], uint64(s)>>16<<16)
+	u[6] = 0x80 | byte(s>>12)&0x0F
+	u[7] = byte(s >> 4)
+	u[8] = 0x80 | byte(s)&0x0F<<2
+
+	return u
+}
+
+// FromUUID returns the Snowflake held by a UUID made by ToUUID.
+// It returns an error wrapping ErrNotSnowflakeUUID if the version, variant
+// or zero bits do not match.
+func FromUUID(u [16]byte) (Snowflake, error) {
+	if u[6]&0xF0 != 0x80 || u[8]&0xC3 != 0x80 || binary.BigEndian.Uint64(u[8:])&(1<<56-1) != 0 {
+		return 0, fmt.Errorf("%w: %x", ErrNotSnowflakeUUID, u)
+	}
+
+	s := binary.BigEndian.Uint64(u[:]) >> 16 << 16
+	s |= uint64(u[6]&0x0F) << 12
+	s |= uint64(u[7]) << 4
+	s |= uint64(u[8]>>2) & 0x0F
+
+	return Snowflake(s), nil
+}
@@ -0,0 +1,154 @@
+// MIT License
+
+// Copyright (c) 2022 Project-Sparrow
+// Copyright (c) 2023 Kelwing <kelwing@kelnet.org>
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package snowflake_test
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"math"
+	"math/rand"
+	"slices"
+	"testing"
+	"testing/quick"
+
+	"wumpgo.dev/snowflake"
+)
+
+// googleUUID has the same definition as github.com/google/uuid.UUID.
+type googleUUID [16]byte
+
+// String returns the canonical form, as google/uuid does.
+func (u googleUUID) String() string {
+	return fmt.Sprintf("%x-%x-%x-%x-%x", u[0:4], u[4:6], u[6:8], u[8:10], u[10:])
+}
+
+// Version returns the UUID version, as google/uuid does.
+func (u googleUUID) Version() byte { return u[6] >> 4 }
+
+func TestUUID(t *testing.T) {
+	for _, tt := range []struct {
+		s    snowflake.Snowflake
+		want string
+	}{
+		{0, "00000000-0000-8000-8000-000000000000"},
+		{1, "00000000-0000-8000-8400-000000000000"},
+		{0xF, "00000000-0000-8000-bc00-000000000000"},
+		{0x10, "00000000-0000-8001-8000-000000000000"},
+		{0x0123456789abcdef, "01234567-89ab-8cde-bc00-000000000000"},
+		{math.MaxUint64, "ffffffff-ffff-8fff-bc00-000000000000"},
+	} {
+		u := googleUUID(tt.s.ToUUID())
+		if got := u.String(); got != tt.want {
+			t.Errorf("ToUUID(%#x) = %s, want %s", uint64(tt.s), got, tt.want)
+		}
+
+		if u.Version() != 8 {
+			t.Errorf("ToUUID(%#x) has version %d, want 8", uint64(tt.s), u.Version())
+		}
+
+		if got, err := snowflake.FromUUID(u); err != nil || got != tt.s {
+			t.Errorf("FromUUID(%s) = %#x, %v, want %#x", u, uint64(got), err, uint64(tt.s))
+		}
+	}
+}
+
+func TestUUIDRoundTrip(t *testing.T) {
+	roundTrip := func(v uint64) bool {
+		got, err := snowflake.FromUUID(snowflake.Snowflake(v).ToUUID())
+		return err == nil && got == snowflake.Snowflake(v)
+	}
+
+	if err := quick.Check(roundTrip, nil); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestFromUUIDInvalid(t *testing.T) {
+	valid := snowflake.Snowflake(175928847299117063).ToUUID()
+
+	for _, tt := range []struct {
+		name string
+		edit func(u *[16]byte)
+	}{
+		{"version 4", func(u *[16]byte) { u[6] = 0x40 | u[6]&0x0F }},
+		{"version 7", func(u *[16]byte) { u[6] = 0x70 | u[6]&0x0F }},
+		{"NCS variant", func(u *[16]byte) { u[8] &^= 0x80 }},
+		{"Microsoft variant", func(u *[16]byte) { u[8] |= 0x40 }},
+		{"low bits of byte 8", func(u *[16]byte) { u[8] |= 0x01 }},
+		{"byte 9", func(u *[16]byte) { u[9] = 1 }},
+		{"byte 15", func(u *[16]byte) { u[15] = 1 }},
+		{"nil UUID", func(u *[16]byte) { *u = [16]byte{} }},
+		{"max UUID", func(u *[16]byte) {
+			*u = [16]byte{0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff}
+		}},
+		{"random v4", func(u *[16]byte) {
+			*u = [16]byte{0x6b, 0xa7, 0xb8, 0x10, 0x9d, 0xad, 0x41, 0xd1, 0x80, 0xb4, 0x00, 0xc0, 0x4f, 0xd4, 0x30, 0xc8}
+		}},
+	} {
+		u := valid
+		tt.edit(&u)
+
+		if got, err := snowflake.FromUUID(u); !errors.Is(err, snowflake.ErrNotSnowflakeUUID) {
+			t.Errorf("FromUUID(%s) = %d, %v, want ErrNotSnowflakeUUID", tt.name, got, err)
+		}
+	}
+}
+
+func TestUUIDSortOrder(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	sample := []snowflake.Snowflake{0, 1, 15, 16, math.MaxUint64}
+	for i := 0; i < 1000; i++ {
+		sample = append(sample, snowflake.Snowflake(r.Uint64()>>r.Intn(64)))
+	}
+	r.Shuffle(len(sample), func(i, j int) { sample[i], sample[j] = sample[j], sample[i] })
+
+	uuids := make([][16]byte, len(sample))
+	for i, s := range sample {
+		uuids[i] = s.ToUUID()
+	}
+
+	slices.SortFunc(uuids, func(a, b [16]byte) int { return bytes.Compare(a[:], b[:]) })
+	slices.Sort(sample)
+
+	for i, s := range sample {
+		if uuids[i] != s.ToUUID() {
+			t.Fatalf("sorted UUID %d = %s, want %s", i, googleUUID(uuids[i]), googleUUID(s.ToUUID()))
+		}
+	}
+}
+
+func ExampleSnowflake_ToUUID() {
+	s := snowflake.Snowflake(175928847299117063)
+
+	// With github.com/google/uuid, uuid.UUID(s.ToUUID()) works the same way.
+	u := googleUUID(s.ToUUID())
+	fmt.Println(u)
+
+	back, err := snowflake.FromUUID(u)
+	fmt.Println(back, err)
+	// Output:
+	// 0271065a-c102-8000-9c00-000000000000
+	// 175928847299117063 <nil>
+}
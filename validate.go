@@ -0,0 +1,90 @@
+// MIT License
+
+// Copyright (c) 2022 Project-Sparrow
+// Copyright (c) 2023 Kelwing <kelwing@kelnet.org>
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package snowflake
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"time"
+)
+
+var (
+	// ErrZero is returned by Validate for the zero Snowflake.
+	ErrZero = errors.New("snowflake is zero")
+
+	// ErrInFuture is returned by Validate for Snowflakes whose timestamp
+	// is further in the future than allowed.
+	ErrInFuture = errors.New("snowflake timestamp is too far in the future")
+)
+
+// DefaultMaxFuture is how far into the future a Snowflake's timestamp
+// may be before Validate rejects it, unless overridden with WithMaxFuture.
+const DefaultMaxFuture = time.Minute
+
+type validateOptions struct {
+	maxFuture time.Duration
+}
+
+// ValidateOption configures the checks performed by Validate.
+type ValidateOption func(*validateOptions)
+
+// WithMaxFuture sets how far into the future a Snowflake's
+// timestamp may be before it is rejected.
+func WithMaxFuture(d time.Duration) ValidateOption {
+	return func(o *validateOptions) {
+		o.maxFuture = d
+	}
+}
+
+// Validate sanity-checks the Snowflake against the epoch passed to Init.
+// The returned error wraps ErrZero, ErrSignBit or ErrInFuture
+// depending on which check failed. ErrSignBit is for Snowflakes that do not
+// fit in a signed 64-bit integer, which many databases and languages store
+// them as; with DefaultLayout and its millisecond time units that is about
+// 69 years after the epoch.
+// There is no check for timestamps before the epoch: the timestamp is an
+// unsigned offset from the epoch, so no Snowflake encodes one. A negative
+// offset forced into the timestamp bits sets the sign bit and is reported
+// as ErrSignBit.
+func (s Snowflake) Validate(opts ...ValidateOption) error {
+	o := validateOptions{maxFuture: DefaultMaxFuture}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	if s.IsZero() {
+		return ErrZero
+	}
+
+	if int64(s) < 0 {
+		return fmt.Errorf("%w: %d exceeds %d", ErrSignBit, s, int64(math.MaxInt64))
+	}
+
+	if ahead := -s.Age(); ahead > o.maxFuture {
+		return fmt.Errorf("%w: %v ahead, maximum is %v", ErrInFuture, ahead, o.maxFuture)
+	}
+
+	return nil
+}
@@ -0,0 +1,79 @@
+// MIT License
+
+// Copyright (c) 2022 Project-Sparrow
+// Copyright (c) 2023 Kelwing <kelwing@kelnet.org>
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package snowflake_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"wumpgo.dev/snowflake"
+)
+
+func TestValidate(t *testing.T) {
+	epoch := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	snowflake.Init(epoch, 0, 0)
+
+	now := epoch.Add(24 * time.Hour)
+	restore := snowflake.FreezeClock(now)
+	defer restore()
+
+	valid, _ := snowflake.Compose(now.Add(-time.Hour), 1, 1, 1)
+	nearFuture, _ := snowflake.Compose(now.Add(30*time.Second), 1, 1, 1)
+	farFuture, _ := snowflake.Compose(now.Add(time.Hour), 1, 1, 1)
+
+	// A time before the epoch cannot be composed, and an offset of
+	// one millisecond before it wraps around to set the sign bit.
+	if _, err := snowflake.Compose(epoch.Add(-time.Millisecond), 1, 1, 1); err == nil {
+		t.Error("Compose() before the epoch succeeded, want error")
+	}
+	offset := int64(-1)
+	preEpoch := snowflake.Snowflake(uint64(offset) << 22)
+
+	tests := []struct {
+		name string
+		s    snowflake.Snowflake
+		opts []snowflake.ValidateOption
+		want error
+	}{
+		{"valid", valid, nil, nil},
+		{"zero", 0, nil, snowflake.ErrZero},
+		{"sign bit", 1 << 63, nil, snowflake.ErrSignBit},
+		{"pre-epoch", preEpoch, nil, snowflake.ErrSignBit},
+		{"largest signed", 1<<63 - 1, []snowflake.ValidateOption{snowflake.WithMaxFuture(1 << 62)}, nil},
+		{"near future", nearFuture, nil, nil},
+		{"far future", farFuture, nil, snowflake.ErrInFuture},
+		{"far future allowed", farFuture, []snowflake.ValidateOption{snowflake.WithMaxFuture(2 * time.Hour)}, nil},
+		{"near future disallowed", nearFuture, []snowflake.ValidateOption{snowflake.WithMaxFuture(0)}, snowflake.ErrInFuture},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.s.Validate(tt.opts...)
+			if !errors.Is(err, tt.want) {
+				t.Errorf("Validate() = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,243 @@
+// MIT License
+
+// Copyright (c) 2022 Project-Sparrow
+// Copyright (c) 2023 Kelwing <kelwing@kelnet.org>
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package snowflake
+
+import (
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"net"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// ErrNoPhysicalInterface is returned by WorkerIDFromMAC when the host has
+// no interface with a hardware address that looks physical.
+var ErrNoPhysicalInterface = errors.New("no physical network interface")
+
+// interfaceAddrs, interfaces and hostname look up the host's interface
+// addresses, interfaces and name. They are replaced in tests.
+var (
+	interfaceAddrs = net.InterfaceAddrs
+	interfaces     = net.Interfaces
+	hostname       = os.Hostname
+)
+
+// virtualPrefixes are name prefixes of common virtual network devices.
+var virtualPrefixes = []string{"br-", "cni", "docker", "flannel", "lo", "tap", "tun", "veth", "virbr", "vmnet", "wg", "zt"}
+
+// WorkerIDFromIP derives a worker ID for DefaultLayout from the first
+// non-loopback unicast address of the host, as WorkerIDFromIPAddr does.
+// This suits deployments where every instance gets its own address,
+// such as pods in a cluster network.
+func WorkerIDFromIP() (uint8, error) {
+	addrs, err := interfaceAddrs()
+	if err != nil {
+		return 0, fmt.Errorf("listing interface addresses: %w", err)
+	}
+
+	for _, addr := range addrs {
+		var ip net.IP
+		switch a := addr.(type) {
+		case *net.IPNet:
+			ip = a.IP
+		case *net.IPAddr:
+			ip = a.IP
+		}
+
+		if ip.IsGlobalUnicast() {
+			return WorkerIDFromIPAddr(ip, workerBits)
+		}
+	}
+
+	return 0, fmt.Errorf("no non-loopback unicast address among %d interface addresses", len(addrs))
+}
+
+// WorkerIDFromIPAddr derives a worker ID of the given bit width from ip.
+// For IPv4 addresses it is the low bits of the address; for IPv6 addresses
+// it is the low bits of the 64-bit FNV-1a hash of the interface identifier,
+// the low 64 bits of the address.
+// Instances whose addresses share those bits get the same worker ID.
+func WorkerIDFromIPAddr(ip net.IP, bits uint8) (uint8, error) {
+	if err := checkIDBits("worker", bits); err != nil {
+		return 0, err
+	}
+
+	if ip4 := ip.To4(); ip4 != nil {
+		return ip4[3] & (1<<bits - 1), nil
+	}
+
+	if len(ip) != net.IPv6len {
+		return 0, fmt.Errorf("invalid IP address %v", ip)
+	}
+
+	return foldHash(hash(ip[8:]), bits), nil
+}
+
+// WorkerIDFromMAC derives a worker ID for DefaultLayout from the hardware
+// address of the host's first physical interface, in order of interface
+// index: the low bits of the 64-bit FNV-1a hash of the address.
+// Loopback interfaces, interfaces without a hardware address or with a
+// locally administered one, and interfaces named like common virtual
+// devices (docker0, veth…, br-…, virbr0 and so on) are skipped.
+func WorkerIDFromMAC() (uint8, error) {
+	return WorkerIDFromMACBits(workerBits)
+}
+
+// WorkerIDFromMACBits is like WorkerIDFromMAC but derives a worker ID
+// of the given bit width, for layouts with a wider worker field.
+func WorkerIDFromMACBits(bits uint8) (uint8, error) {
+	if err := checkIDBits("worker", bits); err != nil {
+		return 0, err
+	}
+
+	ifaces, err := interfaces()
+	if err != nil {
+		return 0, fmt.Errorf("listing interfaces: %w", err)
+	}
+
+	ifaces = slices.Clone(ifaces)
+	slices.SortFunc(ifaces, func(a, b net.Interface) int { return a.Index - b.Index })
+
+	for _, iface := range ifaces {
+		if physical(iface) {
+			return foldHash(hash(iface.HardwareAddr), bits), nil
+		}
+	}
+
+	return 0, fmt.Errorf("%w among %d interfaces", ErrNoPhysicalInterface, len(ifaces))
+}
+
+// physical reports whether iface looks like a physical network device.
+func physical(iface net.Interface) bool {
+	if iface.Flags&net.FlagLoopback != 0 || len(iface.HardwareAddr) == 0 {
+		return false
+	}
+
+	// Virtual devices usually get locally administered addresses.
+	if iface.HardwareAddr[0]&0x02 != 0 {
+		return false
+	}
+
+	for _, prefix := range virtualPrefixes {
+		if strings.HasPrefix(iface.Name, prefix) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// WorkerIDFromHostname derives a worker ID for DefaultLayout from the
+// host name, as WorkerIDFromString does.
+// This suits deployments with stable host names but changing addresses,
+// such as stateful sets.
+func WorkerIDFromHostname() (uint8, error) {
+	name, err := hostname()
+	if err != nil {
+		return 0, fmt.Errorf("looking up host name: %w", err)
+	}
+
+	return WorkerIDFromString(name, workerBits)
+}
+
+// WorkerIDFromString derives a worker ID of the given bit width from s:
+// the low bits of WorkerIDHash(s).
+// Different strings may get the same worker ID; compare their WorkerIDHash
+// values to tell a collision from a duplicate identifier.
+func WorkerIDFromString(s string, bits uint8) (uint8, error) {
+	if err := checkIDBits("worker", bits); err != nil {
+		return 0, err
+	}
+
+	return foldHash(WorkerIDHash(s), bits), nil
+}
+
+// WorkerIDHash returns the 64-bit FNV-1a hash of s that WorkerIDFromString
+// derives worker IDs from. It will not change between releases.
+func WorkerIDHash(s string) uint64 {
+	return hash([]byte(s))
+}
+
+// hash returns the 64-bit FNV-1a hash of b.
+func hash(b []byte) uint64 {
+	h := fnv.New64a()
+	h.Write(b)
+	return h.Sum64()
+}
+
+// foldHash returns the low bits of h.
+func foldHash(h uint64, bits uint8) uint8 {
+	return uint8(h) & (1<<bits - 1)
+}
+
+// WorkerIDFromPodName derives a worker ID of the given bit width from the
+// name of a StatefulSet pod, which ends in the pod's ordinal after the last
+// dash: ingest-7 and my-app-v2-13 get the worker IDs 7 and 13.
+// It returns an error if name has no ordinal or it does not fit in bits.
+func WorkerIDFromPodName(name string, bits uint8) (uint8, error) {
+	if err := checkIDBits("worker", bits); err != nil {
+		return 0, err
+	}
+
+	i := strings.LastIndexByte(name, '-')
+	if i < 0 {
+		return 0, fmt.Errorf("pod name %q has no ordinal", name)
+	}
+
+	ordinal, err := strconv.ParseUint(name[i+1:], 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("pod name %q has no ordinal", name)
+	}
+
+	if max := uint64(1)<<bits - 1; ordinal > max {
+		return 0, fmt.Errorf("ordinal %d of pod %q exceeds maximum worker ID %d", ordinal, name, max)
+	}
+
+	return uint8(ordinal), nil
+}
+
+// WorkerIDFromDownwardAPI derives a worker ID for DefaultLayout from the
+// pod name in the POD_NAME environment variable, as commonly set through
+// the Kubernetes downward API, or else HOSTNAME, which Kubernetes sets to
+// the pod name. It returns an error wrapping ErrEnvNotSet if neither is set.
+func WorkerIDFromDownwardAPI() (uint8, error) {
+	for _, key := range []string{"POD_NAME", "HOSTNAME"} {
+		if name := os.Getenv(key); name != "" {
+			return WorkerIDFromPodName(name, workerBits)
+		}
+	}
+
+	return 0, fmt.Errorf("%w: POD_NAME or HOSTNAME", ErrEnvNotSet)
+}
+
+// checkIDBits checks the width of a kind of ID, such as "worker" or "process".
+func checkIDBits(kind string, bits uint8) error {
+	if bits == 0 || bits > 8 {
+		return fmt.Errorf("%s ID width %d is not between 1 and 8 bits", kind, bits)
+	}
+	return nil
+}
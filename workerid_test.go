@@ -0,0 +1,288 @@
+// MIT License
+
+// Copyright (c) 2022 Project-Sparrow
+// Copyright (c) 2023 Kelwing <kelwing@kelnet.org>
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package snowflake_test
+
+import (
+	"errors"
+	"net"
+	"testing"
+
+	"wumpgo.dev/snowflake"
+)
+
+func ipNet(s string) net.Addr {
+	ip, n, err := net.ParseCIDR(s)
+	if err != nil {
+		panic(err)
+	}
+	n.IP = ip
+	return n
+}
+
+func TestWorkerIDFromIP(t *testing.T) {
+	for _, tt := range []struct {
+		name  string
+		addrs []net.Addr
+		want  uint8
+	}{
+		{"ipv4", []net.Addr{ipNet("10.1.2.37/24")}, 5},
+		{"skips loopback", []net.Addr{ipNet("127.0.0.1/8"), ipNet("::1/128"), ipNet("10.1.2.3/24")}, 3},
+		{"skips link-local", []net.Addr{ipNet("fe80::1/64"), ipNet("169.254.0.9/16"), ipNet("192.168.0.66/24")}, 2},
+		{"ipv6", []net.Addr{ipNet("2001:db8::1/64")}, 18},
+		{"ip addr", []net.Addr{&net.IPAddr{IP: net.ParseIP("fd00::a:b:c:d")}}, 21},
+	} {
+		restore := snowflake.SetInterfaceAddrs(tt.addrs, nil)
+		got, err := snowflake.WorkerIDFromIP()
+		restore()
+
+		if err != nil || got != tt.want {
+			t.Errorf("%s: WorkerIDFromIP() = %d, %v, want %d", tt.name, got, err, tt.want)
+		}
+	}
+}
+
+func TestWorkerIDFromIPErrors(t *testing.T) {
+	defer snowflake.SetInterfaceAddrs([]net.Addr{ipNet("127.0.0.1/8"), ipNet("fe80::1/64")}, nil)()
+	if _, err := snowflake.WorkerIDFromIP(); err == nil {
+		t.Error("WorkerIDFromIP() with only loopback and link-local addresses succeeded, want error")
+	}
+
+	errList := errors.New("no interfaces")
+	defer snowflake.SetInterfaceAddrs(nil, errList)()
+	if _, err := snowflake.WorkerIDFromIP(); !errors.Is(err, errList) {
+		t.Errorf("WorkerIDFromIP() = %v, want %v", err, errList)
+	}
+}
+
+func TestWorkerIDFromIPAddr(t *testing.T) {
+	for _, tt := range []struct {
+		ip   string
+		bits uint8
+		want uint8
+	}{
+		{"10.0.0.255", 5, 31},
+		{"10.0.0.255", 8, 255},
+		{"10.0.0.6", 1, 0},
+		{"::ffff:10.0.0.7", 5, 7},
+		{"2001:db8::1", 8, 18},
+		{"fd00::a:b:c:d", 3, 5},
+	} {
+		got, err := snowflake.WorkerIDFromIPAddr(net.ParseIP(tt.ip), tt.bits)
+		if err != nil || got != tt.want {
+			t.Errorf("WorkerIDFromIPAddr(%s, %d) = %d, %v, want %d", tt.ip, tt.bits, got, err, tt.want)
+		}
+	}
+
+	for _, bits := range []uint8{0, 9} {
+		if _, err := snowflake.WorkerIDFromIPAddr(net.ParseIP("10.0.0.1"), bits); err == nil {
+			t.Errorf("WorkerIDFromIPAddr with %d bits succeeded, want error", bits)
+		}
+	}
+
+	if _, err := snowflake.WorkerIDFromIPAddr(net.IP{1, 2}, 5); err == nil {
+		t.Error("WorkerIDFromIPAddr with an invalid address succeeded, want error")
+	}
+}
+
+// These values are pinned: changing them would reassign the worker IDs
+// of every deployment deriving them from host names.
+var workerIDHashTests = []struct {
+	s    string
+	hash uint64
+	id5  uint8
+	id8  uint8
+}{
+	{"", 0xcbf29ce484222325, 5, 37},
+	{"a", 0xaf63dc4c8601ec8c, 12, 140},
+	{"statefulset-0", 0xcfb31e2ac3a94612, 18, 18},
+	{"statefulset-3", 0xcfb31d2ac3a9445f, 31, 95},
+	{"worker-a7", 0x49a983b3f2ee903e, 30, 62},
+}
+
+func TestWorkerIDFromString(t *testing.T) {
+	for _, tt := range workerIDHashTests {
+		if got := snowflake.WorkerIDHash(tt.s); got != tt.hash {
+			t.Errorf("WorkerIDHash(%q) = %#x, want %#x", tt.s, got, tt.hash)
+		}
+
+		if got, err := snowflake.WorkerIDFromString(tt.s, 5); err != nil || got != tt.id5 {
+			t.Errorf("WorkerIDFromString(%q, 5) = %d, %v, want %d", tt.s, got, err, tt.id5)
+		}
+
+		if got, err := snowflake.WorkerIDFromString(tt.s, 8); err != nil || got != tt.id8 {
+			t.Errorf("WorkerIDFromString(%q, 8) = %d, %v, want %d", tt.s, got, err, tt.id8)
+		}
+	}
+
+	for _, bits := range []uint8{0, 9} {
+		if _, err := snowflake.WorkerIDFromString("a", bits); err == nil {
+			t.Errorf("WorkerIDFromString with %d bits succeeded, want error", bits)
+		}
+	}
+}
+
+func TestWorkerIDFromHostname(t *testing.T) {
+	for _, tt := range workerIDHashTests {
+		restore := snowflake.SetHostname(tt.s, nil)
+		got, err := snowflake.WorkerIDFromHostname()
+		restore()
+
+		if err != nil || got != tt.id5 {
+			t.Errorf("WorkerIDFromHostname() for %q = %d, %v, want %d", tt.s, got, err, tt.id5)
+		}
+	}
+
+	errName := errors.New("no host name")
+	defer snowflake.SetHostname("", errName)()
+	if _, err := snowflake.WorkerIDFromHostname(); !errors.Is(err, errName) {
+		t.Errorf("WorkerIDFromHostname() = %v, want %v", err, errName)
+	}
+}
+
+func iface(index int, name, mac string, flags net.Flags) net.Interface {
+	addr, err := net.ParseMAC(mac)
+	if err != nil {
+		panic(err)
+	}
+	return net.Interface{Index: index, Name: name, HardwareAddr: addr, Flags: flags}
+}
+
+func TestWorkerIDFromMAC(t *testing.T) {
+	eth0 := iface(2, "eth0", "00:1a:2b:3c:4d:5e", net.FlagUp)
+	eth1 := iface(3, "eth1", "f0:de:f1:00:00:01", net.FlagUp)
+
+	for _, tt := range []struct {
+		name   string
+		ifaces []net.Interface
+		want   uint8
+	}{
+		{"single", []net.Interface{eth0}, 11},
+		{"lowest index", []net.Interface{eth1, eth0}, 11},
+		{"skips virtual", []net.Interface{
+			{Index: 1, Name: "lo", Flags: net.FlagLoopback},
+			iface(0, "docker0", "00:42:ac:11:00:01", net.FlagUp),
+			iface(1, "veth12ab", "00:42:ac:11:00:02", net.FlagUp),
+			iface(1, "ens3", "02:42:ac:11:00:02", net.FlagUp),
+			eth1,
+		}, 5},
+	} {
+		restore := snowflake.SetInterfaces(tt.ifaces, nil)
+		got, err := snowflake.WorkerIDFromMAC()
+		restore()
+
+		if err != nil || got != tt.want {
+			t.Errorf("%s: WorkerIDFromMAC() = %d, %v, want %d", tt.name, got, err, tt.want)
+		}
+	}
+}
+
+func TestWorkerIDFromMACBits(t *testing.T) {
+	defer snowflake.SetInterfaces([]net.Interface{iface(2, "eth0", "00:1a:2b:3c:4d:5e", net.FlagUp)}, nil)()
+
+	for _, tt := range []struct {
+		bits uint8
+		want uint8
+	}{
+		{5, 11},
+		{8, 139},
+	} {
+		if got, err := snowflake.WorkerIDFromMACBits(tt.bits); err != nil || got != tt.want {
+			t.Errorf("WorkerIDFromMACBits(%d) = %d, %v, want %d", tt.bits, got, err, tt.want)
+		}
+	}
+
+	for _, bits := range []uint8{0, 9} {
+		if _, err := snowflake.WorkerIDFromMACBits(bits); err == nil {
+			t.Errorf("WorkerIDFromMACBits(%d) succeeded, want error", bits)
+		}
+	}
+}
+
+func TestWorkerIDFromMACErrors(t *testing.T) {
+	defer snowflake.SetInterfaces([]net.Interface{
+		{Index: 1, Name: "lo", Flags: net.FlagLoopback},
+		{Index: 2, Name: "wg0"},
+		iface(3, "virbr0", "52:54:00:00:00:01", net.FlagUp),
+	}, nil)()
+	if _, err := snowflake.WorkerIDFromMAC(); !errors.Is(err, snowflake.ErrNoPhysicalInterface) {
+		t.Errorf("WorkerIDFromMAC() = %v, want ErrNoPhysicalInterface", err)
+	}
+
+	errList := errors.New("no interfaces")
+	defer snowflake.SetInterfaces(nil, errList)()
+	if _, err := snowflake.WorkerIDFromMAC(); !errors.Is(err, errList) {
+		t.Errorf("WorkerIDFromMAC() = %v, want %v", err, errList)
+	}
+}
+
+func TestWorkerIDFromPodName(t *testing.T) {
+	for _, tt := range []struct {
+		name string
+		bits uint8
+		want uint8
+		err  bool
+	}{
+		{"ingest-0", 5, 0, false},
+		{"ingest-7", 5, 7, false},
+		{"ingest-31", 5, 31, false},
+		{"my-app-v2-13", 5, 13, false},
+		{"statefulset-255", 8, 255, false},
+		{"ingest-32", 5, 0, true},
+		{"ingest-99999999999999999999", 8, 0, true},
+		{"ingest", 5, 0, true},
+		{"ingest-", 5, 0, true},
+		{"my-app-v2", 5, 0, true},
+		{"ingest-+1", 5, 0, true},
+		{"", 5, 0, true},
+		{"ingest-3", 0, 0, true},
+	} {
+		got, err := snowflake.WorkerIDFromPodName(tt.name, tt.bits)
+		if (err != nil) != tt.err || got != tt.want {
+			t.Errorf("WorkerIDFromPodName(%q, %d) = %d, %v, want %d and error %v", tt.name, tt.bits, got, err, tt.want, tt.err)
+		}
+	}
+}
+
+func TestWorkerIDFromDownwardAPI(t *testing.T) {
+	t.Setenv("POD_NAME", "")
+	t.Setenv("HOSTNAME", "")
+	if _, err := snowflake.WorkerIDFromDownwardAPI(); !errors.Is(err, snowflake.ErrEnvNotSet) {
+		t.Errorf("WorkerIDFromDownwardAPI() unset = %v, want ErrEnvNotSet", err)
+	}
+
+	t.Setenv("HOSTNAME", "ingest-4")
+	if got, err := snowflake.WorkerIDFromDownwardAPI(); err != nil || got != 4 {
+		t.Errorf("WorkerIDFromDownwardAPI() from HOSTNAME = %d, %v, want 4", got, err)
+	}
+
+	t.Setenv("POD_NAME", "my-app-v2-9")
+	if got, err := snowflake.WorkerIDFromDownwardAPI(); err != nil || got != 9 {
+		t.Errorf("WorkerIDFromDownwardAPI() from POD_NAME = %d, %v, want 9", got, err)
+	}
+
+	t.Setenv("POD_NAME", "ingest-40")
+	if _, err := snowflake.WorkerIDFromDownwardAPI(); err == nil {
+		t.Error("WorkerIDFromDownwardAPI() with ordinal 40 succeeded, want error")
+	}
+}